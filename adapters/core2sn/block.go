@@ -0,0 +1,104 @@
+package core2sn
+
+import (
+	"slices"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/starknet"
+)
+
+// AdaptBlock adapts a block to the feeder format. The block status is left for the caller to set, and the
+// signature is nil if the block has none.
+func AdaptBlock(block *core.Block) (*starknet.Block, *starknet.Signature) {
+	response := &starknet.Block{
+		Hash:             block.Hash,
+		ParentHash:       block.ParentHash,
+		Number:           block.Number,
+		StateRoot:        block.GlobalStateRoot,
+		Transactions:     make([]*starknet.Transaction, len(block.Transactions)),
+		Timestamp:        block.Timestamp,
+		Version:          block.ProtocolVersion,
+		Receipts:         make([]*starknet.TransactionReceipt, len(block.Receipts)),
+		SequencerAddress: block.SequencerAddress,
+		L2GasPrice:       (*starknet.GasPrice)(block.L2GasPrice),
+		L1DAMode:         starknet.L1DAMode(block.L1DAMode),
+		L1DataGasPrice:   (*starknet.GasPrice)(block.L1DataGasPrice),
+	}
+	if block.L1GasPriceETH != nil || block.L1GasPriceSTRK != nil {
+		response.L1GasPrice = &starknet.GasPrice{
+			PriceInWei: block.L1GasPriceETH,
+			PriceInFri: block.L1GasPriceSTRK,
+		}
+	}
+
+	for i, txn := range block.Transactions {
+		response.Transactions[i] = AdaptTransaction(txn)
+	}
+	for i, receipt := range block.Receipts {
+		response.Receipts[i] = AdaptTransactionReceipt(receipt, uint64(i))
+	}
+
+	var sig *starknet.Signature
+	if len(block.Signatures) > 0 {
+		sig = &starknet.Signature{
+			BlockNumber: block.Number,
+			Signature:   block.Signatures[0],
+		}
+		sig.SignatureInput.BlockHash = block.Hash
+	}
+	return response, sig
+}
+
+// AdaptStateUpdate adapts a state update to the feeder format, with the diffs ordered by address, key and
+// class hash.
+func AdaptStateUpdate(stateUpdate *core.StateUpdate) *starknet.StateUpdate {
+	response := &starknet.StateUpdate{
+		BlockHash: stateUpdate.BlockHash,
+		NewRoot:   stateUpdate.NewRoot,
+		OldRoot:   stateUpdate.OldRoot,
+	}
+
+	diff := stateUpdate.StateDiff
+	response.StateDiff.StorageDiffs = make(map[string][]starknet.StorageDiff, len(diff.StorageDiffs))
+	for addr, diffs := range diff.StorageDiffs {
+		storageDiffs := make([]starknet.StorageDiff, 0, len(diffs))
+		for key, value := range diffs {
+			storageDiffs = append(storageDiffs, starknet.StorageDiff{Key: &key, Value: value})
+		}
+		slices.SortFunc(storageDiffs, func(a, b starknet.StorageDiff) int { return a.Key.Cmp(b.Key) })
+		response.StateDiff.StorageDiffs[addr.String()] = storageDiffs
+	}
+
+	response.StateDiff.Nonces = make(map[string]*felt.Felt, len(diff.Nonces))
+	for addr, nonce := range diff.Nonces {
+		response.StateDiff.Nonces[addr.String()] = nonce
+	}
+
+	response.StateDiff.DeployedContracts = adaptDeployedContracts(diff.DeployedContracts)
+	response.StateDiff.OldDeclaredContracts = diff.DeclaredV0Classes
+	response.StateDiff.DeclaredClasses = make([]starknet.DeclaredClass, 0, len(diff.DeclaredV1Classes))
+	for classHash, compiledClassHash := range diff.DeclaredV1Classes {
+		response.StateDiff.DeclaredClasses = append(response.StateDiff.DeclaredClasses, starknet.DeclaredClass{
+			ClassHash:         &classHash,
+			CompiledClassHash: compiledClassHash,
+		})
+	}
+	slices.SortFunc(response.StateDiff.DeclaredClasses, func(a, b starknet.DeclaredClass) int {
+		return a.ClassHash.Cmp(b.ClassHash)
+	})
+	response.StateDiff.ReplacedClasses = adaptDeployedContracts(diff.ReplacedClasses)
+	return response
+}
+
+func adaptDeployedContracts(contracts map[felt.Felt]*felt.Felt) []starknet.DeployedContract {
+	deployedContracts := make([]starknet.DeployedContract, 0, len(contracts))
+	for addr, classHash := range contracts {
+		deployedContracts = append(deployedContracts, starknet.DeployedContract{
+			Address:   &addr,
+			ClassHash: classHash,
+		})
+	}
+	slices.SortFunc(deployedContracts, func(a, b starknet.DeployedContract) int { return a.Address.Cmp(b.Address) })
+	return deployedContracts
+}
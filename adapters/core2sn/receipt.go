@@ -0,0 +1,70 @@
+package core2sn
+
+import (
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/starknet"
+	"github.com/NethermindEth/juno/utils"
+)
+
+func AdaptTransactionReceipt(receipt *core.TransactionReceipt, index uint64) *starknet.TransactionReceipt {
+	executionStatus := starknet.Succeeded
+	if receipt.Reverted {
+		executionStatus = starknet.Reverted
+	}
+
+	return &starknet.TransactionReceipt{
+		ActualFee:          receipt.Fee,
+		Events:             utils.Map(receipt.Events, adaptEvent),
+		ExecutionStatus:    executionStatus,
+		ExecutionResources: adaptExecutionResources(receipt.ExecutionResources),
+		L1ToL2Message:      adaptL1ToL2Message(receipt.L1ToL2Message),
+		L2ToL1Message:      utils.Map(receipt.L2ToL1Message, adaptL2ToL1Message),
+		TransactionHash:    receipt.TransactionHash,
+		TransactionIndex:   index,
+		RevertError:        receipt.RevertReason,
+	}
+}
+
+func adaptEvent(event *core.Event) *starknet.Event {
+	return &starknet.Event{
+		From: event.From,
+		Data: event.Data,
+		Keys: event.Keys,
+	}
+}
+
+func adaptExecutionResources(resources *core.ExecutionResources) *starknet.ExecutionResources {
+	if resources == nil {
+		return nil
+	}
+
+	return &starknet.ExecutionResources{
+		Steps:                  resources.Steps,
+		BuiltinInstanceCounter: starknet.BuiltinInstanceCounter(resources.BuiltinInstanceCounter),
+		MemoryHoles:            resources.MemoryHoles,
+		DataAvailability:       (*starknet.DataAvailability)(resources.DataAvailability),
+		TotalGasConsumed:       (*starknet.GasConsumed)(resources.TotalGasConsumed),
+	}
+}
+
+func adaptL1ToL2Message(msg *core.L1ToL2Message) *starknet.L1ToL2Message {
+	if msg == nil {
+		return nil
+	}
+
+	return &starknet.L1ToL2Message{
+		From:     msg.From.Hex(),
+		Payload:  msg.Payload,
+		Selector: msg.Selector,
+		To:       msg.To,
+		Nonce:    msg.Nonce,
+	}
+}
+
+func adaptL2ToL1Message(msg *core.L2ToL1Message) *starknet.L2ToL1Message {
+	return &starknet.L2ToL1Message{
+		From:    msg.From,
+		Payload: msg.Payload,
+		To:      msg.To.Hex(),
+	}
+}
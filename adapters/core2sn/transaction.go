@@ -0,0 +1,103 @@
+package core2sn
+
+import (
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/starknet"
+	"github.com/NethermindEth/juno/utils"
+)
+
+// AdaptTransaction adapts a transaction to the feeder format. The version 3 fields are only set for
+// transactions with resource bounds.
+func AdaptTransaction(transaction core.Transaction) *starknet.Transaction {
+	switch t := transaction.(type) {
+	case *core.DeclareTransaction:
+		response := &starknet.Transaction{
+			Hash:              t.TransactionHash,
+			Version:           t.Version.AsFelt(),
+			ClassHash:         t.ClassHash,
+			Type:              starknet.TxnDeclare,
+			SenderAddress:     t.SenderAddress,
+			MaxFee:            t.MaxFee,
+			Signature:         utils.HeapPtr(utils.NonNilSlice(t.TransactionSignature)),
+			Nonce:             t.Nonce,
+			CompiledClassHash: t.CompiledClassHash,
+		}
+		if t.ResourceBounds != nil {
+			setV3Fields(response, t.ResourceBounds, t.Tip, t.PaymasterData, t.NonceDAMode, t.FeeDAMode)
+			response.AccountDeploymentData = &t.AccountDeploymentData
+		}
+		return response
+	case *core.DeployTransaction:
+		return adaptDeployTransaction(t)
+	case *core.DeployAccountTransaction:
+		response := adaptDeployTransaction(&t.DeployTransaction)
+		response.Type = starknet.TxnDeployAccount
+		response.MaxFee = t.MaxFee
+		response.Signature = utils.HeapPtr(utils.NonNilSlice(t.TransactionSignature))
+		response.Nonce = t.Nonce
+		if t.ResourceBounds != nil {
+			setV3Fields(response, t.ResourceBounds, t.Tip, t.PaymasterData, t.NonceDAMode, t.FeeDAMode)
+		}
+		return response
+	case *core.InvokeTransaction:
+		response := &starknet.Transaction{
+			Hash:               t.TransactionHash,
+			Version:            t.Version.AsFelt(),
+			ContractAddress:    t.ContractAddress,
+			Type:               starknet.TxnInvoke,
+			SenderAddress:      t.SenderAddress,
+			MaxFee:             t.MaxFee,
+			Signature:          utils.HeapPtr(utils.NonNilSlice(t.TransactionSignature)),
+			CallData:           utils.HeapPtr(utils.NonNilSlice(t.CallData)),
+			EntryPointSelector: t.EntryPointSelector,
+			Nonce:              t.Nonce,
+		}
+		if t.ResourceBounds != nil {
+			setV3Fields(response, t.ResourceBounds, t.Tip, t.PaymasterData, t.NonceDAMode, t.FeeDAMode)
+			response.AccountDeploymentData = &t.AccountDeploymentData
+		}
+		return response
+	case *core.L1HandlerTransaction:
+		return &starknet.Transaction{
+			Hash:               t.TransactionHash,
+			Version:            t.Version.AsFelt(),
+			ContractAddress:    t.ContractAddress,
+			Type:               starknet.TxnL1Handler,
+			CallData:           utils.HeapPtr(utils.NonNilSlice(t.CallData)),
+			EntryPointSelector: t.EntryPointSelector,
+			Nonce:              t.Nonce,
+		}
+	default:
+		return nil
+	}
+}
+
+func adaptDeployTransaction(t *core.DeployTransaction) *starknet.Transaction {
+	return &starknet.Transaction{
+		Hash:                t.TransactionHash,
+		Version:             t.Version.AsFelt(),
+		ContractAddress:     t.ContractAddress,
+		ContractAddressSalt: t.ContractAddressSalt,
+		ClassHash:           t.ClassHash,
+		ConstructorCallData: utils.HeapPtr(utils.NonNilSlice(t.ConstructorCallData)),
+		Type:                starknet.TxnDeploy,
+	}
+}
+
+func setV3Fields(response *starknet.Transaction, resourceBounds map[core.Resource]core.ResourceBounds, tip uint64,
+	paymasterData []*felt.Felt, nonceDAMode, feeDAMode core.DataAvailabilityMode,
+) {
+	bounds := make(map[starknet.Resource]starknet.ResourceBounds, len(resourceBounds))
+	for resource, bound := range resourceBounds {
+		bounds[starknet.Resource(resource)] = starknet.ResourceBounds{
+			MaxAmount:       new(felt.Felt).SetUint64(bound.MaxAmount),
+			MaxPricePerUnit: bound.MaxPricePerUnit,
+		}
+	}
+	response.ResourceBounds = &bounds
+	response.Tip = new(felt.Felt).SetUint64(tip)
+	response.PaymasterData = &paymasterData
+	response.NonceDAMode = utils.HeapPtr(starknet.DataAvailabilityMode(nonceDAMode))
+	response.FeeDAMode = utils.HeapPtr(starknet.DataAvailabilityMode(feeDAMode))
+}
@@ -0,0 +1,101 @@
+package rpc2core
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/NethermindEth/juno/adapters/sn2core"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	rpcv8 "github.com/NethermindEth/juno/rpc/v8"
+	"github.com/NethermindEth/juno/starknet"
+	"github.com/NethermindEth/juno/starknet/compiler"
+	"github.com/NethermindEth/juno/utils"
+)
+
+// AdaptDeclaredClass adapts a class definition in the RPC format, compiling Sierra classes.
+func AdaptDeclaredClass(declaredClass json.RawMessage) (core.Class, error) {
+	var feederClass starknet.ClassDefinition
+	err := json.Unmarshal(declaredClass, &feederClass)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case feederClass.V1 != nil:
+		compiledClass, cErr := compiler.Compile(feederClass.V1)
+		if cErr != nil {
+			return nil, cErr
+		}
+		return sn2core.AdaptCairo1Class(feederClass.V1, compiledClass)
+	case feederClass.V0 != nil:
+		program := feederClass.V0.Program
+
+		// strip the quotes
+		if len(program) < 2 {
+			return nil, errors.New("invalid program")
+		}
+		base64Program := string(program[1 : len(program)-1])
+
+		feederClass.V0.Program, err = utils.Gzip64Decode(base64Program)
+		if err != nil {
+			return nil, err
+		}
+
+		return sn2core.AdaptCairo0Class(feederClass.V0)
+	default:
+		return nil, errors.New("empty class")
+	}
+}
+
+// AdaptTransaction adapts a transaction in the RPC format by way of the feeder format.
+func AdaptTransaction(txn *rpcv8.Transaction) (core.Transaction, error) {
+	feederTxn := &starknet.Transaction{
+		Hash:                  txn.Hash,
+		Version:               txn.Version,
+		ContractAddress:       txn.ContractAddress,
+		ContractAddressSalt:   txn.ContractAddressSalt,
+		ClassHash:             txn.ClassHash,
+		ConstructorCallData:   txn.ConstructorCallData,
+		Type:                  starknet.TransactionType(txn.Type),
+		SenderAddress:         txn.SenderAddress,
+		MaxFee:                txn.MaxFee,
+		Signature:             txn.Signature,
+		CallData:              txn.CallData,
+		EntryPointSelector:    txn.EntryPointSelector,
+		Nonce:                 txn.Nonce,
+		CompiledClassHash:     txn.CompiledClassHash,
+		ResourceBounds:        adaptResourceBounds(txn.ResourceBounds),
+		Tip:                   txn.Tip,
+		NonceDAMode:           adaptDAMode(txn.NonceDAMode),
+		FeeDAMode:             adaptDAMode(txn.FeeDAMode),
+		AccountDeploymentData: txn.AccountDeploymentData,
+		PaymasterData:         txn.PaymasterData,
+	}
+	// Version 0 declare transactions have a zero nonce in the feeder but none in the RPC format.
+	if txn.Type == rpcv8.TxnDeclare && txn.Version.IsZero() && txn.Nonce == nil {
+		feederTxn.Nonce = &felt.Zero
+	}
+	return sn2core.AdaptTransaction(feederTxn)
+}
+
+func adaptResourceBounds(rb *map[rpcv8.Resource]rpcv8.ResourceBounds) *map[starknet.Resource]starknet.ResourceBounds { //nolint:gocritic
+	if rb == nil {
+		return nil
+	}
+	feederResourceBounds := make(map[starknet.Resource]starknet.ResourceBounds)
+	for resource, bounds := range *rb {
+		feederResourceBounds[starknet.Resource(resource)] = starknet.ResourceBounds{
+			MaxAmount:       bounds.MaxAmount,
+			MaxPricePerUnit: bounds.MaxPricePerUnit,
+		}
+	}
+	return &feederResourceBounds
+}
+
+func adaptDAMode(mode *rpcv8.DataAvailabilityMode) *starknet.DataAvailabilityMode {
+	if mode == nil {
+		return nil
+	}
+	return utils.HeapPtr(starknet.DataAvailabilityMode(*mode))
+}
@@ -0,0 +1,90 @@
+package rpc2core_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/adapters/rpc2core"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	rpcv8 "github.com/NethermindEth/juno/rpc/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptDeclaredClassErrors(t *testing.T) {
+	tests := map[string]struct {
+		class json.RawMessage
+		err   string
+	}{
+		"not a class": {
+			class: json.RawMessage(`[]`),
+			err:   "json: cannot unmarshal array into Go value of type map[string]interface {}",
+		},
+		"missing program": {
+			class: json.RawMessage(`{"entry_points_by_type": {}}`),
+			err:   "invalid program",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := rpc2core.AdaptDeclaredClass(test.class)
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestAdaptTransaction(t *testing.T) {
+	t.Run("version 0 declare has a zero nonce", func(t *testing.T) {
+		txn, err := rpc2core.AdaptTransaction(&rpcv8.Transaction{
+			Hash:          new(felt.Felt).SetUint64(1),
+			Type:          rpcv8.TxnDeclare,
+			Version:       new(felt.Felt),
+			MaxFee:        new(felt.Felt).SetUint64(2),
+			ClassHash:     new(felt.Felt).SetUint64(3),
+			SenderAddress: new(felt.Felt).SetUint64(4),
+			Signature:     &[]*felt.Felt{},
+		})
+		require.NoError(t, err)
+
+		declare, ok := txn.(*core.DeclareTransaction)
+		require.True(t, ok)
+		assert.Equal(t, &felt.Zero, declare.Nonce)
+		assert.Equal(t, new(felt.Felt).SetUint64(3), declare.ClassHash)
+	})
+
+	t.Run("resource bounds and data availability modes", func(t *testing.T) {
+		l2DAMode := rpcv8.DAModeL2
+		txn, err := rpc2core.AdaptTransaction(&rpcv8.Transaction{
+			Hash:          new(felt.Felt).SetUint64(1),
+			Type:          rpcv8.TxnInvoke,
+			Version:       new(felt.Felt).SetUint64(3),
+			Nonce:         new(felt.Felt).SetUint64(5),
+			SenderAddress: new(felt.Felt).SetUint64(4),
+			Signature:     &[]*felt.Felt{},
+			CallData:      &[]*felt.Felt{},
+			ResourceBounds: &map[rpcv8.Resource]rpcv8.ResourceBounds{
+				rpcv8.ResourceL1Gas: {
+					MaxAmount:       new(felt.Felt).SetUint64(6),
+					MaxPricePerUnit: new(felt.Felt).SetUint64(7),
+				},
+			},
+			Tip:                   new(felt.Felt),
+			PaymasterData:         &[]*felt.Felt{},
+			AccountDeploymentData: &[]*felt.Felt{},
+			NonceDAMode:           &l2DAMode,
+			FeeDAMode:             &l2DAMode,
+		})
+		require.NoError(t, err)
+
+		invoke, ok := txn.(*core.InvokeTransaction)
+		require.True(t, ok)
+		assert.Equal(t, core.ResourceBounds{
+			MaxAmount:       6,
+			MaxPricePerUnit: new(felt.Felt).SetUint64(7),
+		}, invoke.ResourceBounds[core.ResourceL1Gas])
+		assert.Equal(t, core.DAModeL2, invoke.NonceDAMode)
+		assert.Equal(t, core.DAModeL2, invoke.FeeDAMode)
+	})
+}
@@ -52,7 +52,7 @@ func AdaptStateDiff(fromStateDiff *vm.StateDiff) core.StateDiff {
 		StorageDiffs:      make(map[felt.Felt]map[felt.Felt]*felt.Felt, len(fromStateDiff.StorageDiffs)),
 		Nonces:            make(map[felt.Felt]*felt.Felt, len(fromStateDiff.Nonces)),
 		DeployedContracts: make(map[felt.Felt]*felt.Felt, len(fromStateDiff.DeployedContracts)),
-		DeclaredV0Classes: make([]*felt.Felt, len(fromStateDiff.DeprecatedDeclaredClasses)),
+		DeclaredV0Classes: make([]*felt.Felt, 0, len(fromStateDiff.DeprecatedDeclaredClasses)),
 		DeclaredV1Classes: make(map[felt.Felt]*felt.Felt, len(fromStateDiff.DeclaredClasses)),
 		ReplacedClasses:   make(map[felt.Felt]*felt.Felt, len(fromStateDiff.ReplacedClasses)),
 	}
@@ -76,6 +76,11 @@ func AdaptStateDiff(fromStateDiff *vm.StateDiff) core.StateDiff {
 		toStateDiff.DeployedContracts[dc.Address] = &ch
 	}
 
+	for _, classHash := range fromStateDiff.DeprecatedDeclaredClasses {
+		ch := *classHash
+		toStateDiff.DeclaredV0Classes = append(toStateDiff.DeclaredV0Classes, &ch)
+	}
+
 	for _, dc := range fromStateDiff.DeclaredClasses {
 		cch := dc.CompiledClassHash
 		toStateDiff.DeclaredV1Classes[dc.ClassHash] = &cch
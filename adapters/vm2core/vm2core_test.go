@@ -68,3 +68,33 @@ func TestAdaptOrderedMessagesToL1(t *testing.T) {
 		vm2core.AdaptOrderedMessageToL1(messages[0]),
 	}, vm2core.AdaptOrderedMessagesToL1(messages))
 }
+
+func TestAdaptStateDiff(t *testing.T) {
+	require.Equal(t, core.StateDiff{}, vm2core.AdaptStateDiff(nil))
+
+	address := new(felt.Felt).SetUint64(1)
+	key := new(felt.Felt).SetUint64(2)
+	value := new(felt.Felt).SetUint64(3)
+	cairo0ClassHash := new(felt.Felt).SetUint64(4)
+	classHash := new(felt.Felt).SetUint64(5)
+	compiledClassHash := new(felt.Felt).SetUint64(6)
+
+	require.Equal(t, core.StateDiff{
+		StorageDiffs:      map[felt.Felt]map[felt.Felt]*felt.Felt{*address: {*key: value}},
+		Nonces:            map[felt.Felt]*felt.Felt{*address: value},
+		DeployedContracts: map[felt.Felt]*felt.Felt{*address: classHash},
+		DeclaredV0Classes: []*felt.Felt{cairo0ClassHash},
+		DeclaredV1Classes: map[felt.Felt]*felt.Felt{*classHash: compiledClassHash},
+		ReplacedClasses:   map[felt.Felt]*felt.Felt{*address: classHash},
+	}, vm2core.AdaptStateDiff(&vm.StateDiff{
+		StorageDiffs: []vm.StorageDiff{{
+			Address:        *address,
+			StorageEntries: []vm.Entry{{Key: *key, Value: *value}},
+		}},
+		Nonces:                    []vm.Nonce{{ContractAddress: *address, Nonce: *value}},
+		DeployedContracts:         []vm.DeployedContract{{Address: *address, ClassHash: *classHash}},
+		DeprecatedDeclaredClasses: []*felt.Felt{cairo0ClassHash},
+		DeclaredClasses:           []vm.DeclaredClass{{ClassHash: *classHash, CompiledClassHash: *compiledClassHash}},
+		ReplacedClasses:           []vm.ReplacedClass{{ContractAddress: *address, ClassHash: *classHash}},
+	}))
+}
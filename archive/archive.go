@@ -0,0 +1,139 @@
+// Package archive reads and writes blocks, together with their state updates and the classes they declare, as
+// JSON records in the format of the feeder gateway. Archives are used to seed a database without access to the
+// gateway, for example in air-gapped environments or for reproducible test fixtures.
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/NethermindEth/juno/adapters/core2sn"
+	"github.com/NethermindEth/juno/adapters/sn2core"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/starknet"
+)
+
+// Class is the definition of a class declared in an archived block, with the compiled class for Sierra classes.
+type Class struct {
+	ClassHash     *felt.Felt                `json:"class_hash"`
+	Definition    *starknet.ClassDefinition `json:"definition"`
+	CompiledClass *starknet.CompiledClass   `json:"compiled_class,omitempty"`
+}
+
+// Record is an archived block. It is the response of the feeder "get_state_update" endpoint with the block
+// included, extended with the block signature and the classes declared in the block.
+type Record struct {
+	starknet.StateUpdateWithBlock
+	Signature *starknet.Signature `json:"signature,omitempty"`
+	Classes   []Class             `json:"classes,omitempty"`
+}
+
+// Writer writes records to an archive, one JSON object per line.
+type Writer struct {
+	encoder *json.Encoder
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{encoder: json.NewEncoder(w)}
+}
+
+// Write appends a block, its state update and the classes declared in it to the archive.
+func (w *Writer) Write(stateUpdate *core.StateUpdate, block *core.Block, classes map[felt.Felt]core.Class) error {
+	response, sig := core2sn.AdaptBlock(block)
+	response.Status = "ACCEPTED_ON_L2"
+
+	record := Record{
+		StateUpdateWithBlock: starknet.StateUpdateWithBlock{
+			Block:       response,
+			StateUpdate: core2sn.AdaptStateUpdate(stateUpdate),
+		},
+		Signature: sig,
+		Classes:   make([]Class, 0, len(classes)),
+	}
+	for classHash, class := range classes {
+		archived, err := adaptClass(&classHash, class)
+		if err != nil {
+			return fmt.Errorf("class %s: %v", &classHash, err)
+		}
+		record.Classes = append(record.Classes, archived)
+	}
+	slices.SortFunc(record.Classes, func(a, b Class) int { return a.ClassHash.Cmp(b.ClassHash) })
+
+	return w.encoder.Encode(record)
+}
+
+func adaptClass(classHash *felt.Felt, class core.Class) (Class, error) {
+	archived := Class{ClassHash: classHash}
+	switch c := class.(type) {
+	case *core.Cairo0Class:
+		definition, err := core2sn.AdaptCairo0Class(c)
+		if err != nil {
+			return Class{}, err
+		}
+		archived.Definition = &starknet.ClassDefinition{V0: definition}
+	case *core.Cairo1Class:
+		archived.Definition = &starknet.ClassDefinition{V1: core2sn.AdaptSierraClass(c)}
+		if c.Compiled != nil {
+			compiledClass := core2sn.AdaptCompiledClass(c.Compiled)
+			archived.CompiledClass = &compiledClass
+		}
+	default:
+		return Class{}, fmt.Errorf("unknown class type %T", class)
+	}
+	return archived, nil
+}
+
+// Reader reads records from an archive. Records may be separated by any whitespace, so both JSON lines and
+// concatenated JSON files can be read.
+type Reader struct {
+	decoder *json.Decoder
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{decoder: json.NewDecoder(r)}
+}
+
+// Next reads the next record of the archive and adapts it to core types. It returns io.EOF once all the records
+// have been read.
+func (r *Reader) Next() (*core.StateUpdate, *core.Block, map[felt.Felt]core.Class, error) {
+	var record Record
+	if err := r.decoder.Decode(&record); err != nil {
+		return nil, nil, nil, err
+	}
+	if record.Block == nil || record.StateUpdate == nil {
+		return nil, nil, nil, errors.New("record without a block or a state update")
+	}
+
+	stateUpdate, err := sn2core.AdaptStateUpdate(record.StateUpdate)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	block, err := sn2core.AdaptBlock(record.Block, record.Signature)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	classes := make(map[felt.Felt]core.Class, len(record.Classes))
+	for _, archived := range record.Classes {
+		if archived.ClassHash == nil || archived.Definition == nil {
+			return nil, nil, nil, fmt.Errorf("class without a hash or a definition in block %d", block.Number)
+		}
+
+		var class core.Class
+		if archived.Definition.V1 != nil {
+			class, err = sn2core.AdaptCairo1Class(archived.Definition.V1, archived.CompiledClass)
+		} else {
+			class, err = sn2core.AdaptCairo0Class(archived.Definition.V0)
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("class %s: %v", archived.ClassHash, err)
+		}
+		classes[*archived.ClassHash] = class
+	}
+	return stateUpdate, block, classes, nil
+}
@@ -0,0 +1,103 @@
+package archive_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/NethermindEth/juno/archive"
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db/pebble"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const archivedBlocks = 3
+
+// feederArchive writes the first mainnet blocks of the feeder test client, with all the classes they deploy
+// or declare, to an archive.
+func feederArchive(t *testing.T) []byte {
+	t.Helper()
+
+	gw := adaptfeeder.New(feeder.NewTestClient(t, &utils.Mainnet))
+	var buf bytes.Buffer
+	w := archive.NewWriter(&buf)
+	for number := range uint64(archivedBlocks) {
+		stateUpdate, block, err := gw.StateUpdateWithBlock(t.Context(), number)
+		require.NoError(t, err)
+
+		classes := make(map[felt.Felt]core.Class)
+		for _, classHash := range stateUpdate.StateDiff.DeployedContracts {
+			class, err := gw.Class(t.Context(), classHash)
+			require.NoError(t, err)
+			classes[*classHash] = class
+		}
+		require.NoError(t, w.Write(stateUpdate, block, classes))
+	}
+	return buf.Bytes()
+}
+
+func TestImportExport(t *testing.T) {
+	log := utils.NewNopZapLogger()
+	source := feederArchive(t)
+
+	chain := blockchain.New(pebble.NewMemTest(t), &utils.Mainnet)
+	imported, err := archive.Import(t.Context(), chain, archive.NewReader(bytes.NewReader(source)), log)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(archivedBlocks), imported)
+
+	var exported bytes.Buffer
+	require.NoError(t, archive.Export(t.Context(), chain, archive.NewWriter(&exported), 0, archivedBlocks-1))
+
+	t.Run("export round trips", func(t *testing.T) {
+		other := blockchain.New(pebble.NewMemTest(t), &utils.Mainnet)
+		imported, err := archive.Import(t.Context(), other, archive.NewReader(bytes.NewReader(exported.Bytes())), log)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(archivedBlocks), imported)
+
+		for number := range uint64(archivedBlocks) {
+			expected, err := chain.BlockByNumber(number)
+			require.NoError(t, err)
+			block, err := other.BlockByNumber(number)
+			require.NoError(t, err)
+			assert.Equal(t, expected, block)
+		}
+
+		var reexported bytes.Buffer
+		require.NoError(t, archive.Export(t.Context(), other, archive.NewWriter(&reexported), 0, archivedBlocks-1))
+		assert.Equal(t, exported.String(), reexported.String())
+	})
+
+	t.Run("stored blocks are skipped", func(t *testing.T) {
+		imported, err := archive.Import(t.Context(), chain, archive.NewReader(bytes.NewReader(source)), log)
+		require.NoError(t, err)
+		assert.Zero(t, imported)
+	})
+
+	t.Run("missing blocks", func(t *testing.T) {
+		var partial bytes.Buffer
+		require.NoError(t, archive.Export(t.Context(), chain, archive.NewWriter(&partial), 1, archivedBlocks-1))
+
+		other := blockchain.New(pebble.NewMemTest(t), &utils.Mainnet)
+		_, err := archive.Import(t.Context(), other, archive.NewReader(&partial), log)
+		require.ErrorContains(t, err, "expected block 0 next")
+	})
+
+	t.Run("missing classes", func(t *testing.T) {
+		stateUpdate, err := chain.StateUpdateByNumber(0)
+		require.NoError(t, err)
+		block, err := chain.BlockByNumber(0)
+		require.NoError(t, err)
+
+		var withoutClasses bytes.Buffer
+		require.NoError(t, archive.NewWriter(&withoutClasses).Write(stateUpdate, block, nil))
+
+		other := blockchain.New(pebble.NewMemTest(t), &utils.Mainnet)
+		_, err = archive.Import(t.Context(), other, archive.NewReader(&withoutClasses), log)
+		require.ErrorContains(t, err, "is not in the archive")
+	})
+}
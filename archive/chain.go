@@ -0,0 +1,175 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/utils"
+)
+
+// Export writes the blocks from..to of the chain to the archive, together with the classes first declared in
+// each of them.
+func Export(ctx context.Context, chain *blockchain.Blockchain, w *Writer, from, to uint64) error {
+	state, closer, err := chain.HeadState()
+	if err != nil {
+		return err
+	}
+	defer closer() //nolint:errcheck
+
+	for blockNumber := from; blockNumber <= to; blockNumber++ {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		block, err := chain.BlockByNumber(blockNumber)
+		if err != nil {
+			return fmt.Errorf("block %d: %w", blockNumber, err)
+		}
+		stateUpdate, err := chain.StateUpdateByNumber(blockNumber)
+		if err != nil {
+			return fmt.Errorf("state update %d: %w", blockNumber, err)
+		}
+
+		classes := make(map[felt.Felt]core.Class)
+		for _, classHash := range classHashes(stateUpdate.StateDiff) {
+			declared, err := state.Class(classHash)
+			if err != nil {
+				return fmt.Errorf("class %s of block %d: %w", classHash, blockNumber, err)
+			}
+			// classes deployed in the block may have been declared in an earlier one
+			if declared.At == blockNumber {
+				classes[*classHash] = declared.Class
+			}
+		}
+
+		if err = w.Write(stateUpdate, block, classes); err != nil {
+			return fmt.Errorf("write block %d: %w", blockNumber, err)
+		}
+	}
+	return nil
+}
+
+// Import stores the blocks read from the archive on top of the chain, with the same checks as the synchronizer.
+// Blocks the chain already has are skipped if their hashes match. The number of stored blocks is returned.
+func Import(ctx context.Context, chain *blockchain.Blockchain, r *Reader, log utils.SimpleLogger) (uint64, error) {
+	var imported uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return imported, err
+		}
+
+		stateUpdate, block, classes, err := r.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return imported, nil
+			}
+			return imported, fmt.Errorf("read archive: %w", err)
+		}
+
+		stored, err := storeBlock(chain, stateUpdate, block, classes)
+		if err != nil {
+			return imported, fmt.Errorf("block %d: %w", block.Number, err)
+		}
+		if stored {
+			imported++
+			log.Infow("Imported block", "number", block.Number, "hash", block.Hash.ShortString())
+		}
+	}
+}
+
+func storeBlock(chain *blockchain.Blockchain, stateUpdate *core.StateUpdate, block *core.Block,
+	classes map[felt.Felt]core.Class,
+) (bool, error) {
+	var expected uint64
+	height, err := chain.Height()
+	if err == nil {
+		expected = height + 1
+	} else if !errors.Is(err, db.ErrKeyNotFound) {
+		return false, err
+	}
+
+	if block.Number < expected {
+		header, err := chain.BlockHeaderByNumber(block.Number)
+		if err != nil {
+			return false, err
+		}
+		if !header.Hash.Equal(block.Hash) {
+			return false, fmt.Errorf("hash %s does not match the stored block hash %s", block.Hash, header.Hash)
+		}
+		return false, nil
+	} else if block.Number > expected {
+		return false, fmt.Errorf("expected block %d next", expected)
+	}
+
+	newClasses, err := unknownClasses(chain, stateUpdate, classes)
+	if err != nil {
+		return false, err
+	}
+
+	commitments, err := chain.SanityCheckNewHeight(block, stateUpdate, newClasses)
+	if err != nil {
+		return false, err
+	}
+	return true, chain.Store(block, commitments, stateUpdate, newClasses)
+}
+
+// unknownClasses picks the classes of the state update that the chain does not have yet from the archived ones.
+func unknownClasses(chain *blockchain.Blockchain, stateUpdate *core.StateUpdate,
+	classes map[felt.Felt]core.Class,
+) (map[felt.Felt]core.Class, error) {
+	state, closer, err := chain.HeadState()
+	if err != nil {
+		// if err is db.ErrKeyNotFound we are on an empty DB
+		if !errors.Is(err, db.ErrKeyNotFound) {
+			return nil, err
+		}
+		closer = func() error {
+			return nil
+		}
+	}
+
+	newClasses := make(map[felt.Felt]core.Class)
+	for _, classHash := range classHashes(stateUpdate.StateDiff) {
+		if _, ok := newClasses[*classHash]; ok {
+			continue
+		}
+
+		stateErr := db.ErrKeyNotFound
+		if state != nil {
+			_, stateErr = state.Class(classHash)
+		}
+		if !errors.Is(stateErr, db.ErrKeyNotFound) {
+			if stateErr != nil {
+				return nil, utils.RunAndWrapOnError(closer, stateErr)
+			}
+			continue
+		}
+
+		class, ok := classes[*classHash]
+		if !ok {
+			return nil, utils.RunAndWrapOnError(closer, fmt.Errorf("class %s is not in the archive", classHash))
+		}
+		newClasses[*classHash] = class
+	}
+	return newClasses, closer()
+}
+
+// classHashes lists the classes a state diff deploys or declares.
+func classHashes(stateDiff *core.StateDiff) []*felt.Felt {
+	hashes := make([]*felt.Felt, 0, len(stateDiff.DeployedContracts)+len(stateDiff.DeclaredV0Classes)+
+		len(stateDiff.DeclaredV1Classes))
+	for _, classHash := range stateDiff.DeployedContracts {
+		hashes = append(hashes, classHash)
+	}
+	hashes = append(hashes, stateDiff.DeclaredV0Classes...)
+	for classHash := range stateDiff.DeclaredV1Classes {
+		hashes = append(hashes, &classHash)
+	}
+	return hashes
+}
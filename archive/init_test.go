@@ -0,0 +1,5 @@
+package archive_test
+
+import (
+	_ "github.com/NethermindEth/juno/encoder/registry"
+)
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/NethermindEth/juno/core"
@@ -89,6 +90,9 @@ type Blockchain struct {
 	listener       EventListener
 	l1HeadFeed     *feed.Feed[*core.L1Head]
 	pendingBlockFn func() *core.Block
+
+	historyMu sync.RWMutex
+	history   *historyBounds // nil until loaded
 }
 
 func New(database db.DB, network *utils.Network) *Blockchain {
@@ -187,6 +191,9 @@ func (b *Blockchain) BlockByNumber(number uint64) (*core.Block, error) {
 	b.listener.OnRead("BlockByNumber")
 	var block *core.Block
 	return block, b.database.View(func(txn db.Transaction) error {
+		if err := b.checkHistory(txn, number); err != nil {
+			return err
+		}
 		var err error
 		block, err = BlockByNumber(txn, number)
 		return err
@@ -197,6 +204,9 @@ func (b *Blockchain) BlockHeaderByNumber(number uint64) (*core.Header, error) {
 	b.listener.OnRead("BlockHeaderByNumber")
 	var header *core.Header
 	return header, b.database.View(func(txn db.Transaction) error {
+		if err := b.checkHistory(txn, number); err != nil {
+			return err
+		}
 		var err error
 		header, err = blockHeaderByNumber(txn, number)
 		return err
@@ -208,8 +218,10 @@ func (b *Blockchain) BlockByHash(hash *felt.Felt) (*core.Block, error) {
 	var block *core.Block
 	return block, b.database.View(func(txn db.Transaction) error {
 		var err error
-		block, err = blockByHash(txn, hash)
-		return err
+		if block, err = blockByHash(txn, hash); err != nil {
+			return err
+		}
+		return b.checkHistory(txn, block.Number)
 	})
 }
 
@@ -218,8 +230,10 @@ func (b *Blockchain) BlockHeaderByHash(hash *felt.Felt) (*core.Header, error) {
 	var header *core.Header
 	return header, b.database.View(func(txn db.Transaction) error {
 		var err error
-		header, err = blockHeaderByHash(txn, hash)
-		return err
+		if header, err = blockHeaderByHash(txn, hash); err != nil {
+			return err
+		}
+		return b.checkHistory(txn, header.Number)
 	})
 }
 
@@ -227,6 +241,9 @@ func (b *Blockchain) StateUpdateByNumber(number uint64) (*core.StateUpdate, erro
 	b.listener.OnRead("StateUpdateByNumber")
 	var update *core.StateUpdate
 	return update, b.database.View(func(txn db.Transaction) error {
+		if err := b.checkHistory(txn, number); err != nil {
+			return err
+		}
 		var err error
 		update, err = stateUpdateByNumber(txn, number)
 		return err
@@ -237,8 +254,14 @@ func (b *Blockchain) StateUpdateByHash(hash *felt.Felt) (*core.StateUpdate, erro
 	b.listener.OnRead("StateUpdateByHash")
 	var update *core.StateUpdate
 	return update, b.database.View(func(txn db.Transaction) error {
-		var err error
-		update, err = stateUpdateByHash(txn, hash)
+		header, err := blockHeaderByHash(txn, hash)
+		if err != nil {
+			return err
+		}
+		if err = b.checkHistory(txn, header.Number); err != nil {
+			return err
+		}
+		update, err = stateUpdateByNumber(txn, header.Number)
 		return err
 	})
 }
@@ -258,6 +281,9 @@ func (b *Blockchain) TransactionByBlockNumberAndIndex(blockNumber, index uint64)
 	b.listener.OnRead("TransactionByBlockNumberAndIndex")
 	var transaction core.Transaction
 	return transaction, b.database.View(func(txn db.Transaction) error {
+		if err := b.checkHistory(txn, blockNumber); err != nil {
+			return err
+		}
 		var err error
 		transaction, err = transactionByBlockNumberAndIndex(txn, &txAndReceiptDBKey{blockNumber, index})
 		return err
@@ -269,8 +295,14 @@ func (b *Blockchain) TransactionByHash(hash *felt.Felt) (core.Transaction, error
 	b.listener.OnRead("TransactionByHash")
 	var transaction core.Transaction
 	return transaction, b.database.View(func(txn db.Transaction) error {
-		var err error
-		transaction, err = transactionByHash(txn, hash)
+		bnIndex, err := transactionBlockNumberAndIndexByHash(txn, hash)
+		if err != nil {
+			return err
+		}
+		if err = b.checkHistory(txn, bnIndex.Number); err != nil {
+			return err
+		}
+		transaction, err = transactionByBlockNumberAndIndex(txn, bnIndex)
 		return err
 	})
 }
@@ -285,8 +317,10 @@ func (b *Blockchain) Receipt(hash *felt.Felt) (*core.TransactionReceipt, *felt.F
 	)
 	return receipt, blockHash, blockNumber, b.database.View(func(txn db.Transaction) error {
 		var err error
-		receipt, blockHash, blockNumber, err = receiptByHash(txn, hash)
-		return err
+		if receipt, blockHash, blockNumber, err = receiptByHash(txn, hash); err != nil {
+			return err
+		}
+		return b.checkHistory(txn, blockNumber)
 	})
 }
 
@@ -336,41 +370,70 @@ func (b *Blockchain) Store(block *core.Block, blockCommitments *core.BlockCommit
 	stateUpdate *core.StateUpdate, newClasses map[felt.Felt]core.Class,
 ) error {
 	return b.database.Update(func(txn db.Transaction) error {
-		if err := verifyBlock(txn, block); err != nil {
-			return err
-		}
+		return storeBlock(txn, block, blockCommitments, stateUpdate, newClasses)
+	})
+}
 
-		if err := core.NewState(txn).Update(block.Number, stateUpdate, newClasses); err != nil {
-			return err
-		}
-		if err := StoreBlockHeader(txn, block.Header); err != nil {
-			return err
-		}
+// VerifiedBlock is a block that passed SanityCheckNewHeight, with everything stored along with it.
+type VerifiedBlock struct {
+	Block       *core.Block
+	Commitments *core.BlockCommitments
+	StateUpdate *core.StateUpdate
+	NewClasses  map[felt.Felt]core.Class
+}
 
-		for i, tx := range block.Transactions {
-			if err := storeTransactionAndReceipt(txn, block.Number, uint64(i), tx,
-				block.Receipts[i]); err != nil {
+// StoreBatch stores consecutive blocks in a single database transaction. Their state updates are applied one
+// after the other, committing the tries of every block so that its state root is still verified: only the
+// transaction is shared. Either all the blocks are stored or none.
+func (b *Blockchain) StoreBatch(blocks []*VerifiedBlock) error {
+	return b.database.Update(func(txn db.Transaction) error {
+		for _, verified := range blocks {
+			if err := storeBlock(txn, verified.Block, verified.Commitments, verified.StateUpdate,
+				verified.NewClasses); err != nil {
 				return err
 			}
 		}
+		return nil
+	})
+}
 
-		if err := storeStateUpdate(txn, block.Number, stateUpdate); err != nil {
-			return err
-		}
+func storeBlock(txn db.Transaction, block *core.Block, blockCommitments *core.BlockCommitments,
+	stateUpdate *core.StateUpdate, newClasses map[felt.Felt]core.Class,
+) error {
+	if err := verifyBlock(txn, block); err != nil {
+		return err
+	}
 
-		if err := StoreBlockCommitments(txn, block.Number, blockCommitments); err != nil {
-			return err
-		}
+	if err := core.NewState(txn).Update(block.Number, stateUpdate, newClasses); err != nil {
+		return err
+	}
+	if err := StoreBlockHeader(txn, block.Header); err != nil {
+		return err
+	}
 
-		if err := StoreL1HandlerMsgHashes(txn, block.Transactions); err != nil {
+	for i, tx := range block.Transactions {
+		if err := storeTransactionAndReceipt(txn, block.Number, uint64(i), tx,
+			block.Receipts[i]); err != nil {
 			return err
 		}
+	}
 
-		// Head of the blockchain is maintained as follows:
-		// [db.ChainHeight]() -> (BlockNumber)
-		heightBin := core.MarshalBlockNumber(block.Number)
-		return txn.Set(db.ChainHeight.Key(), heightBin)
-	})
+	if err := storeStateUpdate(txn, block.Number, stateUpdate); err != nil {
+		return err
+	}
+
+	if err := StoreBlockCommitments(txn, block.Number, blockCommitments); err != nil {
+		return err
+	}
+
+	if err := StoreL1HandlerMsgHashes(txn, block.Transactions); err != nil {
+		return err
+	}
+
+	// Head of the blockchain is maintained as follows:
+	// [db.ChainHeight]() -> (BlockNumber)
+	heightBin := core.MarshalBlockNumber(block.Number)
+	return txn.Set(db.ChainHeight.Key(), heightBin)
 }
 
 // VerifyBlock assumes the block has already been sanity-checked.
@@ -421,6 +484,9 @@ func (b *Blockchain) BlockCommitmentsByNumber(blockNumber uint64) (*core.BlockCo
 	b.listener.OnRead("BlockCommitmentsByNumber")
 	var commitments *core.BlockCommitments
 	return commitments, b.database.View(func(txn db.Transaction) error {
+		if err := b.checkHistory(txn, blockNumber); err != nil {
+			return err
+		}
 		var err error
 		commitments, err = blockCommitmentsByNumber(txn, blockNumber)
 		return err
@@ -621,15 +687,6 @@ func stateUpdateByNumber(txn db.Transaction, blockNumber uint64) (*core.StateUpd
 	return update, nil
 }
 
-func stateUpdateByHash(txn db.Transaction, hash *felt.Felt) (*core.StateUpdate, error) {
-	var update *core.StateUpdate
-	return update, txn.Get(db.BlockHeaderNumbersByHash.Key(hash.Marshal()), func(val []byte) error {
-		var err error
-		update, err = stateUpdateByNumber(txn, binary.BigEndian.Uint64(val))
-		return err
-	})
-}
-
 func l1HandlerTxnHashByMsgHash(txn db.Transaction, l1HandlerMsgHash *common.Hash) (*felt.Felt, error) {
 	l1HandlerTxnHash := new(felt.Felt)
 	return l1HandlerTxnHash, txn.Get(db.L1HandlerTxnHashByMsgHash.Key(l1HandlerMsgHash.Bytes()), func(val []byte) error {
@@ -729,15 +786,6 @@ func transactionByBlockNumberAndIndex(txn db.Transaction, bnIndex *txAndReceiptD
 	return transaction, err
 }
 
-// transactionByHash gets the transaction for a given hash.
-func transactionByHash(txn db.Transaction, hash *felt.Felt) (core.Transaction, error) {
-	bnIndex, err := transactionBlockNumberAndIndexByHash(txn, hash)
-	if err != nil {
-		return nil, err
-	}
-	return transactionByBlockNumberAndIndex(txn, bnIndex)
-}
-
 // receiptByHash gets the transaction receipt for a given hash.
 func receiptByHash(txn db.Transaction, hash *felt.Felt) (*core.TransactionReceipt, *felt.Felt, uint64, error) {
 	bnIndex, err := transactionBlockNumberAndIndexByHash(txn, hash)
@@ -794,6 +842,9 @@ func (b *Blockchain) StateAtBlockNumber(blockNumber uint64) (core.StateReader, S
 	}
 
 	_, err = blockHeaderByNumber(txn, blockNumber)
+	if err == nil {
+		err = b.checkStateHistory(txn, blockNumber)
+	}
 	if err != nil {
 		return nil, nil, utils.RunAndWrapOnError(txn.Discard, err)
 	}
@@ -816,6 +867,9 @@ func (b *Blockchain) StateAtBlockHash(blockHash *felt.Felt) (core.StateReader, S
 	}
 
 	header, err := blockHeaderByHash(txn, blockHash)
+	if err == nil {
+		err = b.checkStateHistory(txn, header.Number)
+	}
 	if err != nil {
 		return nil, nil, utils.RunAndWrapOnError(txn.Discard, err)
 	}
@@ -868,6 +922,13 @@ func (b *Blockchain) revertHead(txn db.Transaction) error {
 	}
 	numBytes := core.MarshalBlockNumber(blockNumber)
 
+	// blocks before the checkpoint are not verified, so the chain cannot be reverted to them
+	if checkpoint, err := getCheckpoint(txn); err == nil && blockNumber <= checkpoint.Number {
+		return fmt.Errorf("cannot revert the checkpoint block %d", checkpoint.Number)
+	} else if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	}
+
 	stateUpdate, err := stateUpdateByNumber(txn, blockNumber)
 	if err != nil {
 		return err
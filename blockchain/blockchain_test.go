@@ -1,6 +1,7 @@
 package blockchain_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -279,6 +280,43 @@ func TestStore(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, stateUpdate1, got1Update)
 	})
+
+	t.Run("add a batch of blocks", func(t *testing.T) {
+		batch := make([]*blockchain.VerifiedBlock, 3)
+		for i := range batch {
+			block, err := gw.BlockByNumber(t.Context(), uint64(i))
+			require.NoError(t, err)
+			stateUpdate, err := gw.StateUpdate(t.Context(), uint64(i))
+			require.NoError(t, err)
+			batch[i] = &blockchain.VerifiedBlock{Block: block, Commitments: &emptyCommitments, StateUpdate: stateUpdate}
+		}
+
+		chain := blockchain.New(pebble.NewMemTest(t), &utils.Mainnet)
+		require.NoError(t, chain.StoreBatch(batch))
+
+		headBlock, err := chain.Head()
+		require.NoError(t, err)
+		assert.Equal(t, batch[2].Block, headBlock)
+
+		root, err := chain.StateCommitment()
+		require.NoError(t, err)
+		assert.Equal(t, batch[2].StateUpdate.NewRoot, root)
+
+		for _, verified := range batch {
+			gotBlock, err := chain.BlockByNumber(verified.Block.Number)
+			require.NoError(t, err)
+			assert.Equal(t, verified.Block, gotBlock)
+		}
+
+		t.Run("nothing is stored if a block fails", func(t *testing.T) {
+			chain := blockchain.New(pebble.NewMemTest(t), &utils.Mainnet)
+			require.ErrorContains(t, chain.StoreBatch([]*blockchain.VerifiedBlock{batch[0], batch[2]}),
+				"expected block #1, got block #2")
+
+			_, err := chain.Height()
+			require.ErrorIs(t, err, db.ErrKeyNotFound)
+		})
+	})
 }
 
 func TestStoreL1HandlerTxnHash(t *testing.T) {
@@ -530,7 +568,7 @@ func TestEvents(t *testing.T) {
 
 		allEvents := []*blockchain.FilteredEvent{}
 		t.Run("get all events without pagination", func(t *testing.T) {
-			events, cToken, eErr := filter.Events(nil, 10)
+			events, cToken, eErr := filter.Events(t.Context(), nil, 10)
 			require.Empty(t, cToken)
 			require.NoError(t, eErr)
 			require.Len(t, events, 3)
@@ -547,7 +585,7 @@ func TestEvents(t *testing.T) {
 				var lastToken *blockchain.ContinuationToken
 				var gotEvents []*blockchain.FilteredEvent
 				for range len(allEvents) + 1 {
-					gotEvents, lastToken, err = filter.Events(lastToken, chunkSize)
+					gotEvents, lastToken, err = filter.Events(t.Context(), lastToken, chunkSize)
 					require.NoError(t, err)
 					accEvents = append(accEvents, gotEvents...)
 					if lastToken == nil {
@@ -572,7 +610,7 @@ func TestEvents(t *testing.T) {
 			utils.HexToFelt(t, "0x3b43b334f46b921938854ba85ffc890c1b1321f8fd69e7b2961b18b4260de14")))
 
 		t.Run("get all events without pagination", func(t *testing.T) {
-			events, cToken, err := filter.Events(nil, 10)
+			events, cToken, err := filter.Events(t.Context(), nil, 10)
 			require.Empty(t, cToken)
 			require.NoError(t, err)
 			require.Len(t, events, 1)
@@ -588,12 +626,27 @@ func TestEvents(t *testing.T) {
 		require.NoError(t, err)
 		require.NoError(t, filter.SetRangeEndBlockByNumber(blockchain.EventFilterFrom, 0))
 		require.NoError(t, filter.SetRangeEndBlockByNumber(blockchain.EventFilterTo, 6))
-		events, cToken, err := filter.Events(nil, 10)
+		events, cToken, err := filter.Events(t.Context(), nil, 10)
 		require.NoError(t, err)
 		require.Nil(t, cToken)
 		require.Empty(t, events)
 		require.NoError(t, filter.Close())
 	})
+
+	t.Run("cancelled context stops the scan", func(t *testing.T) {
+		filter, err := chain.EventFilter(from, nil)
+		require.NoError(t, err)
+		require.NoError(t, filter.SetRangeEndBlockByNumber(blockchain.EventFilterFrom, 0))
+		require.NoError(t, filter.SetRangeEndBlockByNumber(blockchain.EventFilterTo, 6))
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		events, cToken, err := filter.Events(ctx, nil, 10)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Nil(t, cToken)
+		require.Empty(t, events)
+		require.NoError(t, filter.Close())
+	})
 }
 
 func TestRevert(t *testing.T) {
@@ -707,3 +760,134 @@ func TestSubscribeL1Head(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, l1Head, got)
 }
+
+func TestCheckpoint(t *testing.T) {
+	client := feeder.NewTestClient(t, &utils.Mainnet)
+	gw := adaptfeeder.New(client)
+
+	newChain := func(t *testing.T, height uint64) *blockchain.Blockchain {
+		t.Helper()
+		chain := blockchain.New(pebble.NewMemTest(t), &utils.Mainnet)
+		for i := range height + 1 {
+			b, err := gw.BlockByNumber(t.Context(), i)
+			require.NoError(t, err)
+			su, err := gw.StateUpdate(t.Context(), i)
+			require.NoError(t, err)
+			require.NoError(t, chain.Store(b, &emptyCommitments, su, nil))
+		}
+		return chain
+	}
+
+	block1, err := gw.BlockByNumber(t.Context(), 1)
+	require.NoError(t, err)
+	checkpoint := &blockchain.Checkpoint{Number: 1, Hash: block1.Hash}
+
+	t.Run("history before the checkpoint is unavailable", func(t *testing.T) {
+		chain := newChain(t, 2)
+		require.NoError(t, chain.SetCheckpoint(t.Context(), checkpoint))
+
+		got, err := chain.Checkpoint()
+		require.NoError(t, err)
+		assert.Equal(t, checkpoint, got)
+
+		_, err = chain.BlockByNumber(0)
+		require.ErrorIs(t, err, blockchain.ErrHistoryUnavailable)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+		_, err = chain.StateUpdateByNumber(0)
+		require.ErrorIs(t, err, blockchain.ErrHistoryUnavailable)
+		_, _, err = chain.StateAtBlockNumber(0)
+		require.ErrorIs(t, err, blockchain.ErrHistoryUnavailable)
+
+		block, err := chain.BlockByHash(block1.Hash)
+		require.NoError(t, err)
+		assert.Equal(t, block1, block)
+	})
+
+	t.Run("store history", func(t *testing.T) {
+		block2, err := gw.BlockByNumber(t.Context(), 2)
+		require.NoError(t, err)
+		chain := newChain(t, 2)
+		require.NoError(t, chain.SetCheckpoint(t.Context(), &blockchain.Checkpoint{Number: 2, Hash: block2.Hash}))
+
+		verified := func(number uint64) *blockchain.VerifiedBlock {
+			stateUpdate, block, err := gw.StateUpdateWithBlock(t.Context(), number)
+			require.NoError(t, err)
+			return &blockchain.VerifiedBlock{Block: block, Commitments: &emptyCommitments, StateUpdate: stateUpdate}
+		}
+
+		require.ErrorContains(t, chain.StoreHistory([]*blockchain.VerifiedBlock{verified(0)}), "expected block #1")
+		require.NoError(t, chain.StoreHistory([]*blockchain.VerifiedBlock{verified(1)}))
+		start, err := chain.HistoryStart()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), start)
+
+		block, err := chain.BlockByNumber(1)
+		require.NoError(t, err)
+		assert.Equal(t, block1, block)
+		_, _, err = chain.StateAtBlockNumber(1)
+		require.ErrorIs(t, err, blockchain.ErrHistoryUnavailable)
+
+		block0 := verified(0)
+		block0.Block.Hash = new(felt.Felt).SetUint64(1)
+		require.ErrorContains(t, chain.StoreHistory([]*blockchain.VerifiedBlock{block0}), "does not match the parent hash")
+		require.NoError(t, chain.StoreHistory([]*blockchain.VerifiedBlock{verified(0)}))
+		start, err = chain.HistoryStart()
+		require.NoError(t, err)
+		assert.Zero(t, start)
+		require.ErrorContains(t, chain.StoreHistory([]*blockchain.VerifiedBlock{verified(0)}), "the history is complete")
+	})
+
+	t.Run("the checkpoint is loaded from the database", func(t *testing.T) {
+		testDB := pebble.NewMemTest(t)
+		chain := blockchain.New(testDB, &utils.Mainnet)
+		for i := range uint64(2) {
+			stateUpdate, block, err := gw.StateUpdateWithBlock(t.Context(), i)
+			require.NoError(t, err)
+			require.NoError(t, chain.Store(block, &emptyCommitments, stateUpdate, nil))
+		}
+		require.NoError(t, chain.SetCheckpoint(t.Context(), checkpoint))
+
+		reopened := blockchain.New(testDB, &utils.Mainnet)
+		got, err := reopened.Checkpoint()
+		require.NoError(t, err)
+		assert.Equal(t, checkpoint, got)
+		start, err := reopened.HistoryStart()
+		require.NoError(t, err)
+		assert.Equal(t, checkpoint.Number, start)
+		_, err = reopened.BlockByNumber(0)
+		require.ErrorIs(t, err, blockchain.ErrHistoryUnavailable)
+	})
+
+	t.Run("setting the checkpoint again", func(t *testing.T) {
+		chain := newChain(t, 1)
+		require.NoError(t, chain.SetCheckpoint(t.Context(), checkpoint))
+		require.NoError(t, chain.SetCheckpoint(t.Context(), checkpoint))
+		require.Error(t, chain.SetCheckpoint(t.Context(), &blockchain.Checkpoint{Number: 0, Hash: block1.ParentHash}))
+	})
+
+	t.Run("the checkpoint block cannot be reverted", func(t *testing.T) {
+		chain := newChain(t, 2)
+		require.NoError(t, chain.SetCheckpoint(t.Context(), checkpoint))
+		require.NoError(t, chain.RevertHead())
+		require.ErrorContains(t, chain.RevertHead(), "cannot revert the checkpoint block")
+	})
+
+	t.Run("wrong checkpoint hash", func(t *testing.T) {
+		chain := newChain(t, 2)
+		wrong := &blockchain.Checkpoint{Number: 1, Hash: new(felt.Felt).SetUint64(1)}
+		require.ErrorContains(t, chain.SetCheckpoint(t.Context(), wrong), "not the checkpoint hash")
+
+		_, err := chain.Checkpoint()
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+
+	t.Run("database ends before the checkpoint", func(t *testing.T) {
+		chain := newChain(t, 0)
+		require.ErrorContains(t, chain.SetCheckpoint(t.Context(), checkpoint), "before the checkpoint block")
+	})
+
+	t.Run("empty database", func(t *testing.T) {
+		chain := blockchain.New(pebble.NewMemTest(t), &utils.Mainnet)
+		require.ErrorContains(t, chain.SetCheckpoint(t.Context(), checkpoint), "the database is empty")
+	})
+}
@@ -0,0 +1,313 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/encoder"
+	"github.com/NethermindEth/juno/utils"
+)
+
+// ErrHistoryUnavailable is returned for blocks before the checkpoint of the chain that the node has not
+// verified, either because they are not backfilled yet or because their state is requested. Errors matching it
+// also match db.ErrKeyNotFound, so that these blocks are treated as missing.
+var ErrHistoryUnavailable = errors.New("history before the checkpoint is not available")
+
+// Checkpoint is a trusted block. A chain restored from a database snapshot is verified from its checkpoint
+// instead of from genesis.
+type Checkpoint struct {
+	Number uint64
+	Hash   *felt.Felt
+}
+
+type historyUnavailableError struct {
+	number uint64
+	start  uint64
+}
+
+func (e *historyUnavailableError) Error() string {
+	return fmt.Sprintf("block %d is not available, history starts at block %d", e.number, e.start)
+}
+
+func (e *historyUnavailableError) Is(target error) bool {
+	return target == ErrHistoryUnavailable || target == db.ErrKeyNotFound
+}
+
+// historyBounds are the checkpoint of the chain and the start of its history, which are checked by every read.
+// They are loaded from the database once and then kept up to date by SetCheckpoint and StoreHistory.
+type historyBounds struct {
+	checkpoint *Checkpoint // nil if the chain was verified from genesis
+	start      uint64
+}
+
+// historyBounds returns the cached history bounds, loading them from txn if they are not cached yet.
+func (b *Blockchain) historyBounds(txn db.Transaction) (historyBounds, error) {
+	b.historyMu.RLock()
+	bounds := b.history
+	b.historyMu.RUnlock()
+	if bounds != nil {
+		return *bounds, nil
+	}
+
+	b.historyMu.Lock()
+	defer b.historyMu.Unlock()
+	if b.history == nil {
+		checkpoint, err := getCheckpoint(txn)
+		if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+			return historyBounds{}, err
+		}
+		start, err := historyStart(txn)
+		if err != nil {
+			return historyBounds{}, err
+		}
+		b.history = &historyBounds{checkpoint: checkpoint, start: start}
+	}
+	return *b.history, nil
+}
+
+func (b *Blockchain) setHistoryBounds(bounds historyBounds) {
+	b.historyMu.Lock()
+	defer b.historyMu.Unlock()
+	b.history = &bounds
+}
+
+// Checkpoint returns the checkpoint the chain was verified from. db.ErrKeyNotFound is returned if the chain
+// was verified from genesis.
+func (b *Blockchain) Checkpoint() (*Checkpoint, error) {
+	b.listener.OnRead("Checkpoint")
+	var bounds historyBounds
+	if err := b.database.View(func(txn db.Transaction) error {
+		var err error
+		bounds, err = b.historyBounds(txn)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if bounds.checkpoint == nil {
+		return nil, db.ErrKeyNotFound
+	}
+	return bounds.checkpoint, nil
+}
+
+func getCheckpoint(txn db.Transaction) (*Checkpoint, error) {
+	var checkpoint *Checkpoint
+	if err := txn.Get(db.Checkpoint.Key(), func(val []byte) error {
+		return encoder.Unmarshal(val, &checkpoint)
+	}); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// SetCheckpoint verifies the blocks of the database, usually restored from a snapshot, from the checkpoint
+// block to the head: the checkpoint block must have the trusted hash, every block must have a valid hash and
+// link to its parent, and the state must have the global state root of the head. Blocks before the checkpoint
+// are not verified and become unavailable.
+//
+// The chain is verified once. Setting the same checkpoint again is a no-op, setting another one fails.
+func (b *Blockchain) SetCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	stored, err := b.Checkpoint()
+	if err == nil {
+		if stored.Number != checkpoint.Number || !stored.Hash.Equal(checkpoint.Hash) {
+			return fmt.Errorf("the chain was verified from the checkpoint block %d with hash %s", stored.Number,
+				stored.Hash)
+		}
+		return nil
+	} else if !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	}
+
+	if err = b.database.View(func(txn db.Transaction) error {
+		return verifyFromCheckpoint(ctx, txn, b.network, checkpoint)
+	}); err != nil {
+		return err
+	}
+
+	checkpointBytes, err := encoder.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	if err = b.database.Update(func(txn db.Transaction) error {
+		return txn.Set(db.Checkpoint.Key(), checkpointBytes)
+	}); err != nil {
+		return err
+	}
+	// nothing is backfilled before a checkpoint is set, so the history starts at the checkpoint
+	b.setHistoryBounds(historyBounds{checkpoint: checkpoint, start: checkpoint.Number})
+	return nil
+}
+
+func verifyFromCheckpoint(ctx context.Context, txn db.Transaction, network *utils.Network, checkpoint *Checkpoint) error {
+	height, err := ChainHeight(txn)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return errors.New("the database is empty, a snapshot with the checkpoint block is needed")
+		}
+		return err
+	}
+	if height < checkpoint.Number {
+		return fmt.Errorf("the database ends at block %d, before the checkpoint block %d", height, checkpoint.Number)
+	}
+
+	var parent *core.Block
+	for number := checkpoint.Number; number <= height; number++ {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		block, err := BlockByNumber(txn, number)
+		if err != nil {
+			return fmt.Errorf("block %d: %w", number, err)
+		}
+		if parent == nil {
+			if !block.Hash.Equal(checkpoint.Hash) {
+				return fmt.Errorf("block %d has hash %s, not the checkpoint hash %s", number, block.Hash, checkpoint.Hash)
+			}
+		} else if !block.ParentHash.Equal(parent.Hash) {
+			return fmt.Errorf("parent hash of block %d does not match the hash of block %d", number, parent.Number)
+		}
+
+		stateUpdate, err := stateUpdateByNumber(txn, number)
+		if err != nil {
+			return fmt.Errorf("state update %d: %w", number, err)
+		}
+		if !stateUpdate.BlockHash.Equal(block.Hash) || !stateUpdate.NewRoot.Equal(block.GlobalStateRoot) {
+			return fmt.Errorf("state update %d does not match its block", number)
+		}
+		if _, err = core.VerifyBlockHash(block, network, stateUpdate.StateDiff); err != nil {
+			return fmt.Errorf("block %d: %v", number, err)
+		}
+		parent = block
+	}
+
+	root, err := core.NewState(txn).Root()
+	if err != nil {
+		return err
+	}
+	if !root.Equal(parent.GlobalStateRoot) {
+		return fmt.Errorf("state root %s does not match the global state root %s of the head", root, parent.GlobalStateRoot)
+	}
+	return nil
+}
+
+// HistoryStart returns the lowest block whose history is available: the lowest backfilled block, or the
+// checkpoint block if none was backfilled. It is 0 if the chain was verified from genesis.
+func (b *Blockchain) HistoryStart() (uint64, error) {
+	b.listener.OnRead("HistoryStart")
+	var bounds historyBounds
+	return bounds.start, b.database.View(func(txn db.Transaction) error {
+		var err error
+		bounds, err = b.historyBounds(txn)
+		return err
+	})
+}
+
+func historyStart(txn db.Transaction) (uint64, error) {
+	var start uint64
+	err := txn.Get(db.HistoryStart.Key(), func(val []byte) error {
+		start = binary.BigEndian.Uint64(val)
+		return nil
+	})
+	if !errors.Is(err, db.ErrKeyNotFound) {
+		return start, err
+	}
+
+	checkpoint, err := getCheckpoint(txn)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return checkpoint.Number, nil
+}
+
+// StoreHistory stores backfilled blocks before the start of the history, in descending order. Each block must be
+// the parent of the block after it. The state is not touched, so the state at these blocks stays unavailable.
+func (b *Blockchain) StoreHistory(blocks []*VerifiedBlock) error {
+	var bounds historyBounds
+	if err := b.database.Update(func(txn db.Transaction) error {
+		var err error
+		if bounds, err = b.historyBounds(txn); err != nil {
+			return err
+		}
+		start := bounds.start
+
+		for _, verified := range blocks {
+			block := verified.Block
+			if start == 0 {
+				return errors.New("the history is complete")
+			} else if block.Number != start-1 {
+				return fmt.Errorf("expected block #%d, got block #%d", start-1, block.Number)
+			}
+			child, err := blockHeaderByNumber(txn, start)
+			if err != nil {
+				return err
+			}
+			if !child.ParentHash.Equal(block.Hash) {
+				return fmt.Errorf("hash of block %d does not match the parent hash of block %d", block.Number, start)
+			}
+
+			if err = storeHistoricalBlock(txn, block, verified.Commitments, verified.StateUpdate); err != nil {
+				return err
+			}
+			start = block.Number
+		}
+		bounds.start = start
+		return txn.Set(db.HistoryStart.Key(), core.MarshalBlockNumber(start))
+	}); err != nil {
+		return err
+	}
+	b.setHistoryBounds(bounds)
+	return nil
+}
+
+func storeHistoricalBlock(txn db.Transaction, block *core.Block, blockCommitments *core.BlockCommitments,
+	stateUpdate *core.StateUpdate,
+) error {
+	if err := StoreBlockHeader(txn, block.Header); err != nil {
+		return err
+	}
+	for i, tx := range block.Transactions {
+		if err := storeTransactionAndReceipt(txn, block.Number, uint64(i), tx, block.Receipts[i]); err != nil {
+			return err
+		}
+	}
+	if err := storeStateUpdate(txn, block.Number, stateUpdate); err != nil {
+		return err
+	}
+	if err := StoreBlockCommitments(txn, block.Number, blockCommitments); err != nil {
+		return err
+	}
+	return StoreL1HandlerMsgHashes(txn, block.Transactions)
+}
+
+// checkHistory fails with ErrHistoryUnavailable if the block is before the start of the history.
+func (b *Blockchain) checkHistory(txn db.Transaction, number uint64) error {
+	bounds, err := b.historyBounds(txn)
+	if err != nil {
+		return err
+	}
+	if number < bounds.start {
+		return &historyUnavailableError{number: number, start: bounds.start}
+	}
+	return nil
+}
+
+// checkStateHistory fails with ErrHistoryUnavailable if the block is before the checkpoint of the chain, since
+// the state is not backfilled.
+func (b *Blockchain) checkStateHistory(txn db.Transaction, number uint64) error {
+	bounds, err := b.historyBounds(txn)
+	if err != nil {
+		return err
+	}
+	if bounds.checkpoint != nil && number < bounds.checkpoint.Number {
+		return &historyUnavailableError{number: number, start: bounds.checkpoint.Number}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -17,7 +18,7 @@ var errChunkSizeReached = errors.New("chunk size reached")
 type EventFilterer interface {
 	io.Closer
 
-	Events(cToken *ContinuationToken, chunkSize uint64) ([]*FilteredEvent, *ContinuationToken, error)
+	Events(ctx context.Context, cToken *ContinuationToken, chunkSize uint64) ([]*FilteredEvent, *ContinuationToken, error)
 	SetRangeEndBlockByNumber(filterRange EventFilterRange, blockNumber uint64) error
 	SetRangeEndBlockByHash(filterRange EventFilterRange, blockHash *felt.Felt) error
 	WithLimit(limit uint) *EventFilter
@@ -106,8 +107,12 @@ type FilteredEvent struct {
 	EventIndex      int
 }
 
+// Events returns the events matching the filter. Scanning stops with ctx's error once ctx is done.
+//
 //nolint:gocyclo
-func (e *EventFilter) Events(cToken *ContinuationToken, chunkSize uint64) ([]*FilteredEvent, *ContinuationToken, error) {
+func (e *EventFilter) Events(ctx context.Context, cToken *ContinuationToken, chunkSize uint64) ([]*FilteredEvent,
+	*ContinuationToken, error,
+) {
 	var matchedEvents []*FilteredEvent
 	latest, err := ChainHeight(e.txn)
 	if err != nil {
@@ -135,6 +140,10 @@ func (e *EventFilter) Events(cToken *ContinuationToken, chunkSize uint64) ([]*Fi
 		rToken                 *ContinuationToken
 	)
 	for ; curBlock <= e.toBlock && remainingScannedBlocks > 0; curBlock, remainingScannedBlocks = curBlock+1, remainingScannedBlocks-1 {
+		if err = ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
 		var header *core.Header
 		if curBlock != latest+1 {
 			header, err = blockHeaderByNumber(e.txn, curBlock)
@@ -0,0 +1,189 @@
+package feeder
+
+import (
+	"container/list"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const cacheTempPrefix = "tmp-"
+
+// DiskCache stores feeder responses that never change in a directory, one file per key. Keys name what the
+// response is rather than how it was queried, such as the hash of a class or the number of a block, so that
+// the same response fetched from different feeders is stored once. Once the files exceed the size limit, the
+// least recently used ones are evicted.
+type DiskCache struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List // of *cacheEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	name string
+	size int64
+}
+
+// NewDiskCache opens the cache in dir, creating it if needed. The responses cached by earlier runs are kept, up
+// to maxSize bytes.
+func NewDiskCache(dir string, maxSize int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type file struct {
+		entry   *cacheEntry
+		modTime time.Time
+	}
+	files := make([]file, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+		// left over by an interrupted write
+		if strings.HasPrefix(dirEntry.Name(), cacheTempPrefix) {
+			if err = os.Remove(filepath.Join(dir, dirEntry.Name())); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file{
+			entry:   &cacheEntry{name: dirEntry.Name(), size: info.Size()},
+			modTime: info.ModTime(),
+		})
+	}
+	slices.SortFunc(files, func(a, b file) int {
+		return b.modTime.Compare(a.modTime)
+	})
+
+	c := &DiskCache{
+		dir:     dir,
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element, len(files)),
+	}
+	for _, f := range files {
+		c.entries[f.entry.name] = c.lru.PushBack(f.entry)
+		c.size += f.entry.size
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c, c.evict()
+}
+
+// Get returns the response cached under key, false if it is not cached. A response that cannot be read is
+// dropped from the cache.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	element, ok := c.entries[key]
+	if ok {
+		c.lru.MoveToFront(element)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	path := filepath.Join(c.dir, key)
+	body, err := os.ReadFile(path)
+	if err != nil {
+		c.mu.Lock()
+		c.remove(key)
+		c.mu.Unlock()
+		return nil, false
+	}
+	// keep the order of use across restarts
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return body, true
+}
+
+// Put caches the response under key, evicting the least recently used responses if the cache gets too large.
+// Responses larger than the cache are not cached.
+func (c *DiskCache) Put(key string, body []byte) error {
+	size := int64(len(body))
+	if size > c.maxSize {
+		return nil
+	}
+
+	// write to a temporary file first, so that a cached response is never partially written
+	tmp, err := os.CreateTemp(c.dir, cacheTempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(body); err != nil {
+		return errors.Join(err, tmp.Close(), os.Remove(tmp.Name()))
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err = os.Rename(tmp.Name(), filepath.Join(c.dir, key)); err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*cacheEntry)
+		c.size += size - entry.size
+		entry.size = size
+		c.lru.MoveToFront(element)
+	} else {
+		c.entries[key] = c.lru.PushFront(&cacheEntry{name: key, size: size})
+		c.size += size
+	}
+	return c.evict()
+}
+
+// Size returns the total size of the cached responses in bytes.
+func (c *DiskCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// evict removes the least recently used responses until the cache fits its size limit. c.mu must be held.
+func (c *DiskCache) evict() error {
+	for c.size > c.maxSize {
+		element := c.lru.Back()
+		entry := element.Value.(*cacheEntry)
+		if err := os.Remove(filepath.Join(c.dir, entry.name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		c.lru.Remove(element)
+		delete(c.entries, entry.name)
+		c.size -= entry.size
+	}
+	return nil
+}
+
+// remove drops the response cached under key, if any. c.mu must be held.
+func (c *DiskCache) remove(key string) {
+	element, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	c.lru.Remove(element)
+	delete(c.entries, key)
+	c.size -= element.Value.(*cacheEntry).size
+	_ = os.Remove(filepath.Join(c.dir, key))
+}
@@ -0,0 +1,72 @@
+package feeder_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := feeder.NewDiskCache(dir, 10)
+	require.NoError(t, err)
+
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+
+	require.NoError(t, cache.Put("a", []byte("aaaa")))
+	require.NoError(t, cache.Put("b", []byte("bbbb")))
+	body, ok := cache.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, []byte("aaaa"), body)
+	assert.Equal(t, int64(8), cache.Size())
+
+	t.Run("least recently used responses are evicted", func(t *testing.T) {
+		require.NoError(t, cache.Put("c", []byte("cccc")))
+		assert.Equal(t, int64(8), cache.Size())
+
+		_, ok = cache.Get("b")
+		assert.False(t, ok)
+		_, ok = cache.Get("a")
+		assert.True(t, ok)
+		_, ok = cache.Get("c")
+		assert.True(t, ok)
+	})
+
+	t.Run("responses larger than the cache are not cached", func(t *testing.T) {
+		require.NoError(t, cache.Put("d", []byte("ddddddddddd")))
+		_, ok = cache.Get("d")
+		assert.False(t, ok)
+	})
+
+	t.Run("responses are kept across restarts", func(t *testing.T) {
+		reopened, err := feeder.NewDiskCache(dir, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(8), reopened.Size())
+		body, ok := reopened.Get("c")
+		require.True(t, ok)
+		assert.Equal(t, []byte("cccc"), body)
+	})
+
+	t.Run("a smaller cache evicts responses on restart", func(t *testing.T) {
+		reopened, err := feeder.NewDiskCache(dir, 4)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), reopened.Size())
+	})
+
+	t.Run("unreadable responses are dropped", func(t *testing.T) {
+		reopened, err := feeder.NewDiskCache(dir, 10)
+		require.NoError(t, err)
+		size := reopened.Size()
+		require.NoError(t, reopened.Put("e", []byte("ee")))
+		require.NoError(t, os.Remove(filepath.Join(dir, "e")))
+
+		_, ok := reopened.Get("e")
+		assert.False(t, ok)
+		assert.Equal(t, size, reopened.Size())
+	})
+}
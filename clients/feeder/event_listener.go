@@ -4,10 +4,16 @@ import "time"
 
 type EventListener interface {
 	OnResponse(urlPath string, status int, took time.Duration)
+	// OnSourceResponse is called for every response of a source of a multi-source StarknetData.
+	OnSourceResponse(source, method string, err error, took time.Duration)
+	// OnSourceDisagreement is called when a source returns a block other than the one agreed on by the quorum.
+	OnSourceDisagreement(source string)
 }
 
 type SelectiveListener struct {
-	OnResponseCb func(urlPath string, status int, took time.Duration)
+	OnResponseCb           func(urlPath string, status int, took time.Duration)
+	OnSourceResponseCb     func(source, method string, err error, took time.Duration)
+	OnSourceDisagreementCb func(source string)
 }
 
 func (l *SelectiveListener) OnResponse(urlPath string, status int, took time.Duration) {
@@ -15,3 +21,15 @@ func (l *SelectiveListener) OnResponse(urlPath string, status int, took time.Dur
 		l.OnResponseCb(urlPath, status, took)
 	}
 }
+
+func (l *SelectiveListener) OnSourceResponse(source, method string, err error, took time.Duration) {
+	if l.OnSourceResponseCb != nil {
+		l.OnSourceResponseCb(source, method, err, took)
+	}
+}
+
+func (l *SelectiveListener) OnSourceDisagreement(source string) {
+	if l.OnSourceDisagreementCb != nil {
+		l.OnSourceDisagreementCb(source)
+	}
+}
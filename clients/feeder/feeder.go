@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -23,6 +24,9 @@ import (
 
 var ErrDeprecatedCompiledClass = errors.New("deprecated compiled class")
 
+// acceptedOnL1 is the status of blocks that can no longer change.
+const acceptedOnL1 = "ACCEPTED_ON_L1"
+
 type Backoff func(wait time.Duration) time.Duration
 
 type Client struct {
@@ -36,6 +40,16 @@ type Client struct {
 	userAgent  string
 	apiKey     string
 	listener   EventListener
+	cache      *DiskCache
+
+	// conditionalMu guards conditional, the last response to each query whose result changes over time
+	conditionalMu sync.Mutex
+	conditional   map[string]*conditionalResponse
+}
+
+type conditionalResponse struct {
+	etag string
+	body []byte
 }
 
 func (c *Client) WithListener(l EventListener) *Client {
@@ -43,6 +57,13 @@ func (c *Client) WithListener(l EventListener) *Client {
 	return c
 }
 
+// WithDiskCache caches the responses that never change on disk: classes, compiled classes and blocks accepted
+// on L1. Clients of the same network can share a cache. A nil cache disables caching.
+func (c *Client) WithDiskCache(cache *DiskCache) *Client {
+	c.cache = cache
+	return c
+}
+
 func (c *Client) WithBackoff(b Backoff) *Client {
 	c.backoff = b
 	return c
@@ -198,14 +219,15 @@ func handleNotFound(dir, queryArg string, w http.ResponseWriter) {
 
 func NewClient(clientURL string) *Client {
 	return &Client{
-		url:        clientURL,
-		client:     http.DefaultClient,
-		backoff:    ExponentialBackoff,
-		maxRetries: 10, // ~40 secs with default backoff and maxWait (block time on mainnet is 20 seconds on average)
-		maxWait:    4 * time.Second,
-		minWait:    time.Second,
-		log:        utils.NewNopZapLogger(),
-		listener:   &SelectiveListener{},
+		url:         clientURL,
+		client:      http.DefaultClient,
+		backoff:     ExponentialBackoff,
+		maxRetries:  10, // ~40 secs with default backoff and maxWait (block time on mainnet is 20 seconds on average)
+		maxWait:     4 * time.Second,
+		minWait:     time.Second,
+		log:         utils.NewNopZapLogger(),
+		listener:    &SelectiveListener{},
+		conditional: make(map[string]*conditionalResponse),
 	}
 }
 
@@ -229,6 +251,105 @@ func (c *Client) buildQueryString(endpoint string, args map[string]string) strin
 
 // get performs a "GET" http request with the given URL and returns the response body
 func (c *Client) get(ctx context.Context, queryURL string) (io.ReadCloser, error) {
+	res, err := c.do(ctx, queryURL, "")
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// cacheKey names a response in the disk cache by what it is, e.g. the class or the block it is about.
+func cacheKey(kind, id string) string {
+	return kind + "-" + id
+}
+
+// getBlockData gets a query about the given block and decodes it with decode. Queries about blocks that change
+// over time are conditional, the others go through the disk cache under the kind of the query and the block.
+func (c *Client) getBlockData(ctx context.Context, kind, blockID, queryURL string, decode func(body []byte) (bool, error)) error {
+	if blockID != "pending" && blockID != "latest" {
+		return c.getImmutable(ctx, cacheKey(kind, blockID), queryURL, decode)
+	}
+
+	body, err := c.getConditional(ctx, queryURL)
+	if err != nil {
+		return err
+	}
+	_, err = decode(body)
+	return err
+}
+
+// getImmutable gets a query through the disk cache, where its response is stored under key, and decodes it with
+// decode, which reports whether the response never changes and can be cached.
+func (c *Client) getImmutable(ctx context.Context, key, queryURL string, decode func(body []byte) (bool, error)) error {
+	if c.cache != nil {
+		if body, ok := c.cache.Get(key); ok {
+			if _, err := decode(body); err == nil {
+				return nil
+			}
+			c.log.Warnw("Failed to decode cached feeder response, fetching it again", "req", queryURL)
+		}
+	}
+
+	res, err := c.get(ctx, queryURL)
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+	body, err := io.ReadAll(res)
+	if err != nil {
+		return err
+	}
+
+	immutable, err := decode(body)
+	if err != nil {
+		return err
+	}
+	if immutable && c.cache != nil {
+		if err = c.cache.Put(key, body); err != nil {
+			c.log.Warnw("Failed to cache feeder response", "req", queryURL, "err", err)
+		}
+	}
+	return nil
+}
+
+// getConditional performs a conditional "GET" http request for a query whose result changes over time. The ETag
+// of the last response is sent along, and its body is reused if the feeder answers that nothing changed.
+func (c *Client) getConditional(ctx context.Context, queryURL string) ([]byte, error) {
+	c.conditionalMu.Lock()
+	last := c.conditional[queryURL]
+	c.conditionalMu.Unlock()
+
+	etag := ""
+	if last != nil {
+		etag = last.etag
+	}
+	res, err := c.do(ctx, queryURL, etag)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotModified {
+		return last.body, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.conditionalMu.Lock()
+	if etag = res.Header.Get("ETag"); etag != "" {
+		c.conditional[queryURL] = &conditionalResponse{etag: etag, body: body}
+	} else {
+		delete(c.conditional, queryURL)
+	}
+	c.conditionalMu.Unlock()
+	return body, nil
+}
+
+// do performs a "GET" http request with the given URL, retrying on failures. If etag is set, the request is
+// conditional and a "304 Not Modified" response is returned as well.
+func (c *Client) do(ctx context.Context, queryURL, etag string) (*http.Response, error) {
 	var res *http.Response
 	var err error
 	wait := time.Duration(0)
@@ -248,13 +369,16 @@ func (c *Client) get(ctx context.Context, queryURL string) (io.ReadCloser, error
 			if c.apiKey != "" {
 				req.Header.Set("X-Throttling-Bypass", c.apiKey)
 			}
+			if etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
 
 			reqTimer := time.Now()
 			res, err = c.client.Do(req)
 			if err == nil {
 				c.listener.OnResponse(req.URL.Path, res.StatusCode, time.Since(reqTimer))
-				if res.StatusCode == http.StatusOK {
-					return res.Body, nil
+				if res.StatusCode == http.StatusOK || (etag != "" && res.StatusCode == http.StatusNotModified) {
+					return res, nil
 				} else {
 					err = errors.New(res.Status)
 				}
@@ -280,14 +404,12 @@ func (c *Client) StateUpdate(ctx context.Context, blockID string) (*starknet.Sta
 		"blockNumber": blockID,
 	})
 
-	body, err := c.get(ctx, queryURL)
-	if err != nil {
-		return nil, err
-	}
-	defer body.Close()
-
-	update := new(starknet.StateUpdate)
-	if err = json.NewDecoder(body).Decode(update); err != nil {
+	var update *starknet.StateUpdate
+	if err := c.getBlockData(ctx, "state_update", blockID, queryURL, func(body []byte) (bool, error) {
+		update = new(starknet.StateUpdate)
+		// the state update does not tell whether its block is accepted on L1
+		return false, json.Unmarshal(body, update)
+	}); err != nil {
 		return nil, err
 	}
 	return update, nil
@@ -316,14 +438,14 @@ func (c *Client) Block(ctx context.Context, blockID string) (*starknet.Block, er
 		"blockNumber": blockID,
 	})
 
-	body, err := c.get(ctx, queryURL)
-	if err != nil {
-		return nil, err
-	}
-	defer body.Close()
-
-	block := new(starknet.Block)
-	if err = json.NewDecoder(body).Decode(block); err != nil {
+	var block *starknet.Block
+	if err := c.getBlockData(ctx, "block", blockID, queryURL, func(body []byte) (bool, error) {
+		block = new(starknet.Block)
+		if err := json.Unmarshal(body, block); err != nil {
+			return false, err
+		}
+		return block.Status == acceptedOnL1, nil
+	}); err != nil {
 		return nil, err
 	}
 	return block, nil
@@ -335,14 +457,11 @@ func (c *Client) ClassDefinition(ctx context.Context, classHash *felt.Felt) (*st
 		"blockNumber": "pending",
 	})
 
-	body, err := c.get(ctx, queryURL)
-	if err != nil {
-		return nil, err
-	}
-	defer body.Close()
-
-	class := new(starknet.ClassDefinition)
-	if err = json.NewDecoder(body).Decode(class); err != nil {
+	var class *starknet.ClassDefinition
+	if err := c.getImmutable(ctx, cacheKey("class", classHash.String()), queryURL, func(body []byte) (bool, error) {
+		class = new(starknet.ClassDefinition)
+		return true, json.Unmarshal(body, class)
+	}); err != nil {
 		return nil, err
 	}
 	return class, nil
@@ -354,23 +473,14 @@ func (c *Client) CompiledClassDefinition(ctx context.Context, classHash *felt.Fe
 		"blockNumber": "pending",
 	})
 
-	body, err := c.get(ctx, queryURL)
-	if err != nil {
-		return nil, err
-	}
-	defer body.Close()
-
-	definition, err := io.ReadAll(body)
-	if err != nil {
-		return nil, err
-	}
-
-	if deprecated, _ := starknet.IsDeprecatedCompiledClassDefinition(definition); deprecated {
-		return nil, ErrDeprecatedCompiledClass
-	}
-
-	class := new(starknet.CompiledClass)
-	if err = json.Unmarshal(definition, class); err != nil {
+	var class *starknet.CompiledClass
+	if err := c.getImmutable(ctx, cacheKey("compiled_class", classHash.String()), queryURL, func(definition []byte) (bool, error) {
+		if deprecated, _ := starknet.IsDeprecatedCompiledClassDefinition(definition); deprecated {
+			return false, ErrDeprecatedCompiledClass
+		}
+		class = new(starknet.CompiledClass)
+		return true, json.Unmarshal(definition, class)
+	}); err != nil {
 		return nil, err
 	}
 	return class, nil
@@ -418,17 +528,16 @@ func (c *Client) StateUpdateWithBlock(ctx context.Context, blockID string) (*sta
 		"includeBlock": "true",
 	})
 
-	body, err := c.get(ctx, queryURL)
-	if err != nil {
-		return nil, err
-	}
-	defer body.Close()
-
-	stateUpdate := new(starknet.StateUpdateWithBlock)
-	if err := json.NewDecoder(body).Decode(stateUpdate); err != nil {
+	var stateUpdate *starknet.StateUpdateWithBlock
+	if err := c.getBlockData(ctx, "state_update_with_block", blockID, queryURL, func(body []byte) (bool, error) {
+		stateUpdate = new(starknet.StateUpdateWithBlock)
+		if err := json.Unmarshal(body, stateUpdate); err != nil {
+			return false, err
+		}
+		return stateUpdate.Block != nil && stateUpdate.Block.Status == acceptedOnL1, nil
+	}); err != nil {
 		return nil, err
 	}
-
 	return stateUpdate, nil
 }
 
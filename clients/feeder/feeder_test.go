@@ -561,6 +561,109 @@ func TestHttpError(t *testing.T) {
 	}
 }
 
+func TestConditionalRequests(t *testing.T) {
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `"1"`)
+		if r.Header.Get("If-None-Match") == `"1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"block_number": 1, "transactions": []}`)) //nolint:errcheck
+	}))
+	t.Cleanup(srv.Close)
+	client := feeder.NewClient(srv.URL).WithBackoff(feeder.NopBackoff).WithMaxRetries(0).WithUserAgent(ua)
+
+	for range 3 {
+		block, err := client.Block(t.Context(), "pending")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), block.Number)
+	}
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, 2, notModified)
+
+	// blocks that do not change are not requested conditionally
+	_, err := client.Block(t.Context(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, notModified)
+}
+
+func TestClientDiskCache(t *testing.T) {
+	requests := make(map[string]int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		blockNumber := r.URL.Query().Get("blockNumber")
+		requests[r.URL.Path+blockNumber]++
+		switch blockNumber {
+		case "1":
+			w.Write([]byte(`{"block_number": 1, "status": "ACCEPTED_ON_L1"}`)) //nolint:errcheck
+		case "2":
+			w.Write([]byte(`{"block_number": 2, "status": "ACCEPTED_ON_L2"}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cache, err := feeder.NewDiskCache(t.TempDir(), utils.Megabyte)
+	require.NoError(t, err)
+	client := feeder.NewClient(srv.URL).WithBackoff(feeder.NopBackoff).WithMaxRetries(0).WithUserAgent(ua).
+		WithDiskCache(cache)
+
+	for range 2 {
+		block, err := client.Block(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), block.Number)
+
+		block, err = client.Block(t.Context(), "2")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), block.Number)
+	}
+	// only blocks accepted on L1 are cached
+	assert.Equal(t, 1, requests["/get_block1"])
+	assert.Equal(t, 2, requests["/get_block2"])
+}
+
+func TestClientDiskCacheSharedByFeeders(t *testing.T) {
+	newFeeder := func(requests *int) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*requests++
+			switch r.URL.Path {
+			case "/get_block":
+				w.Write([]byte(`{"block_number": 1, "status": "ACCEPTED_ON_L1"}`)) //nolint:errcheck
+			case "/get_class_by_hash":
+				w.Write([]byte(`{"sierra_program": ["0x1"], "contract_class_version": "0.1.0"}`)) //nolint:errcheck
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	var firstRequests, secondRequests int
+	first, second := newFeeder(&firstRequests), newFeeder(&secondRequests)
+
+	cache, err := feeder.NewDiskCache(t.TempDir(), utils.Megabyte)
+	require.NoError(t, err)
+	classHash := new(felt.Felt).SetUint64(1)
+	for _, srv := range []*httptest.Server{first, second} {
+		client := feeder.NewClient(srv.URL).WithBackoff(feeder.NopBackoff).WithMaxRetries(0).WithUserAgent(ua).
+			WithDiskCache(cache)
+
+		block, err := client.Block(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), block.Number)
+
+		class, err := client.ClassDefinition(t.Context(), classHash)
+		require.NoError(t, err)
+		require.NotNil(t, class.V1)
+	}
+	// the second feeder is not asked for what the first one answered
+	assert.Equal(t, 2, firstRequests)
+	assert.Equal(t, 0, secondRequests)
+}
+
 func TestBackoffFailure(t *testing.T) {
 	maxRetries := 5
 	try := 0
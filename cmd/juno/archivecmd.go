@@ -0,0 +1,249 @@
+package main
+
+import (
+	"cmp"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/NethermindEth/juno/archive"
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/migration"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	importFromF   = "from"
+	exportFromF   = "from"
+	exportToF     = "to"
+	exportOutputF = "output"
+
+	importFromUsage   = "Archive file, or directory of archive files, to import. Files ending in .gz are decompressed."
+	exportFromUsage   = "First block to export."
+	exportToUsage     = "Last block to export. Defaults to the head of the chain."
+	exportOutputUsage = "File to write the archive to, compressed if it ends in .gz. Defaults to the standard output."
+)
+
+func ImportCmd(defaultDBPath string) *cobra.Command {
+	network := utils.Mainnet
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import blocks from an archive",
+		Long: `This command stores the blocks, state updates and classes of an archive written by export-blocks, in the
+format of the feeder gateway, with the same checks as sync. The files of a directory are imported in the order of
+their names, with numeric names in numeric order. Blocks already in the database are skipped.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return importBlocks(cmd, &network)
+		},
+	}
+
+	cmd.Flags().String(dbPathF, defaultDBPath, dbPathUsage)
+	cmd.Flags().Var(&network, networkF, networkUsage)
+	cmd.Flags().String(importFromF, "", importFromUsage)
+	return cmd
+}
+
+func ExportBlocksCmd(defaultDBPath string) *cobra.Command {
+	network := utils.Mainnet
+
+	cmd := &cobra.Command{
+		Use:   "export-blocks",
+		Short: "Export a range of blocks to an archive",
+		Long: `This command writes a range of stored blocks, with their state updates, signatures and the classes they
+declare, as JSON lines in the format of the feeder gateway. The archive can be imported with the import command.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return exportBlocks(cmd, &network)
+		},
+	}
+
+	cmd.Flags().String(dbPathF, defaultDBPath, dbPathUsage)
+	cmd.Flags().Var(&network, networkF, networkUsage)
+	cmd.Flags().Uint64(exportFromF, 0, exportFromUsage)
+	cmd.Flags().Uint64(exportToF, 0, exportToUsage)
+	cmd.Flags().String(exportOutputF, "", exportOutputUsage)
+	return cmd
+}
+
+func importBlocks(cmd *cobra.Command, network *utils.Network) error {
+	dbPath, err := cmd.Flags().GetString(dbPathF)
+	if err != nil {
+		return err
+	}
+
+	from, err := cmd.Flags().GetString(importFromF)
+	if err != nil {
+		return err
+	}
+	if from == "" {
+		return fmt.Errorf("--%v is required", importFromF)
+	}
+	files, err := archiveFiles(from)
+	if err != nil {
+		return err
+	}
+
+	log, err := utils.NewZapLogger(utils.NewLogLevel(utils.INFO), false)
+	if err != nil {
+		return err
+	}
+
+	database, err := pebble.New(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open db: %w", err)
+	}
+	defer database.Close()
+
+	if err = migration.MigrateIfNeeded(cmd.Context(), database, network, log); err != nil {
+		return fmt.Errorf("failed to migrate the db: %w", err)
+	}
+	chain := blockchain.New(database, network)
+
+	var imported uint64
+	for _, file := range files {
+		n, err := importFile(cmd, chain, file, log)
+		imported += n
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	log.Infow("Import finished", "blocks", imported)
+	return nil
+}
+
+func importFile(cmd *cobra.Command, chain *blockchain.Blockchain, path string, log utils.SimpleLogger) (uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return 0, err
+		}
+		defer gzipReader.Close()
+		r = gzipReader
+	}
+	return archive.Import(cmd.Context(), chain, archive.NewReader(r), log)
+}
+
+// archiveFiles lists the files to import from a file or a directory. Files whose names, without extensions,
+// are numbers, such as block numbers, are ordered numerically, and before the other ones.
+func archiveFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	number := func(name string) (uint64, bool) {
+		n, err := strconv.ParseUint(strings.Split(name, ".")[0], 10, 64)
+		return n, err == nil
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		aNumber, aOK := number(a)
+		bNumber, bOK := number(b)
+		switch {
+		case aOK && bOK:
+			return cmp.Or(cmp.Compare(aNumber, bNumber), strings.Compare(a, b))
+		case aOK:
+			return -1
+		case bOK:
+			return 1
+		default:
+			return strings.Compare(a, b)
+		}
+	})
+
+	files := make([]string, len(names))
+	for i, name := range names {
+		files[i] = filepath.Join(path, name)
+	}
+	return files, nil
+}
+
+func exportBlocks(cmd *cobra.Command, network *utils.Network) error {
+	dbPath, err := cmd.Flags().GetString(dbPathF)
+	if err != nil {
+		return err
+	}
+
+	from, err := cmd.Flags().GetUint64(exportFromF)
+	if err != nil {
+		return err
+	}
+
+	database, err := openDB(dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	chain := blockchain.New(database, network)
+
+	to, err := cmd.Flags().GetUint64(exportToF)
+	if err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed(exportToF) {
+		if to, err = chain.Height(); err != nil {
+			return fmt.Errorf("failed to get the chain height: %v", err)
+		}
+	}
+	if from > to {
+		return fmt.Errorf("--%v cannot be greater than --%v", exportFromF, exportToF)
+	}
+
+	output, err := cmd.Flags().GetString(exportOutputF)
+	if err != nil {
+		return err
+	}
+	var (
+		w          io.Writer = cmd.OutOrStdout()
+		gzipWriter *gzip.Writer
+	)
+	if output != "" {
+		var file *os.File
+		if file, err = os.Create(output); err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+
+		if strings.HasSuffix(output, ".gz") {
+			gzipWriter = gzip.NewWriter(file)
+			w = gzipWriter
+		}
+	}
+
+	if err = archive.Export(cmd.Context(), chain, archive.NewWriter(w), from, to); err != nil {
+		return err
+	}
+	if gzipWriter != nil {
+		return gzipWriter.Close()
+	}
+	return nil
+}
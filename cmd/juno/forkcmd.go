@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/NethermindEth/juno/node"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	forkUpstreamF = "upstream"
+	forkAtBlockF  = "at-block"
+
+	forkUpstreamUsage = "URL of the Starknet RPC node to fork."
+	forkAtBlockUsage  = "Number of the upstream block to fork at."
+)
+
+func ForkCmd() *cobra.Command {
+	network := utils.Mainnet
+
+	cmd := &cobra.Command{
+		Use:   "fork",
+		Short: "Run a node on top of the state of another node",
+		Long: `This command starts a node serving the RPC on top of the state of an upstream Starknet RPC node at
+a given block. Storage, nonces, class hashes and classes are fetched from the upstream the first time they are
+read and kept in memory. Transactions added through the RPC are executed locally, each in a block of its own,
+and are never sent to the upstream.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runFork(cmd, &network)
+		},
+	}
+
+	defaultMaxVMs := 3 * runtime.GOMAXPROCS(0)
+	cmd.Flags().String(forkUpstreamF, "", forkUpstreamUsage)
+	cmd.Flags().Uint64(forkAtBlockF, 0, forkAtBlockUsage)
+	cmd.Flags().Var(&network, networkF, networkUsage)
+	cmd.Flags().String(httpHostF, defaulHost, httpHostUsage)
+	cmd.Flags().Uint16(httpPortF, defaultHTTPPort, httpPortUsage)
+	cmd.Flags().Uint(maxVMsF, uint(defaultMaxVMs), maxVMsUsage)
+	cmd.Flags().Uint(maxVMQueueF, 2*uint(defaultMaxVMs), maxVMQueueUsage)
+	cmd.Flags().Uint(callMaxStepsF, defaultCallMaxSteps, callMaxStepsUsage)
+	cmd.Flags().String(logLevelF, utils.INFO.String(), logLevelFlagUsage)
+	cmd.Flags().String(versionedConstantsFileF, defaultVersionedConstantsFile, versionedConstantsFileUsage)
+	return cmd
+}
+
+func runFork(cmd *cobra.Command, network *utils.Network) error {
+	flags := cmd.Flags()
+	if !flags.Changed(forkUpstreamF) || !flags.Changed(forkAtBlockF) {
+		return fmt.Errorf("--%v and --%v are required", forkUpstreamF, forkAtBlockF)
+	}
+	cfg := node.ForkConfig{Network: *network}
+
+	var err error
+	if cfg.Upstream, err = flags.GetString(forkUpstreamF); err != nil {
+		return err
+	}
+	if cfg.AtBlock, err = flags.GetUint64(forkAtBlockF); err != nil {
+		return err
+	}
+	if cfg.HTTPHost, err = flags.GetString(httpHostF); err != nil {
+		return err
+	}
+	if cfg.HTTPPort, err = flags.GetUint16(httpPortF); err != nil {
+		return err
+	}
+	if cfg.MaxVMs, err = flags.GetUint(maxVMsF); err != nil {
+		return err
+	}
+	if cfg.MaxVMQueue, err = flags.GetUint(maxVMQueueF); err != nil {
+		return err
+	}
+	if cfg.RPCCallMaxSteps, err = flags.GetUint(callMaxStepsF); err != nil {
+		return err
+	}
+
+	versionedConstantsFile, err := flags.GetString(versionedConstantsFileF)
+	if err != nil {
+		return err
+	}
+	if versionedConstantsFile != "" {
+		if err = vm.SetVersionedConstants(versionedConstantsFile); err != nil {
+			return fmt.Errorf("failed to set versioned constants: %w", err)
+		}
+	}
+
+	logLevelFlag, err := flags.GetString(logLevelF)
+	if err != nil {
+		return err
+	}
+	logLevel := utils.NewLogLevel(utils.INFO)
+	if err = logLevel.Set(logLevelFlag); err != nil {
+		return err
+	}
+	log, err := utils.NewZapLogger(logLevel, false)
+	if err != nil {
+		return err
+	}
+
+	fork, err := node.NewFork(cmd.Context(), &cfg, Version, log)
+	if err != nil {
+		return err
+	}
+	fork.Run(cmd.Context())
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 	_ "github.com/NethermindEth/juno/jemalloc"
 	"github.com/NethermindEth/juno/node"
 	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cobra"
@@ -68,12 +69,28 @@ const (
 	grpcPortF               = "grpc-port"
 	maxVMsF                 = "max-vms"
 	maxVMQueueF             = "max-vm-queue"
+	vmClassCacheSizeF       = "vm-class-cache-size"
+	vmClassCacheWarmBlocksF = "vm-class-cache-warm-blocks"
+	vmBackendF              = "vm-backend"
+	vmShadowBackendF        = "vm-shadow-backend"
+	vmShadowConstantsFileF  = "vm-shadow-versioned-constants-file"
 	remoteDBF               = "remote-db"
 	rpcMaxBlockScanF        = "rpc-max-block-scan"
 	dbCacheSizeF            = "db-cache-size"
 	dbMaxHandlesF           = "db-max-handles"
 	gwAPIKeyF               = "gw-api-key" //nolint: gosec
 	gwTimeoutF              = "gw-timeout" //nolint: gosec
+	syncRPCURLF             = "sync-rpc-url"
+	syncFeederURLsF         = "sync-feeder-urls"
+	syncQuorumF             = "sync-quorum"
+	syncHedgeDelayF         = "sync-hedge-delay"
+	syncBatchSizeF          = "sync-batch-size"
+	syncFinalityF           = "sync-finality"
+	syncUpstreamWSF         = "sync-upstream-ws"
+	checkpointF             = "checkpoint"
+	backfillF               = "backfill"
+	feederCacheDirF         = "feeder-cache-dir"
+	feederCacheSizeF        = "feeder-cache-size"
 	cnNameF                 = "cn-name"
 	cnFeederURLF            = "cn-feeder-url"
 	cnGatewayURLF           = "cn-gateway-url"
@@ -82,6 +99,18 @@ const (
 	cnCoreContractAddressF  = "cn-core-contract-address"
 	cnUnverifiableRangeF    = "cn-unverifiable-range"
 	callMaxStepsF           = "rpc-call-max-steps"
+	rpcResponseCacheSizeF   = "rpc-response-cache-size"
+	rpcCompressionF         = "rpc-compression"
+	rpcCompressThresholdF   = "rpc-compression-threshold"
+	rpcMethodTimeoutsF      = "rpc-method-timeouts"
+	rpcMaxResponseSizeF     = "rpc-max-response-size"
+	rpcAuditLogF            = "rpc-audit-log"
+	rpcAuditLogMaxSizeF     = "rpc-audit-log-max-size"
+	rpcAuditLogMaxBackupsF  = "rpc-audit-log-max-backups"
+	rpcAuditSampleRateF     = "rpc-audit-sample-rate"
+	rpcAuditRedactParamsF   = "rpc-audit-redact-params"
+	rpcAuditParamsMaxSizeF  = "rpc-audit-params-max-size"
+	rpcAuditAPIKeyHeaderF   = "rpc-audit-api-key-header"
 	corsEnableF             = "rpc-cors-enable"
 	versionedConstantsFileF = "versioned-constants-file"
 	pluginPathF             = "plugin-path"
@@ -122,7 +151,31 @@ const (
 	defaultCNL2ChainID              = ""
 	defaultCNCoreContractAddressStr = ""
 	defaultCallMaxSteps             = 4_000_000
+	defaultVMClassCacheSize         = 256
+	defaultVMClassCacheWarmBlocks   = 100
+	defaultRPCResponseCacheSize     = 0
+	defaultRPCCompression           = false
+	defaultRPCCompressThreshold     = 1024
+	defaultRPCMaxResponseSize       = 0
+	defaultRPCAuditLog              = ""
+	defaultRPCAuditLogMaxSize       = 100
+	defaultRPCAuditLogMaxBackups    = 10
+	defaultRPCAuditSampleRate       = 1.0
+	defaultRPCAuditRedactParams     = false
+	defaultRPCAuditParamsMaxSize    = 1024
+	defaultRPCAuditAPIKeyHeader     = ""
 	defaultGwTimeout                = 5 * time.Second
+	defaultSyncRPCURL               = ""
+	defaultSyncFeederURLs           = ""
+	defaultSyncQuorum               = 0
+	defaultSyncHedgeDelay           = 0
+	defaultSyncBatchSize            = 0
+	defaultSyncFinality             = "l2"
+	defaultSyncUpstreamWS           = ""
+	defaultCheckpoint               = ""
+	defaultBackfill                 = false
+	defaultFeederCacheDir           = ""
+	defaultFeederCacheSize          = 4096
 	defaultCorsEnable               = false
 	defaultVersionedConstantsFile   = ""
 	defaultPluginPath               = ""
@@ -160,15 +213,19 @@ const (
 		"These peers can be either Feeder or regular nodes."
 	p2pFeederNodeUsage = "EXPERIMENTAL: Run juno as a feeder node which will only sync from feeder gateway and gossip the new" +
 		" blocks to the network."
-	p2pPrivateKeyUsage   = "EXPERIMENTAL: Hexadecimal representation of a private key on the Ed25519 elliptic curve."
-	metricsUsage         = "Enables the Prometheus metrics endpoint on the default port."
-	metricsHostUsage     = "The interface on which the Prometheus endpoint will listen for requests."
-	metricsPortUsage     = "The port on which the Prometheus endpoint will listen for requests."
-	grpcUsage            = "Enable the HTTP gRPC server on the default port."
-	grpcHostUsage        = "The interface on which the gRPC server will listen for requests."
-	grpcPortUsage        = "The port on which the gRPC server will listen for requests."
-	maxVMsUsage          = "Maximum number for VM instances to be used for RPC calls concurrently"
-	maxVMQueueUsage      = "Maximum number for requests to queue after reaching max-vms before starting to reject incoming requests"
+	p2pPrivateKeyUsage    = "EXPERIMENTAL: Hexadecimal representation of a private key on the Ed25519 elliptic curve."
+	metricsUsage          = "Enables the Prometheus metrics endpoint on the default port."
+	metricsHostUsage      = "The interface on which the Prometheus endpoint will listen for requests."
+	metricsPortUsage      = "The port on which the Prometheus endpoint will listen for requests."
+	grpcUsage             = "Enable the HTTP gRPC server on the default port."
+	grpcHostUsage         = "The interface on which the gRPC server will listen for requests."
+	grpcPortUsage         = "The port on which the gRPC server will listen for requests."
+	maxVMsUsage           = "Maximum number for VM instances to be used for RPC calls concurrently"
+	maxVMQueueUsage       = "Maximum number for requests to queue after reaching max-vms before starting to reject incoming requests"
+	vmClassCacheSizeUsage = "Determines the amount of memory (in megabytes) used to cache classes prepared for the VM. " +
+		"0 disables the cache."
+	vmClassCacheWarmBlocksUsage = "Number of recent blocks whose most used classes are loaded into the VM class cache at startup. " +
+		"0 disables warming the cache."
 	remoteDBUsage        = "gRPC URL of a remote Juno node"
 	rpcMaxBlockScanUsage = "Maximum number of blocks scanned in single starknet_getEvents call"
 	dbCacheSizeUsage     = "Determines the amount of memory (in megabytes) allocated for caching data in the database."
@@ -177,11 +234,53 @@ const (
 	gwTimeoutUsage       = "Timeout for requests made to the gateway"          //nolint: gosec
 	callMaxStepsUsage    = "Maximum number of steps to be executed in starknet_call requests. " +
 		"The upper limit is 4 million steps, and any higher value will still be capped at 4 million."
+	rpcResponseCacheSizeUsage = "Determines the amount of memory (in megabytes) used to cache RPC responses for blocks " +
+		"accepted on L1. 0 disables the cache."
+	rpcCompressionUsage = "Compress RPC responses with gzip or zstd over HTTP and permessage-deflate over WebSocket, " +
+		"if supported by the client."
+	rpcCompressThresholdUsage = "Minimum size (in bytes) of an RPC response before compression is applied."
+	rpcMethodTimeoutsUsage    = "Maximum duration of RPC requests per cancellable method, e.g. starknet_getEvents=10s. Others have no timeout."
+	rpcMaxResponseSizeUsage   = "Maximum size (in megabytes) of a single RPC response, including batches. 0 means no limit."
+	rpcAuditLogUsage          = "Record every RPC request as a line of JSON in the given file, or on standard output if set to \"stdout\". " +
+		"Disabled if empty."
+	rpcAuditLogMaxSizeUsage     = "Size (in megabytes) at which the RPC audit log file is rotated."
+	rpcAuditLogMaxBackupsUsage  = "Number of rotated RPC audit log files to keep. 0 keeps all of them."
+	rpcAuditSampleRateUsage     = "Fraction of RPC requests recorded in the audit log, between 0 and 1."
+	rpcAuditRedactParamsUsage   = "Omit request parameters from the RPC audit log."
+	rpcAuditParamsMaxSizeUsage  = "Size (in bytes) request parameters are truncated to in the RPC audit log. 0 means no limit."
+	rpcAuditAPIKeyHeaderUsage   = "HTTP header holding the client's API key. A fingerprint of the key is recorded in the RPC audit log."
 	corsEnableUsage             = "Enable CORS on RPC endpoints"
 	versionedConstantsFileUsage = "Use custom versioned constants from provided file"
 	pluginPathUsage             = "Path to the plugin .so file"
 	logHostUsage                = "The interface on which the log level HTTP server will listen for requests."
 	logPortUsage                = "The port on which the log level HTTP server will listen for requests."
+	vmBackendUsage              = "VM implementation used to execute transactions and calls."
+	vmShadowConstantsFileUsage  = "Use custom versioned constants from provided file for the shadow VM only."
+	vmShadowBackendUsage        = "VM implementation run alongside the VM on the same calls and executions. " +
+		"Differences in their results are logged and counted in the metrics, only the results of the VM are returned. " +
+		"Disabled if empty."
+	syncRPCURLUsage = "Comma-separated URLs of Starknet JSON-RPC 0.8 endpoints to sync from instead of the feeder gateway, " +
+		"e.g. http://localhost:6060/v0_8. Blocks synced this way have no signatures."
+	syncFeederURLsUsage = "Comma-separated URLs of additional feeder gateways to sync from. " +
+		"Requests fail over between all the sync sources, healthy ones first."
+	syncQuorumUsage = "Number of sync sources that have to agree on the hash of a block before it is stored. " +
+		"0 or 1 disables the quorum."
+	syncHedgeDelayUsage = "Time after which a request to a sync source is also sent to the next source, using the first answer. " +
+		"0 disables hedged requests."
+	syncBatchSizeUsage = "Maximum number of blocks sharing a single database transaction while catching up with the chain. " +
+		"Every block is still verified and its state applied on its own. 0 or 1 stores blocks one at a time."
+	syncFinalityUsage = "Status blocks need to reach before they are stored: l2 or l1. With l1, only blocks accepted on L1 " +
+		"are stored and announced, so they are never reorged. Requires L1 verification."
+	syncUpstreamWSUsage = "WebSocket URL of an upstream Starknet RPC node. Its starknet_subscribeNewHeads notifications " +
+		"trigger fetching the latest and the pending block right away instead of at the next poll."
+	checkpointUsage = "Trusted block, as <number>:<hash>, to verify a database restored from a snapshot from, instead of " +
+		"genesis. The database must contain the block. Blocks before it are not verified and are not served."
+	backfillUsage = "Download the blocks before the checkpoint in the background, down to genesis, verifying them against " +
+		"the hashes of the blocks after them. The state at these blocks is not backfilled."
+	feederCacheDirUsage = "Directory to cache the feeder responses that never change in: classes, compiled classes and blocks " +
+		"accepted on L1, so that syncing again mostly reads from disk. Each network has its own subdirectory. " +
+		"Empty disables the cache."
+	feederCacheSizeUsage = "Maximum size of the feeder response cache in megabytes. The least recently used responses are evicted."
 )
 
 var Version string
@@ -362,6 +461,11 @@ func NewCmd(config *node.Config, run func(*cobra.Command, []string) error) *cobr
 	junoCmd.Flags().Uint16(grpcPortF, defaultGRPCPort, grpcPortUsage)
 	junoCmd.Flags().Uint(maxVMsF, uint(defaultMaxVMs), maxVMsUsage)
 	junoCmd.Flags().Uint(maxVMQueueF, 2*uint(defaultMaxVMs), maxVMQueueUsage)
+	junoCmd.Flags().Uint(vmClassCacheSizeF, defaultVMClassCacheSize, vmClassCacheSizeUsage)
+	junoCmd.Flags().Uint(vmClassCacheWarmBlocksF, defaultVMClassCacheWarmBlocks, vmClassCacheWarmBlocksUsage)
+	junoCmd.Flags().String(vmBackendF, vm.RustBackend, vmBackendUsage)
+	junoCmd.Flags().String(vmShadowBackendF, "", vmShadowBackendUsage)
+	junoCmd.Flags().String(vmShadowConstantsFileF, "", vmShadowConstantsFileUsage)
 	junoCmd.Flags().String(remoteDBF, defaultRemoteDB, remoteDBUsage)
 	junoCmd.Flags().Uint(rpcMaxBlockScanF, defaultRPCMaxBlockScan, rpcMaxBlockScanUsage)
 	junoCmd.Flags().Uint(dbCacheSizeF, defaultCacheSizeMb, dbCacheSizeUsage)
@@ -370,7 +474,30 @@ func NewCmd(config *node.Config, run func(*cobra.Command, []string) error) *cobr
 	junoCmd.MarkFlagsRequiredTogether(cnNameF, cnFeederURLF, cnGatewayURLF, cnL1ChainIDF, cnL2ChainIDF, cnCoreContractAddressF, cnUnverifiableRangeF) //nolint:lll
 	junoCmd.MarkFlagsMutuallyExclusive(networkF, cnNameF)
 	junoCmd.Flags().Uint(callMaxStepsF, defaultCallMaxSteps, callMaxStepsUsage)
+	junoCmd.Flags().Uint(rpcResponseCacheSizeF, defaultRPCResponseCacheSize, rpcResponseCacheSizeUsage)
+	junoCmd.Flags().Bool(rpcCompressionF, defaultRPCCompression, rpcCompressionUsage)
+	junoCmd.Flags().Uint(rpcCompressThresholdF, defaultRPCCompressThreshold, rpcCompressThresholdUsage)
+	junoCmd.Flags().StringToString(rpcMethodTimeoutsF, nil, rpcMethodTimeoutsUsage)
+	junoCmd.Flags().Uint(rpcMaxResponseSizeF, defaultRPCMaxResponseSize, rpcMaxResponseSizeUsage)
+	junoCmd.Flags().String(rpcAuditLogF, defaultRPCAuditLog, rpcAuditLogUsage)
+	junoCmd.Flags().Uint(rpcAuditLogMaxSizeF, defaultRPCAuditLogMaxSize, rpcAuditLogMaxSizeUsage)
+	junoCmd.Flags().Uint(rpcAuditLogMaxBackupsF, defaultRPCAuditLogMaxBackups, rpcAuditLogMaxBackupsUsage)
+	junoCmd.Flags().Float64(rpcAuditSampleRateF, defaultRPCAuditSampleRate, rpcAuditSampleRateUsage)
+	junoCmd.Flags().Bool(rpcAuditRedactParamsF, defaultRPCAuditRedactParams, rpcAuditRedactParamsUsage)
+	junoCmd.Flags().Uint(rpcAuditParamsMaxSizeF, defaultRPCAuditParamsMaxSize, rpcAuditParamsMaxSizeUsage)
+	junoCmd.Flags().String(rpcAuditAPIKeyHeaderF, defaultRPCAuditAPIKeyHeader, rpcAuditAPIKeyHeaderUsage)
 	junoCmd.Flags().Duration(gwTimeoutF, defaultGwTimeout, gwTimeoutUsage)
+	junoCmd.Flags().String(syncRPCURLF, defaultSyncRPCURL, syncRPCURLUsage)
+	junoCmd.Flags().String(syncFeederURLsF, defaultSyncFeederURLs, syncFeederURLsUsage)
+	junoCmd.Flags().Uint(syncQuorumF, defaultSyncQuorum, syncQuorumUsage)
+	junoCmd.Flags().Duration(syncHedgeDelayF, defaultSyncHedgeDelay, syncHedgeDelayUsage)
+	junoCmd.Flags().Uint(syncBatchSizeF, defaultSyncBatchSize, syncBatchSizeUsage)
+	junoCmd.Flags().String(syncFinalityF, defaultSyncFinality, syncFinalityUsage)
+	junoCmd.Flags().String(syncUpstreamWSF, defaultSyncUpstreamWS, syncUpstreamWSUsage)
+	junoCmd.Flags().String(checkpointF, defaultCheckpoint, checkpointUsage)
+	junoCmd.Flags().Bool(backfillF, defaultBackfill, backfillUsage)
+	junoCmd.Flags().String(feederCacheDirF, defaultFeederCacheDir, feederCacheDirUsage)
+	junoCmd.Flags().Uint(feederCacheSizeF, defaultFeederCacheSize, feederCacheSizeUsage)
 	junoCmd.Flags().Bool(corsEnableF, defaultCorsEnable, corsEnableUsage)
 	junoCmd.Flags().String(versionedConstantsFileF, defaultVersionedConstantsFile, versionedConstantsFileUsage)
 	junoCmd.MarkFlagsMutuallyExclusive(p2pFeederNodeF, p2pPeersF)
@@ -378,7 +505,8 @@ func NewCmd(config *node.Config, run func(*cobra.Command, []string) error) *cobr
 	junoCmd.Flags().String(logHostF, defaulHost, logHostUsage)
 	junoCmd.Flags().Uint16(logPortF, defaultLogPort, logPortUsage)
 
-	junoCmd.AddCommand(GenP2PKeyPair(), DBCmd(defaultDBPath))
+	junoCmd.AddCommand(GenP2PKeyPair(), DBCmd(defaultDBPath), VerifyExecutionCmd(defaultDBPath), ForkCmd(),
+		ProfileCmd(defaultDBPath), ImportCmd(defaultDBPath), ExportBlocksCmd(defaultDBPath))
 
 	return junoCmd
 }
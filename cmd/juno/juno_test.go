@@ -62,6 +62,17 @@ func TestConfigPrecedence(t *testing.T) {
 	defaultMaxCacheSize := uint(1024)
 	defaultMaxHandles := 1024
 	defaultCallMaxSteps := uint(4_000_000)
+	defaultRPCCompressionThreshold := uint(1024)
+	defaultRPCMethodTimeouts := map[string]time.Duration{}
+	defaultRPCAuditLogMaxSize := uint(100)
+	defaultVMClassCacheSize := uint(256)
+	defaultVMClassCacheWarmBlocks := uint(100)
+	defaultVMBackend := "rust"
+	defaultSyncFinality := "l2"
+	defaultFeederCacheSize := uint(4096)
+	defaultRPCAuditLogMaxBackups := uint(10)
+	defaultRPCAuditSampleRate := 1.0
+	defaultRPCAuditParamsMaxSize := uint(1024)
 	defaultGwTimeout := 5 * time.Second
 
 	tests := map[string]struct {
@@ -82,35 +93,46 @@ func TestConfigPrecedence(t *testing.T) {
 				"--cn-core-contract-address", "0xc662c410C0ECf747543f5bA90660f6ABeBD9C8c4",
 			},
 			expectedConfig: &node.Config{
-				LogLevel:            "debug",
-				HTTP:                defaultHTTP,
-				HTTPHost:            "0.0.0.0",
-				HTTPPort:            4576,
-				Websocket:           defaultWS,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				DatabasePath:        "/home/.juno",
-				Network:             defaultCustomNetwork,
-				Pprof:               true,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				Colour:              defaultColour,
-				PendingPollInterval: defaultPendingPollInterval,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                "debug",
+				HTTP:                    defaultHTTP,
+				HTTPHost:                "0.0.0.0",
+				HTTPPort:                4576,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            "/home/.juno",
+				Network:                 defaultCustomNetwork,
+				Pprof:                   true,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"custom network config file": {
@@ -129,103 +151,136 @@ cn-core-contract-address: 0xc662c410C0ECf747543f5bA90660f6ABeBD9C8c4
 cn-unverifiable-range: [0,10]
 `,
 			expectedConfig: &node.Config{
-				LogLevel:            "debug",
-				HTTP:                defaultHTTP,
-				HTTPHost:            "0.0.0.0",
-				HTTPPort:            4576,
-				Websocket:           defaultWS,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				DatabasePath:        "/home/.juno",
-				Network:             defaultCustomNetwork,
-				Pprof:               true,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				Colour:              defaultColour,
-				PendingPollInterval: defaultPendingPollInterval,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                "debug",
+				HTTP:                    defaultHTTP,
+				HTTPHost:                "0.0.0.0",
+				HTTPPort:                4576,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            "/home/.juno",
+				Network:                 defaultCustomNetwork,
+				Pprof:                   true,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"default config with no flags": {
 			inputArgs: []string{""},
 			expectedConfig: &node.Config{
-				LogLevel:            defaultLogLevel,
-				HTTP:                defaultHTTP,
-				HTTPHost:            defaultHost,
-				HTTPPort:            defaultHTTPPort,
-				Websocket:           defaultWS,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				DatabasePath:        defaultDBPath,
-				Network:             defaultNetwork,
-				Pprof:               defaultPprof,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				Colour:              defaultColour,
-				PendingPollInterval: defaultPendingPollInterval,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                defaultLogLevel,
+				HTTP:                    defaultHTTP,
+				HTTPHost:                defaultHost,
+				HTTPPort:                defaultHTTPPort,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				DatabasePath:            defaultDBPath,
+				Network:                 defaultNetwork,
+				Pprof:                   defaultPprof,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"config file path is empty string": {
 			inputArgs: []string{"--config", ""},
 			expectedConfig: &node.Config{
-				LogLevel:            defaultLogLevel,
-				HTTP:                defaultHTTP,
-				HTTPHost:            defaultHost,
-				HTTPPort:            defaultHTTPPort,
-				Websocket:           defaultWS,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				DatabasePath:        defaultDBPath,
-				Network:             defaultNetwork,
-				Pprof:               defaultPprof,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				Colour:              defaultColour,
-				PendingPollInterval: defaultPendingPollInterval,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                defaultLogLevel,
+				HTTP:                    defaultHTTP,
+				HTTPHost:                defaultHost,
+				HTTPPort:                defaultHTTPPort,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            defaultDBPath,
+				Network:                 defaultNetwork,
+				Pprof:                   defaultPprof,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"config file doesn't exist": {
@@ -236,35 +291,46 @@ cn-unverifiable-range: [0,10]
 			cfgFile:         true,
 			cfgFileContents: "\n",
 			expectedConfig: &node.Config{
-				LogLevel:            defaultLogLevel,
-				HTTP:                defaultHTTP,
-				HTTPHost:            defaultHost,
-				HTTPPort:            defaultHTTPPort,
-				Websocket:           defaultWS,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				Network:             defaultNetwork,
-				Colour:              defaultColour,
-				PendingPollInterval: defaultPendingPollInterval,
-				Pprof:               defaultPprof,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				DatabasePath:        defaultDBPath,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                defaultLogLevel,
+				HTTP:                    defaultHTTP,
+				HTTPHost:                defaultHost,
+				HTTPPort:                defaultHTTPPort,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				Network:                 defaultNetwork,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				Pprof:                   defaultPprof,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				DatabasePath:            defaultDBPath,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"config file with all settings but without any other flags": {
@@ -277,35 +343,46 @@ network: sepolia
 pprof: true
 `,
 			expectedConfig: &node.Config{
-				LogLevel:            "debug",
-				HTTP:                defaultHTTP,
-				HTTPHost:            "0.0.0.0",
-				HTTPPort:            4576,
-				Websocket:           defaultWS,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				DatabasePath:        "/home/.juno",
-				Network:             utils.Sepolia,
-				Pprof:               true,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				Colour:              defaultColour,
-				PendingPollInterval: defaultPendingPollInterval,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                "debug",
+				HTTP:                    defaultHTTP,
+				HTTPHost:                "0.0.0.0",
+				HTTPPort:                4576,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            "/home/.juno",
+				Network:                 utils.Sepolia,
+				Pprof:                   true,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"config file with some settings but without any other flags": {
@@ -315,35 +392,46 @@ http-host: 0.0.0.0
 http-port: 4576
 `,
 			expectedConfig: &node.Config{
-				LogLevel:            "debug",
-				HTTP:                defaultHTTP,
-				HTTPHost:            "0.0.0.0",
-				HTTPPort:            4576,
-				Websocket:           defaultWS,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				DatabasePath:        defaultDBPath,
-				Network:             defaultNetwork,
-				Pprof:               defaultPprof,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				Colour:              defaultColour,
-				PendingPollInterval: defaultPendingPollInterval,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                "debug",
+				HTTP:                    defaultHTTP,
+				HTTPHost:                "0.0.0.0",
+				HTTPPort:                4576,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            defaultDBPath,
+				Network:                 defaultNetwork,
+				Pprof:                   defaultPprof,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"all flags without config file": {
@@ -352,35 +440,46 @@ http-port: 4576
 				"--db-path", "/home/.juno", "--network", "sepolia-integration", "--pprof", "--db-cache-size", "1024",
 			},
 			expectedConfig: &node.Config{
-				LogLevel:            "debug",
-				HTTP:                defaultHTTP,
-				HTTPHost:            "0.0.0.0",
-				HTTPPort:            4576,
-				Websocket:           defaultWS,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				DatabasePath:        "/home/.juno",
-				Network:             utils.SepoliaIntegration,
-				Pprof:               true,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				Colour:              defaultColour,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				PendingPollInterval: defaultPendingPollInterval,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                "debug",
+				HTTP:                    defaultHTTP,
+				HTTPHost:                "0.0.0.0",
+				HTTPPort:                4576,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            "/home/.juno",
+				Network:                 utils.SepoliaIntegration,
+				Pprof:                   true,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				PendingPollInterval:     defaultPendingPollInterval,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"some flags without config file": {
@@ -389,35 +488,46 @@ http-port: 4576
 				"--network", "sepolia",
 			},
 			expectedConfig: &node.Config{
-				LogLevel:            "debug",
-				HTTP:                defaultHTTP,
-				HTTPHost:            "0.0.0.0",
-				HTTPPort:            4576,
-				Websocket:           defaultWS,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				DatabasePath:        "/home/.juno",
-				Network:             utils.Sepolia,
-				Pprof:               defaultPprof,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				Colour:              defaultColour,
-				PendingPollInterval: defaultPendingPollInterval,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                "debug",
+				HTTP:                    defaultHTTP,
+				HTTPHost:                "0.0.0.0",
+				HTTPPort:                4576,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            "/home/.juno",
+				Network:                 utils.Sepolia,
+				Pprof:                   defaultPprof,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"all setting set in both config file and flags": {
@@ -450,35 +560,46 @@ db-cache-size: 1024
 				"--db-cache-size", "9",
 			},
 			expectedConfig: &node.Config{
-				LogLevel:            "error",
-				HTTP:                true,
-				HTTPHost:            "127.0.0.1",
-				HTTPPort:            4577,
-				Websocket:           true,
-				WebsocketHost:       "127.0.0.1",
-				WebsocketPort:       4577,
-				Metrics:             true,
-				MetricsHost:         "127.0.0.1",
-				MetricsPort:         4577,
-				GRPC:                true,
-				GRPCHost:            "127.0.0.1",
-				GRPCPort:            4577,
-				DatabasePath:        "/home/flag/.juno",
-				Network:             utils.Mainnet,
-				Pprof:               true,
-				PprofHost:           "0.0.0.0",
-				PprofPort:           6064,
-				Colour:              defaultColour,
-				PendingPollInterval: time.Millisecond,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         9,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                "error",
+				HTTP:                    true,
+				HTTPHost:                "127.0.0.1",
+				HTTPPort:                4577,
+				Websocket:               true,
+				WebsocketHost:           "127.0.0.1",
+				WebsocketPort:           4577,
+				Metrics:                 true,
+				MetricsHost:             "127.0.0.1",
+				MetricsPort:             4577,
+				GRPC:                    true,
+				GRPCHost:                "127.0.0.1",
+				GRPCPort:                4577,
+				DatabasePath:            "/home/flag/.juno",
+				Network:                 utils.Mainnet,
+				Pprof:                   true,
+				PprofHost:               "0.0.0.0",
+				PprofPort:               6064,
+				Colour:                  defaultColour,
+				PendingPollInterval:     time.Millisecond,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             9,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"some setting set in both config file and flags": {
@@ -490,35 +611,46 @@ network: sepolia
 `,
 			inputArgs: []string{"--db-path", "/home/flag/.juno"},
 			expectedConfig: &node.Config{
-				LogLevel:            "warn",
-				HTTP:                defaultHTTP,
-				HTTPHost:            "0.0.0.0",
-				HTTPPort:            4576,
-				Websocket:           defaultWS,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				DatabasePath:        "/home/flag/.juno",
-				Network:             utils.Sepolia,
-				Pprof:               defaultPprof,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				Colour:              defaultColour,
-				PendingPollInterval: defaultPendingPollInterval,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                "warn",
+				HTTP:                    defaultHTTP,
+				HTTPHost:                "0.0.0.0",
+				HTTPPort:                4576,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            "/home/flag/.juno",
+				Network:                 utils.Sepolia,
+				Pprof:                   defaultPprof,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"some setting set in default, config file and flags": {
@@ -526,140 +658,284 @@ network: sepolia
 			cfgFileContents: `network: sepolia-integration`,
 			inputArgs:       []string{"--db-path", "/home/flag/.juno", "--pprof"},
 			expectedConfig: &node.Config{
-				LogLevel:            defaultLogLevel,
-				HTTP:                defaultHTTP,
-				HTTPHost:            defaultHost,
-				HTTPPort:            defaultHTTPPort,
-				Websocket:           defaultWS,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				DatabasePath:        "/home/flag/.juno",
-				Network:             utils.SepoliaIntegration,
-				Pprof:               true,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				Colour:              defaultColour,
-				PendingPollInterval: defaultPendingPollInterval,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                defaultLogLevel,
+				HTTP:                    defaultHTTP,
+				HTTPHost:                defaultHost,
+				HTTPPort:                defaultHTTPPort,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            "/home/flag/.juno",
+				Network:                 utils.SepoliaIntegration,
+				Pprof:                   true,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"only set env variables": {
 			env: []string{"JUNO_HTTP_PORT", "8080", "JUNO_WS", "true", "JUNO_HTTP_HOST", "0.0.0.0"},
 			expectedConfig: &node.Config{
-				LogLevel:            defaultLogLevel,
-				HTTP:                defaultHTTP,
-				HTTPHost:            "0.0.0.0",
-				HTTPPort:            8080,
-				Websocket:           true,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				DatabasePath:        defaultDBPath,
-				Network:             defaultNetwork,
-				Pprof:               defaultPprof,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				Colour:              defaultColour,
-				PendingPollInterval: defaultPendingPollInterval,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                defaultLogLevel,
+				HTTP:                    defaultHTTP,
+				HTTPHost:                "0.0.0.0",
+				HTTPPort:                8080,
+				Websocket:               true,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            defaultDBPath,
+				Network:                 defaultNetwork,
+				Pprof:                   defaultPprof,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"some setting set in both env variables and flags": {
 			env:       []string{"JUNO_DB_PATH", "/home/env/.juno"},
 			inputArgs: []string{"--db-path", "/home/flag/.juno"},
 			expectedConfig: &node.Config{
-				LogLevel:            defaultLogLevel,
-				HTTP:                defaultHTTP,
-				HTTPHost:            defaultHost,
-				HTTPPort:            defaultHTTPPort,
-				Websocket:           defaultWS,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				DatabasePath:        "/home/flag/.juno",
-				Network:             defaultNetwork,
-				Pprof:               defaultPprof,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				Colour:              defaultColour,
-				PendingPollInterval: defaultPendingPollInterval,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                defaultLogLevel,
+				HTTP:                    defaultHTTP,
+				HTTPHost:                defaultHost,
+				HTTPPort:                defaultHTTPPort,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            "/home/flag/.juno",
+				Network:                 defaultNetwork,
+				Pprof:                   defaultPprof,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
 			},
 		},
 		"some setting set in both env variables and config file": {
 			cfgFileContents: `db-path: /home/file/.juno`,
 			env:             []string{"JUNO_DB_PATH", "/home/env/.juno", "JUNO_GW_API_KEY", "apikey"},
 			expectedConfig: &node.Config{
-				LogLevel:            defaultLogLevel,
-				HTTP:                defaultHTTP,
-				HTTPHost:            defaultHost,
-				HTTPPort:            defaultHTTPPort,
-				Websocket:           defaultWS,
-				WebsocketHost:       defaultHost,
-				WebsocketPort:       defaultWSPort,
-				GRPC:                defaultGRPC,
-				GRPCHost:            defaultHost,
-				GRPCPort:            defaultGRPCPort,
-				Metrics:             defaultMetrics,
-				MetricsHost:         defaultHost,
-				MetricsPort:         defaultMetricsPort,
-				DatabasePath:        "/home/env/.juno",
-				Network:             defaultNetwork,
-				Pprof:               defaultPprof,
-				PprofHost:           defaultHost,
-				PprofPort:           defaultPprofPort,
-				Colour:              defaultColour,
-				PendingPollInterval: defaultPendingPollInterval,
-				MaxVMs:              defaultMaxVMs,
-				MaxVMQueue:          2 * defaultMaxVMs,
-				RPCMaxBlockScan:     defaultRPCMaxBlockScan,
-				DBCacheSize:         defaultMaxCacheSize,
-				GatewayAPIKey:       "apikey",
-				DBMaxHandles:        defaultMaxHandles,
-				RPCCallMaxSteps:     defaultCallMaxSteps,
-				GatewayTimeout:      defaultGwTimeout,
-				LogHost:             defaultHost,
-				LogPort:             0,
+				LogLevel:                defaultLogLevel,
+				HTTP:                    defaultHTTP,
+				HTTPHost:                defaultHost,
+				HTTPPort:                defaultHTTPPort,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            "/home/env/.juno",
+				Network:                 defaultNetwork,
+				Pprof:                   defaultPprof,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				GatewayAPIKey:           "apikey",
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts:       defaultRPCMethodTimeouts,
+				RPCAuditLogMaxSize:      defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups:   defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:      defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize:   defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:          defaultGwTimeout,
+				LogHost:                 defaultHost,
+				LogPort:                 0,
+			},
+		},
+		"rpc method timeouts in config file": {
+			cfgFile: true,
+			cfgFileContents: `rpc-method-timeouts:
+  starknet_getEvents: 10s
+  starknet_simulateTransactions: 1m
+`,
+			expectedConfig: &node.Config{
+				LogLevel:                defaultLogLevel,
+				HTTP:                    defaultHTTP,
+				HTTPHost:                defaultHost,
+				HTTPPort:                defaultHTTPPort,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            defaultDBPath,
+				Network:                 defaultNetwork,
+				Pprof:                   defaultPprof,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts: map[string]time.Duration{
+					"starknet_getevents":            10 * time.Second,
+					"starknet_simulatetransactions": time.Minute,
+				},
+				RPCAuditLogMaxSize:    defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups: defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:    defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize: defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:        defaultGwTimeout,
+				LogHost:               defaultHost,
+				LogPort:               0,
+			},
+		},
+		"rpc method timeouts flag": {
+			inputArgs: []string{"--rpc-method-timeouts", "starknet_getEvents=10s,starknet_call=500ms"},
+			expectedConfig: &node.Config{
+				LogLevel:                defaultLogLevel,
+				HTTP:                    defaultHTTP,
+				HTTPHost:                defaultHost,
+				HTTPPort:                defaultHTTPPort,
+				Websocket:               defaultWS,
+				WebsocketHost:           defaultHost,
+				WebsocketPort:           defaultWSPort,
+				GRPC:                    defaultGRPC,
+				GRPCHost:                defaultHost,
+				GRPCPort:                defaultGRPCPort,
+				Metrics:                 defaultMetrics,
+				MetricsHost:             defaultHost,
+				MetricsPort:             defaultMetricsPort,
+				DatabasePath:            defaultDBPath,
+				Network:                 defaultNetwork,
+				Pprof:                   defaultPprof,
+				PprofHost:               defaultHost,
+				PprofPort:               defaultPprofPort,
+				Colour:                  defaultColour,
+				PendingPollInterval:     defaultPendingPollInterval,
+				MaxVMs:                  defaultMaxVMs,
+				MaxVMQueue:              2 * defaultMaxVMs,
+				VMClassCacheSize:        defaultVMClassCacheSize,
+				VMClassCacheWarmBlocks:  defaultVMClassCacheWarmBlocks,
+				VMBackend:               defaultVMBackend,
+				SyncFinality:            defaultSyncFinality,
+				FeederCacheSize:         defaultFeederCacheSize,
+				RPCMaxBlockScan:         defaultRPCMaxBlockScan,
+				DBCacheSize:             defaultMaxCacheSize,
+				DBMaxHandles:            defaultMaxHandles,
+				RPCCallMaxSteps:         defaultCallMaxSteps,
+				RPCCompressionThreshold: defaultRPCCompressionThreshold,
+				RPCMethodTimeouts: map[string]time.Duration{
+					"starknet_getEvents": 10 * time.Second,
+					"starknet_call":      500 * time.Millisecond,
+				},
+				RPCAuditLogMaxSize:    defaultRPCAuditLogMaxSize,
+				RPCAuditLogMaxBackups: defaultRPCAuditLogMaxBackups,
+				RPCAuditSampleRate:    defaultRPCAuditSampleRate,
+				RPCAuditParamsMaxSize: defaultRPCAuditParamsMaxSize,
+				GatewayTimeout:        defaultGwTimeout,
+				LogHost:               defaultHost,
+				LogPort:               0,
 			},
 		},
 	}
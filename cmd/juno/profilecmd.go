@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/profiler"
+	rpcv8 "github.com/NethermindEth/juno/rpc/v8"
+	"github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	profileFormatF = "format"
+	profileMetricF = "metric"
+	profileOutputF = "output"
+
+	profileFormatUsage = "Format of the profile: json, folded or pprof."
+	profileMetricUsage = "Metric of the folded stacks: steps, l1_gas, l1_data_gas, l2_gas, calldata or calls."
+	profileOutputUsage = "File to write the profile to. Defaults to the standard output."
+)
+
+func ProfileCmd(defaultDBPath string) *cobra.Command {
+	network := utils.Mainnet
+
+	cmd := &cobra.Command{
+		Use:   "profile <transaction hash>",
+		Short: "Profile the execution of a stored transaction",
+		Long: `This command re-executes a transaction stored in the database and aggregates the steps, builtins,
+L1 and L2 gas and calldata of its calls by contract address, class hash and entry point selector. The
+folded and pprof formats can be turned into flamegraphs, e.g. with flamegraph.pl or go tool pprof.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return profileTransaction(cmd, &network, args[0])
+		},
+	}
+
+	cmd.Flags().String(dbPathF, defaultDBPath, dbPathUsage)
+	cmd.Flags().Var(&network, networkF, networkUsage)
+	cmd.Flags().String(profileFormatF, string(rpcv8.ProfileJSON), profileFormatUsage)
+	cmd.Flags().String(profileMetricF, string(profiler.Steps), profileMetricUsage)
+	cmd.Flags().String(profileOutputF, "", profileOutputUsage)
+	cmd.Flags().String(versionedConstantsFileF, defaultVersionedConstantsFile, versionedConstantsFileUsage)
+	return cmd
+}
+
+func profileTransaction(cmd *cobra.Command, network *utils.Network, txHash string) error {
+	hash, err := new(felt.Felt).SetString(txHash)
+	if err != nil {
+		return fmt.Errorf("invalid transaction hash: %v", err)
+	}
+
+	flags := cmd.Flags()
+	var (
+		format rpcv8.ProfileFormat
+		metric profiler.Metric
+	)
+	formatFlag, err := flags.GetString(profileFormatF)
+	if err != nil {
+		return err
+	}
+	if err = format.UnmarshalText([]byte(formatFlag)); err != nil {
+		return err
+	}
+	metricFlag, err := flags.GetString(profileMetricF)
+	if err != nil {
+		return err
+	}
+	if err = metric.UnmarshalText([]byte(metricFlag)); err != nil {
+		return err
+	}
+
+	versionedConstantsFile, err := flags.GetString(versionedConstantsFileF)
+	if err != nil {
+		return err
+	}
+	if versionedConstantsFile != "" {
+		if err = vm.SetVersionedConstants(versionedConstantsFile); err != nil {
+			return fmt.Errorf("failed to set versioned constants: %w", err)
+		}
+	}
+
+	dbPath, err := flags.GetString(dbPathF)
+	if err != nil {
+		return err
+	}
+	database, err := openDB(dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	log, err := utils.NewZapLogger(utils.NewLogLevel(utils.WARN), false)
+	if err != nil {
+		return err
+	}
+	handler := rpcv8.New(blockchain.New(database, network), &sync.NoopSynchronizer{}, vm.New(false, log), Version, log)
+	result, _, rpcErr := handler.ProfileTransaction(cmd.Context(), *hash, format, metric)
+	if rpcErr != nil {
+		return fmt.Errorf("failed to profile transaction: %s", rpcErr.Message)
+	}
+
+	output, err := flags.GetString(profileOutputF)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = cmd.OutOrStdout()
+	if output != "" {
+		var file *os.File
+		if file, err = os.Create(output); err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch format {
+	case rpcv8.ProfileFolded:
+		_, err = io.WriteString(w, result.Folded)
+	case rpcv8.ProfilePProf:
+		_, err = w.Write(result.PProf)
+	default:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(result.Profile)
+	}
+	return err
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/NethermindEth/juno/adapters/vm2core"
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	verifyFromF = "from"
+	verifyToF   = "to"
+
+	verifyFromUsage = "First block to re-execute."
+	verifyToUsage   = "Last block to re-execute. Defaults to the head of the chain."
+)
+
+// ExecutionMismatch is a difference between the result of re-executing a block and what is stored for it.
+// Transaction related fields are only set for receipt mismatches, address and key only for state diff ones.
+type ExecutionMismatch struct {
+	TransactionIndex *int       `json:"transaction_index,omitempty"`
+	TransactionHash  *felt.Felt `json:"transaction_hash,omitempty"`
+	Field            string     `json:"field"`
+	Address          *felt.Felt `json:"address,omitempty"`
+	Key              *felt.Felt `json:"key,omitempty"`
+	Expected         any        `json:"expected"`
+	Actual           any        `json:"actual"`
+}
+
+// BlockVerification is the report line written for every re-executed block.
+type BlockVerification struct {
+	BlockNumber uint64              `json:"block_number"`
+	BlockHash   *felt.Felt          `json:"block_hash"`
+	Error       string              `json:"error,omitempty"`
+	Mismatches  []ExecutionMismatch `json:"mismatches"`
+}
+
+func (v *BlockVerification) Failed() bool {
+	return v.Error != "" || len(v.Mismatches) > 0
+}
+
+func VerifyExecutionCmd(defaultDBPath string) *cobra.Command {
+	network := utils.Mainnet
+
+	cmd := &cobra.Command{
+		Use:   "verify-execution",
+		Short: "Re-execute stored blocks and compare the results",
+		Long: `This command re-executes the transactions of a range of blocks stored in the database and compares
+the fees, events, messages, execution status and state diff with what is stored. A JSON report line is
+written for every block, and the command fails if any block does not match.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return verifyExecution(cmd, &network)
+		},
+	}
+
+	cmd.Flags().String(dbPathF, defaultDBPath, dbPathUsage)
+	cmd.Flags().Var(&network, networkF, networkUsage)
+	cmd.Flags().Uint64(verifyFromF, 0, verifyFromUsage)
+	cmd.Flags().Uint64(verifyToF, 0, verifyToUsage)
+	cmd.Flags().String(versionedConstantsFileF, defaultVersionedConstantsFile, versionedConstantsFileUsage)
+	return cmd
+}
+
+func verifyExecution(cmd *cobra.Command, network *utils.Network) error {
+	dbPath, err := cmd.Flags().GetString(dbPathF)
+	if err != nil {
+		return err
+	}
+
+	from, err := cmd.Flags().GetUint64(verifyFromF)
+	if err != nil {
+		return err
+	}
+
+	versionedConstantsFile, err := cmd.Flags().GetString(versionedConstantsFileF)
+	if err != nil {
+		return err
+	}
+	if versionedConstantsFile != "" {
+		if err = vm.SetVersionedConstants(versionedConstantsFile); err != nil {
+			return fmt.Errorf("failed to set versioned constants: %w", err)
+		}
+	}
+
+	database, err := openDB(dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	chain := blockchain.New(database, network)
+
+	to, err := cmd.Flags().GetUint64(verifyToF)
+	if err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed(verifyToF) {
+		if to, err = chain.Height(); err != nil {
+			return fmt.Errorf("failed to get the chain height: %v", err)
+		}
+	}
+	if from > to {
+		return fmt.Errorf("--%v cannot be greater than --%v", verifyFromF, verifyToF)
+	}
+
+	log, err := utils.NewZapLogger(utils.NewLogLevel(utils.WARN), false)
+	if err != nil {
+		return err
+	}
+	virtualMachine := vm.New(false, log)
+
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	var failed uint64
+	for blockNumber := from; blockNumber <= to; blockNumber++ {
+		report, err := verifyBlock(chain, virtualMachine, blockNumber)
+		if err != nil {
+			return fmt.Errorf("failed to verify block %d: %v", blockNumber, err)
+		}
+		if report.Failed() {
+			failed++
+		}
+		if err = encoder.Encode(report); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d blocks failed verification", failed, to-from+1)
+	}
+	return nil
+}
+
+// verifyBlock re-executes the block on top of its parent state. Errors reading the database are returned, while
+// execution errors are part of the report.
+func verifyBlock(chain *blockchain.Blockchain, virtualMachine vm.VM, blockNumber uint64) (*BlockVerification, error) {
+	block, err := chain.BlockByNumber(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	stateUpdate, err := chain.StateUpdateByNumber(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BlockVerification{
+		BlockNumber: blockNumber,
+		BlockHash:   block.Hash,
+		Mismatches:  []ExecutionMismatch{},
+	}
+
+	state, closer, err := chain.StateAtBlockHash(block.ParentHash)
+	if err != nil {
+		return nil, err
+	}
+	defer closer() //nolint:errcheck
+
+	headState, headCloser, err := chain.HeadState()
+	if err != nil {
+		return nil, err
+	}
+	defer headCloser() //nolint:errcheck
+
+	var classes []core.Class
+	paidFeesOnL1 := []*felt.Felt{}
+	for _, transaction := range block.Transactions {
+		switch tx := transaction.(type) {
+		case *core.DeclareTransaction:
+			class, err := headState.Class(tx.ClassHash)
+			if err != nil {
+				report.Error = fmt.Sprintf("class %s of declare transaction %s: %v", tx.ClassHash, tx.Hash(), err)
+				return report, nil
+			}
+			classes = append(classes, class.Class)
+		case *core.L1HandlerTransaction:
+			paidFeesOnL1 = append(paidFeesOnL1, new(felt.Felt).SetUint64(1))
+		}
+	}
+
+	var blockHashToBeRevealed *felt.Felt
+	if blockNumber >= core.BlockHashLag {
+		header, err := chain.BlockHeaderByNumber(blockNumber - core.BlockHashLag)
+		if err != nil {
+			return nil, err
+		}
+		blockHashToBeRevealed = header.Hash
+	}
+
+	executionResults, err := virtualMachine.Execute(block.Transactions, classes, paidFeesOnL1, &vm.BlockInfo{
+		Header:                block.Header,
+		BlockHashToBeRevealed: blockHashToBeRevealed,
+	}, state, chain.Network(), false, false, false, true)
+	if err != nil {
+		report.Error = err.Error()
+		return report, nil
+	}
+	if len(executionResults.Traces) != len(block.Receipts) || len(executionResults.OverallFees) != len(block.Receipts) {
+		report.Error = fmt.Sprintf("inconsistent lengths: %d receipts, %d traces, %d overall fees",
+			len(block.Receipts), len(executionResults.Traces), len(executionResults.OverallFees))
+		return report, nil
+	}
+
+	stateDiff := core.EmptyStateDiff()
+	if blockHashToBeRevealed != nil {
+		stateDiff.StorageDiffs[*core.BlockHashContract] = map[felt.Felt]*felt.Felt{
+			*new(felt.Felt).SetUint64(blockNumber - core.BlockHashLag): blockHashToBeRevealed,
+		}
+	}
+
+	for idx, receipt := range block.Receipts {
+		trace := &executionResults.Traces[idx]
+		report.Mismatches = append(report.Mismatches,
+			compareReceipt(idx, receipt, executionResults.OverallFees[idx], trace)...)
+
+		txnDiff := vm2core.AdaptStateDiff(trace.StateDiff)
+		stateDiff.Merge(&txnDiff)
+	}
+	report.Mismatches = append(report.Mismatches, compareStateDiff(stateUpdate.StateDiff, &stateDiff)...)
+
+	return report, nil
+}
+
+func executionStatus(reverted bool) string {
+	if reverted {
+		return "REVERTED"
+	}
+	return "SUCCEEDED"
+}
+
+func compareReceipt(idx int, receipt *core.TransactionReceipt, fee *felt.Felt,
+	trace *vm.TransactionTrace,
+) []ExecutionMismatch {
+	var mismatches []ExecutionMismatch
+	mismatch := func(field string, expected, actual any) {
+		mismatches = append(mismatches, ExecutionMismatch{
+			TransactionIndex: &idx,
+			TransactionHash:  receipt.TransactionHash,
+			Field:            field,
+			Expected:         expected,
+			Actual:           actual,
+		})
+	}
+
+	if !receipt.Fee.Equal(fee) {
+		mismatch("fee", receipt.Fee, fee)
+	}
+
+	if reverted := trace.RevertReason() != ""; receipt.Reverted != reverted {
+		mismatch("execution_status", executionStatus(receipt.Reverted), executionStatus(reverted))
+	}
+
+	events := vm2core.AdaptOrderedEvents(trace.AllEvents())
+	if !equalOrEmpty(receipt.Events, events) {
+		mismatch("events", receipt.Events, events)
+	}
+
+	messages := vm2core.AdaptOrderedMessagesToL1(trace.AllMessages())
+	if !equalOrEmpty(receipt.L2ToL1Message, messages) {
+		mismatch("messages", receipt.L2ToL1Message, messages)
+	}
+	return mismatches
+}
+
+// equalOrEmpty is reflect.DeepEqual treating nil and empty slices as equal.
+func equalOrEmpty[T any](a, b []T) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compareStateDiff lists the differences between the stored and re-executed state diffs, ordered by address
+// and key so that reports of the same block can be compared.
+func compareStateDiff(expected, actual *core.StateDiff) []ExecutionMismatch {
+	var mismatches []ExecutionMismatch
+	compareMap := func(field string, expected, actual map[felt.Felt]*felt.Felt, address *felt.Felt) {
+		for _, key := range sortedKeys(expected, actual) {
+			expectedValue, actualValue := expected[key], actual[key]
+			if expectedValue != nil && actualValue != nil && expectedValue.Equal(actualValue) {
+				continue
+			}
+
+			m := ExecutionMismatch{Field: field, Expected: expectedValue, Actual: actualValue}
+			if address != nil {
+				m.Address, m.Key = address, &key
+			} else {
+				m.Address = &key
+			}
+			mismatches = append(mismatches, m)
+		}
+	}
+
+	for _, address := range sortedKeys(expected.StorageDiffs, actual.StorageDiffs) {
+		compareMap("storage", expected.StorageDiffs[address], actual.StorageDiffs[address], &address)
+	}
+	compareMap("nonce", expected.Nonces, actual.Nonces, nil)
+	compareMap("deployed_contract", expected.DeployedContracts, actual.DeployedContracts, nil)
+	compareMap("replaced_class", expected.ReplacedClasses, actual.ReplacedClasses, nil)
+
+	// declared classes are keyed by class hash rather than address
+	for _, classHash := range sortedKeys(expected.DeclaredV1Classes, actual.DeclaredV1Classes) {
+		expectedValue, actualValue := expected.DeclaredV1Classes[classHash], actual.DeclaredV1Classes[classHash]
+		if expectedValue == nil || actualValue == nil || !expectedValue.Equal(actualValue) {
+			mismatches = append(mismatches, ExecutionMismatch{
+				Field:    "declared_class",
+				Key:      &classHash,
+				Expected: expectedValue,
+				Actual:   actualValue,
+			})
+		}
+	}
+
+	expectedV0 := sortedFelts(expected.DeclaredV0Classes)
+	actualV0 := sortedFelts(actual.DeclaredV0Classes)
+	if !slices.Equal(expectedV0, actualV0) {
+		mismatches = append(mismatches, ExecutionMismatch{
+			Field:    "deprecated_declared_classes",
+			Expected: expectedV0,
+			Actual:   actualV0,
+		})
+	}
+	return mismatches
+}
+
+func sortedKeys[V any](maps ...map[felt.Felt]V) []felt.Felt {
+	keys := make(map[felt.Felt]struct{})
+	for _, m := range maps {
+		for key := range m {
+			keys[key] = struct{}{}
+		}
+	}
+
+	sorted := make([]felt.Felt, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	slices.SortFunc(sorted, func(a, b felt.Felt) int { return a.Cmp(&b) })
+	return sorted
+}
+
+func sortedFelts(felts []*felt.Felt) []felt.Felt {
+	sorted := make([]felt.Felt, 0, len(felts))
+	for _, f := range felts {
+		if f != nil {
+			sorted = append(sorted, *f)
+		}
+	}
+	slices.SortFunc(sorted, func(a, b felt.Felt) int { return a.Cmp(&b) })
+	return sorted
+}
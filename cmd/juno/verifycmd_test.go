@@ -0,0 +1,83 @@
+package main_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/clients/feeder"
+	juno "github.com/NethermindEth/juno/cmd/juno"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db/pebble"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyExecutionCmd(t *testing.T) {
+	const syncToBlock = uint64(1)
+	dbPath := prepareExecutableDB(t, &utils.Mainnet, syncToBlock)
+
+	t.Run("from greater than to", func(t *testing.T) {
+		cmd := juno.VerifyExecutionCmd(dbPath)
+		require.NoError(t, cmd.Flags().Set("from", "1"))
+		require.NoError(t, cmd.Flags().Set("to", "0"))
+		require.Error(t, cmd.Execute())
+	})
+
+	t.Run("one report line per block", func(t *testing.T) {
+		cmd := juno.VerifyExecutionCmd(dbPath)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		require.NoError(t, cmd.Execute())
+
+		var blockNumbers []uint64
+		scanner := bufio.NewScanner(&out)
+		scanner.Buffer(nil, 1<<24)
+		for scanner.Scan() {
+			var report juno.BlockVerification
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &report))
+			blockNumbers = append(blockNumbers, report.BlockNumber)
+		}
+		require.NoError(t, scanner.Err())
+		assert.Equal(t, []uint64{0, 1}, blockNumbers)
+	})
+}
+
+// prepareExecutableDB stores the blocks up to syncToBlock along with the classes of the contracts they deploy,
+// so that the blocks can be re-executed.
+func prepareExecutableDB(t *testing.T, network *utils.Network, syncToBlock uint64) string {
+	gw := adaptfeeder.New(feeder.NewTestClient(t, network))
+
+	dbPath := t.TempDir()
+	testDB, err := pebble.New(dbPath)
+	require.NoError(t, err)
+
+	chain := blockchain.New(testDB, network)
+	storedClasses := make(map[felt.Felt]struct{})
+	for blockNumber := uint64(0); blockNumber <= syncToBlock; blockNumber++ {
+		block, err := gw.BlockByNumber(t.Context(), blockNumber)
+		require.NoError(t, err)
+		stateUpdate, err := gw.StateUpdate(t.Context(), blockNumber)
+		require.NoError(t, err)
+
+		newClasses := make(map[felt.Felt]core.Class)
+		for _, classHash := range stateUpdate.StateDiff.DeployedContracts {
+			if _, ok := storedClasses[*classHash]; ok {
+				continue
+			}
+			class, err := gw.Class(t.Context(), classHash)
+			require.NoError(t, err)
+			newClasses[*classHash] = class
+			storedClasses[*classHash] = struct{}{}
+		}
+		require.NoError(t, chain.Store(block, &emptyCommitments, stateUpdate, newClasses))
+	}
+	require.NoError(t, testDB.Close())
+
+	return dbPath
+}
@@ -0,0 +1,20 @@
+package core
+
+import "github.com/NethermindEth/juno/core/felt"
+
+// PendingDelta is the change of the pending block since the previous delta.
+type PendingDelta struct {
+	// Number and ParentHash identify the pending block after the change.
+	Number     uint64
+	ParentHash *felt.Felt
+	// Added are the transactions that joined the pending block, in order, with their receipts and events.
+	Added []PendingTransaction
+	// Retracted are the hashes of the transactions that left the pending block without being included in a
+	// block, for example because they were replaced or reorged out.
+	Retracted []*felt.Felt
+}
+
+type PendingTransaction struct {
+	Transaction Transaction
+	Receipt     *TransactionReceipt
+}
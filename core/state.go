@@ -257,11 +257,17 @@ func (s *State) Update(blockNumber uint64, update *StateUpdate, declaredClasses
 	return s.verifyStateUpdateRoot(update.NewRoot)
 }
 
+// BlockHashLag is how many blocks behind a block is the one whose hash is written to BlockHashContract at its start.
+const BlockHashLag = 10
+
 var (
+	// BlockHashContract is the system contract that maps block numbers to block hashes.
+	BlockHashContract = new(felt.Felt).SetUint64(1)
+
 	systemContractsClassHash = new(felt.Felt).SetUint64(0)
 
 	systemContracts = map[felt.Felt]struct{}{
-		*new(felt.Felt).SetUint64(1): {},
+		*BlockHashContract:           {},
 		*new(felt.Felt).SetUint64(2): {},
 	}
 )
@@ -327,6 +333,23 @@ type DeclaredClass struct {
 	Class Class
 }
 
+// ClassChecker is implemented by the states that can tell whether a class is declared without decoding it.
+type ClassChecker interface {
+	HasClass(classHash *felt.Felt) (bool, error)
+}
+
+// HasClass reports whether the class is declared in state, without decoding it if state is a ClassChecker.
+func HasClass(state StateReader, classHash *felt.Felt) (bool, error) {
+	if checker, ok := state.(ClassChecker); ok {
+		return checker.HasClass(classHash)
+	}
+	_, err := state.Class(classHash)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 func (s *State) putClass(classHash *felt.Felt, class Class, declaredAt uint64) error {
 	classKey := db.Class.Key(classHash.Marshal())
 
@@ -362,6 +385,27 @@ func (s *State) Class(classHash *felt.Felt) (*DeclaredClass, error) {
 	return &class, nil
 }
 
+// HasClass reports whether the class is declared, without decoding the class itself.
+func (s *State) HasClass(classHash *felt.Felt) (bool, error) {
+	_, err := s.classDeclaredAt(classHash)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// classDeclaredAt returns the number of the block the class was declared in.
+func (s *State) classDeclaredAt(classHash *felt.Felt) (uint64, error) {
+	// fields missing from the struct are skipped when decoding, so the class is not decoded
+	var declared struct {
+		At uint64
+	}
+	err := s.txn.Get(db.Class.Key(classHash.Marshal()), func(val []byte) error {
+		return encoder.Unmarshal(val, &declared)
+	})
+	return declared.At, err
+}
+
 func (s *State) updateStorageBuffered(contractAddr *felt.Felt, updateDiff map[felt.Felt]*felt.Felt, blockNumber uint64, logChanges bool) (
 	*db.BufferedTransaction, error,
 ) {
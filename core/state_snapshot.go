@@ -91,6 +91,28 @@ func (s *stateSnapshot) Class(classHash *felt.Felt) (*DeclaredClass, error) {
 	return declaredClass, nil
 }
 
+func (s *stateSnapshot) HasClass(classHash *felt.Felt) (bool, error) {
+	var (
+		declaredAt uint64
+		err        error
+	)
+	if state, ok := s.state.(*State); ok {
+		declaredAt, err = state.classDeclaredAt(classHash)
+	} else {
+		var declaredClass *DeclaredClass
+		if declaredClass, err = s.state.Class(classHash); err == nil {
+			declaredAt = declaredClass.At
+		}
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return declaredAt <= s.blockNumber, nil
+}
+
 func (s *stateSnapshot) ClassTrie() (*trie.Trie, error) {
 	return nil, ErrHistoricalTrieNotSupported
 }
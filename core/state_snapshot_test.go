@@ -138,4 +138,16 @@ func TestStateSnapshot(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, declareHeight, declared.At)
 	})
+
+	t.Run("has class from the height it is declared", func(t *testing.T) {
+		for snapshot, expected := range map[core.StateReader]bool{
+			snapshotBeforeDeployment: false,
+			snapshotBeforeChange:     true,
+			snapshotAfterChange:      true,
+		} {
+			hasClass, err := core.HasClass(snapshot, addr)
+			require.NoError(t, err)
+			require.Equal(t, expected, hasClass)
+		}
+	})
 }
@@ -642,6 +642,12 @@ func TestRevertDeclaredClasses(t *testing.T) {
 	sierraClass, sErr := state.Class(sierraHash)
 	require.NoError(t, sErr)
 	assert.Equal(t, uint64(0), sierraClass.At)
+	hasClass, err := state.HasClass(classHash)
+	require.NoError(t, err)
+	assert.True(t, hasClass)
+	hasClass, err = state.HasClass(utils.HexToFelt(t, "0xDEADBEEF3"))
+	require.NoError(t, err)
+	assert.False(t, hasClass)
 
 	declareDiff.OldRoot = declareDiff.NewRoot
 	require.NoError(t, state.Update(1, declareDiff, newClasses))
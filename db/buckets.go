@@ -38,6 +38,8 @@ const (
 	MempoolTail               // key of the tail node
 	MempoolLength             // number of transactions
 	MempoolNode
+	Checkpoint   // trusted block the chain was verified from, when restored from a snapshot
+	HistoryStart // lowest block whose history was backfilled below the checkpoint
 )
 
 // Key flattens a prefix and series of byte arrays into a single []byte.
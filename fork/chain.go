@@ -0,0 +1,470 @@
+package fork
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/feed"
+	junoSync "github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+)
+
+var (
+	ErrEventsUnsupported      = errors.New("events are not available in fork mode")
+	ErrStateBeforeForkBlock   = errors.New("the state before the fork block is not available")
+	ErrStateUpdateUnsupported = errors.New("state updates of upstream blocks are not available in fork mode")
+)
+
+var (
+	_ blockchain.Reader = (*Chain)(nil)
+	_ junoSync.Reader   = (*Chain)(nil)
+)
+
+// upstreamHeaderCacheSize is how many headers of upstream blocks are kept in memory.
+const upstreamHeaderCacheSize = 1024
+
+func noopCloser() error { return nil }
+
+// localBlock is a block of transactions executed by the fork, with the state after it.
+type localBlock struct {
+	block       *core.Block
+	stateUpdate *core.StateUpdate
+	commitments *core.BlockCommitments
+	// diff and classes are everything changed since the fork block, they are never modified once the block
+	// is added so that states of past blocks stay the same.
+	diff    *core.StateDiff
+	classes map[felt.Felt]core.Class
+}
+
+type txnLocation struct {
+	block uint64
+	index uint64
+}
+
+// Chain is the chain of a fork: the blocks of the upstream up to the fork block, of which only the headers
+// are known, followed by the blocks of transactions added locally. It serves both as the blockchain and as the
+// synchroniser of the RPC handlers, and has an empty pending block on top of its head.
+type Chain struct {
+	upstream   *Upstream
+	network    *utils.Network
+	base       *State
+	forkHeader *core.Header
+	// headers caches the headers fetched from the upstream, keyed by block number
+	headers *lru.Cache[uint64, *core.Header]
+
+	mu             sync.RWMutex
+	blocks         []*localBlock
+	blocksByHash   map[felt.Felt]uint64
+	txnsByHash     map[felt.Felt]txnLocation
+	l1HandlerTxns  map[common.Hash]*felt.Felt
+	newHeads       *feed.Feed[*core.Block]
+	reorgs         *feed.Feed[*junoSync.ReorgBlockRange]
+	pendingUpdates *feed.Feed[*core.Block]
+	l1Heads        *feed.Feed[*core.L1Head]
+}
+
+// NewChain forks the upstream at the block it is pinned to.
+func NewChain(ctx context.Context, upstream *Upstream, network *utils.Network) (*Chain, error) {
+	chainID, err := upstream.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get upstream chain id: %w", err)
+	}
+	if !chainID.Equal(network.L2ChainIDFelt()) {
+		return nil, fmt.Errorf("upstream chain id %s does not match network %s", chainID, network)
+	}
+
+	forkHeader, err := upstream.HeaderByNumber(ctx, upstream.ForkBlock())
+	if err != nil {
+		return nil, fmt.Errorf("get fork block %d: %w", upstream.ForkBlock(), err)
+	}
+
+	return &Chain{
+		upstream:       upstream,
+		network:        network,
+		base:           NewState(upstream),
+		forkHeader:     forkHeader,
+		headers:        lru.NewCache[uint64, *core.Header](upstreamHeaderCacheSize),
+		blocksByHash:   make(map[felt.Felt]uint64),
+		txnsByHash:     make(map[felt.Felt]txnLocation),
+		l1HandlerTxns:  make(map[common.Hash]*felt.Felt),
+		newHeads:       feed.New[*core.Block](),
+		reorgs:         feed.New[*junoSync.ReorgBlockRange](),
+		pendingUpdates: feed.New[*core.Block](),
+		l1Heads:        feed.New[*core.L1Head](),
+	}, nil
+}
+
+func (c *Chain) Network() *utils.Network {
+	return c.network
+}
+
+// ForkHeader returns the header of the upstream block the chain was forked at.
+func (c *Chain) ForkHeader() *core.Header {
+	return c.forkHeader
+}
+
+func (c *Chain) Height() (uint64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.forkHeader.Number + uint64(len(c.blocks)), nil
+}
+
+// local returns the local block with the given number, or nil if it is not a local block.
+func (c *Chain) local(number uint64) *localBlock {
+	if number <= c.forkHeader.Number || number-c.forkHeader.Number > uint64(len(c.blocks)) {
+		return nil
+	}
+	return c.blocks[number-c.forkHeader.Number-1]
+}
+
+func (c *Chain) head() *localBlock {
+	if len(c.blocks) == 0 {
+		return nil
+	}
+	return c.blocks[len(c.blocks)-1]
+}
+
+func (c *Chain) Head() (*core.Block, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if head := c.head(); head != nil {
+		return head.block, nil
+	}
+	return &core.Block{Header: c.forkHeader}, nil
+}
+
+func (c *Chain) HeadsHeader() (*core.Header, error) {
+	head, err := c.Head()
+	if err != nil {
+		return nil, err
+	}
+	return head.Header, nil
+}
+
+// BlockByNumber returns a local block, or a block of the upstream without its transactions.
+func (c *Chain) BlockByNumber(number uint64) (*core.Block, error) {
+	c.mu.RLock()
+	local := c.local(number)
+	c.mu.RUnlock()
+	if local != nil {
+		return local.block, nil
+	}
+
+	header, err := c.BlockHeaderByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+	return &core.Block{Header: header}, nil
+}
+
+func (c *Chain) BlockByHash(hash *felt.Felt) (*core.Block, error) {
+	header, err := c.BlockHeaderByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return c.BlockByNumber(header.Number)
+}
+
+func (c *Chain) BlockHeaderByNumber(number uint64) (*core.Header, error) {
+	c.mu.RLock()
+	local := c.local(number)
+	c.mu.RUnlock()
+	switch {
+	case local != nil:
+		return local.block.Header, nil
+	case number == c.forkHeader.Number:
+		return c.forkHeader, nil
+	case number > c.forkHeader.Number:
+		return nil, db.ErrKeyNotFound
+	}
+	if header, ok := c.headers.Get(number); ok {
+		return header, nil
+	}
+
+	header, err := c.upstream.HeaderByNumber(context.Background(), number)
+	if err != nil {
+		return nil, err
+	}
+	c.headers.Add(number, header)
+	return header, nil
+}
+
+func (c *Chain) BlockHeaderByHash(hash *felt.Felt) (*core.Header, error) {
+	c.mu.RLock()
+	number, ok := c.blocksByHash[*hash]
+	c.mu.RUnlock()
+	if ok {
+		return c.BlockHeaderByNumber(number)
+	}
+
+	header, err := c.upstream.HeaderByHash(context.Background(), hash)
+	if err != nil {
+		return nil, err
+	}
+	c.headers.Add(header.Number, header)
+	return header, nil
+}
+
+// TransactionByHash only finds transactions added locally.
+func (c *Chain) TransactionByHash(hash *felt.Felt) (core.Transaction, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	location, ok := c.txnsByHash[*hash]
+	if !ok {
+		return nil, db.ErrKeyNotFound
+	}
+	return c.local(location.block).block.Transactions[location.index], nil
+}
+
+func (c *Chain) TransactionByBlockNumberAndIndex(blockNumber, index uint64) (core.Transaction, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	local := c.local(blockNumber)
+	if local == nil || index >= uint64(len(local.block.Transactions)) {
+		return nil, db.ErrKeyNotFound
+	}
+	return local.block.Transactions[index], nil
+}
+
+// Receipt only finds receipts of transactions added locally.
+func (c *Chain) Receipt(hash *felt.Felt) (*core.TransactionReceipt, *felt.Felt, uint64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	location, ok := c.txnsByHash[*hash]
+	if !ok {
+		return nil, nil, 0, db.ErrKeyNotFound
+	}
+	block := c.local(location.block).block
+	return block.Receipts[location.index], block.Hash, block.Number, nil
+}
+
+func (c *Chain) StateUpdateByNumber(number uint64) (*core.StateUpdate, error) {
+	c.mu.RLock()
+	local := c.local(number)
+	height := c.forkHeader.Number + uint64(len(c.blocks))
+	c.mu.RUnlock()
+	switch {
+	case local != nil:
+		return local.stateUpdate, nil
+	case number > height:
+		return nil, db.ErrKeyNotFound
+	default:
+		return nil, ErrStateUpdateUnsupported
+	}
+}
+
+func (c *Chain) StateUpdateByHash(hash *felt.Felt) (*core.StateUpdate, error) {
+	header, err := c.BlockHeaderByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return c.StateUpdateByNumber(header.Number)
+}
+
+func (c *Chain) BlockCommitmentsByNumber(number uint64) (*core.BlockCommitments, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if local := c.local(number); local != nil {
+		return local.commitments, nil
+	}
+	return nil, db.ErrKeyNotFound
+}
+
+// L1HandlerTxnHash only finds L1 handler transactions added locally.
+func (c *Chain) L1HandlerTxnHash(msgHash *common.Hash) (*felt.Felt, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if txnHash, ok := c.l1HandlerTxns[*msgHash]; ok {
+		return txnHash, nil
+	}
+	return nil, db.ErrKeyNotFound
+}
+
+// L1Head always fails, a fork is never confirmed on L1.
+func (c *Chain) L1Head() (*core.L1Head, error) {
+	return nil, db.ErrKeyNotFound
+}
+
+func (c *Chain) SubscribeL1Head() blockchain.L1HeadSubscription {
+	return blockchain.L1HeadSubscription{Subscription: c.l1Heads.Subscribe()}
+}
+
+func (c *Chain) EventFilter(from *felt.Felt, keys [][]felt.Felt) (blockchain.EventFilterer, error) {
+	return nil, ErrEventsUnsupported
+}
+
+func (c *Chain) HeadState() (core.StateReader, blockchain.StateCloser, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.stateAfter(c.head()), noopCloser, nil
+}
+
+func (c *Chain) StateAtBlockNumber(number uint64) (core.StateReader, blockchain.StateCloser, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	switch local := c.local(number); {
+	case local != nil:
+		return c.stateAfter(local), noopCloser, nil
+	case number == c.forkHeader.Number:
+		return c.base, noopCloser, nil
+	case number < c.forkHeader.Number:
+		return nil, nil, ErrStateBeforeForkBlock
+	default:
+		return nil, nil, db.ErrKeyNotFound
+	}
+}
+
+func (c *Chain) StateAtBlockHash(hash *felt.Felt) (core.StateReader, blockchain.StateCloser, error) {
+	header, err := c.BlockHeaderByHash(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.StateAtBlockNumber(header.Number)
+}
+
+// stateAfter returns the state after a local block, or the fork block if it is nil.
+func (c *Chain) stateAfter(local *localBlock) core.StateReader {
+	if local == nil {
+		return c.base
+	}
+	return junoSync.NewPendingState(local.diff, local.classes, c.base)
+}
+
+// appendBlock adds a block executed on top of the head, with its state diff and the classes it declared.
+func (c *Chain) appendBlock(block *core.Block, commitments *core.BlockCommitments, diff *core.StateDiff,
+	classes map[felt.Felt]core.Class,
+) error {
+	c.mu.Lock()
+	head := c.head()
+	headHeader := c.forkHeader
+	cumulativeDiff := core.EmptyStateDiff()
+	cumulativeClasses := make(map[felt.Felt]core.Class, len(classes))
+	if head != nil {
+		headHeader = head.block.Header
+		cumulativeDiff = *copyStateDiff(head.diff)
+		maps.Copy(cumulativeClasses, head.classes)
+	}
+	if block.Number != headHeader.Number+1 || !block.ParentHash.Equal(headHeader.Hash) {
+		c.mu.Unlock()
+		return fmt.Errorf("block %d does not extend the head %d", block.Number, headHeader.Number)
+	}
+	cumulativeDiff.Merge(copyStateDiff(diff))
+	maps.Copy(cumulativeClasses, classes)
+
+	c.blocks = append(c.blocks, &localBlock{
+		block: block,
+		stateUpdate: &core.StateUpdate{
+			BlockHash: block.Hash,
+			NewRoot:   block.GlobalStateRoot,
+			OldRoot:   headHeader.GlobalStateRoot,
+			StateDiff: diff,
+		},
+		commitments: commitments,
+		diff:        &cumulativeDiff,
+		classes:     cumulativeClasses,
+	})
+	c.blocksByHash[*block.Hash] = block.Number
+	for idx, txn := range block.Transactions {
+		c.txnsByHash[*txn.Hash()] = txnLocation{block: block.Number, index: uint64(idx)}
+		if l1Handler, ok := txn.(*core.L1HandlerTransaction); ok {
+			c.l1HandlerTxns[common.BytesToHash(l1Handler.MessageHash())] = txn.Hash()
+		}
+	}
+	c.mu.Unlock()
+
+	c.newHeads.Send(block)
+	return nil
+}
+
+// copyStateDiff deep copies a state diff, Merge shares the storage maps of the incoming diff.
+func copyStateDiff(diff *core.StateDiff) *core.StateDiff {
+	copied := core.EmptyStateDiff()
+	for addr, storage := range diff.StorageDiffs {
+		copied.StorageDiffs[addr] = maps.Clone(storage)
+	}
+	maps.Copy(copied.Nonces, diff.Nonces)
+	maps.Copy(copied.DeployedContracts, diff.DeployedContracts)
+	maps.Copy(copied.DeclaredV1Classes, diff.DeclaredV1Classes)
+	maps.Copy(copied.ReplacedClasses, diff.ReplacedClasses)
+	copied.DeclaredV0Classes = append(copied.DeclaredV0Classes, diff.DeclaredV0Classes...)
+	return &copied
+}
+
+func (c *Chain) StartingBlockNumber() (uint64, error) {
+	return c.forkHeader.Number, nil
+}
+
+func (c *Chain) HighestBlockHeader() *core.Header {
+	header, _ := c.HeadsHeader()
+	return header
+}
+
+func (c *Chain) SubscribeNewHeads() junoSync.NewHeadSubscription {
+	return junoSync.NewHeadSubscription{Subscription: c.newHeads.Subscribe()}
+}
+
+func (c *Chain) SubscribeReorg() junoSync.ReorgSubscription {
+	return junoSync.ReorgSubscription{Subscription: c.reorgs.Subscribe()}
+}
+
+func (c *Chain) SubscribePending() junoSync.PendingSubscription {
+	return junoSync.PendingSubscription{Subscription: c.pendingUpdates.Subscribe()}
+}
+
+// Pending returns an empty block on top of the head, transactions are added to the chain in blocks of their own.
+func (c *Chain) Pending() (*junoSync.Pending, error) {
+	head, err := c.HeadsHeader()
+	if err != nil {
+		return nil, err
+	}
+	return &junoSync.Pending{
+		Block: &core.Block{
+			Header: nextHeader(head, uint64(time.Now().Unix())),
+		},
+		StateUpdate: &core.StateUpdate{
+			OldRoot:   head.GlobalStateRoot,
+			StateDiff: utils.HeapPtr(core.EmptyStateDiff()),
+		},
+		NewClasses: make(map[felt.Felt]core.Class),
+	}, nil
+}
+
+func (c *Chain) PendingBlock() *core.Block {
+	pending, err := c.Pending()
+	if err != nil {
+		return nil
+	}
+	return pending.Block
+}
+
+func (c *Chain) PendingState() (core.StateReader, func() error, error) {
+	return c.HeadState()
+}
+
+// nextHeader returns the header of a block on top of parent, inheriting its gas prices. The timestamp of the
+// new block does not go back before the one of its parent.
+func nextHeader(parent *core.Header, timestamp uint64) *core.Header {
+	return &core.Header{
+		ParentHash:       parent.Hash,
+		Number:           parent.Number + 1,
+		GlobalStateRoot:  parent.GlobalStateRoot,
+		SequencerAddress: parent.SequencerAddress,
+		Timestamp:        max(timestamp, parent.Timestamp),
+		ProtocolVersion:  parent.ProtocolVersion,
+		L1GasPriceETH:    parent.L1GasPriceETH,
+		L1GasPriceSTRK:   parent.L1GasPriceSTRK,
+		L1DAMode:         parent.L1DAMode,
+		L1DataGasPrice:   parent.L1DataGasPrice,
+		L2GasPrice:       parent.L2GasPrice,
+	}
+}
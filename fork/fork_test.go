@@ -0,0 +1,286 @@
+package fork_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/NethermindEth/juno/clients/gateway"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/fork"
+	"github.com/NethermindEth/juno/mocks"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeUpstream is a Starknet RPC node answering every method with a fixed result, or a contract not found
+// error if there is none. Results that are upstream errors are answered as errors.
+type fakeUpstream struct {
+	results map[string]any
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func newFakeUpstream(t *testing.T, results map[string]any) (*fakeUpstream, *httptest.Server) {
+	t.Helper()
+
+	upstream := &fakeUpstream{results: results, calls: make(map[string]int)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+			ID     uint64          `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		upstream.mu.Lock()
+		upstream.calls[req.Method]++
+		upstream.mu.Unlock()
+
+		res := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		if result, ok := upstream.results[req.Method]; ok {
+			if upstreamErr, isErr := result.(*fork.UpstreamError); isErr {
+				res["error"] = upstreamErr
+			} else {
+				res["result"] = result
+			}
+		} else {
+			res["error"] = map[string]any{"code": 20, "message": "Contract not found"}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	t.Cleanup(server.Close)
+	return upstream, server
+}
+
+func (u *fakeUpstream) callCount(method string) int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.calls[method]
+}
+
+const forkBlock = 5
+
+func upstreamResults() map[string]any {
+	return map[string]any{
+		"starknet_chainId":        utils.Mainnet.L2ChainIDFelt().String(),
+		"starknet_getStorageAt":   "0x7",
+		"starknet_getNonce":       "0x2",
+		"starknet_getClassHashAt": "0xc1a55",
+		"starknet_getBlockWithTxHashes": map[string]any{
+			"block_hash":        "0xb5",
+			"parent_hash":       "0xb4",
+			"block_number":      forkBlock,
+			"new_root":          "0x5",
+			"timestamp":         100,
+			"sequencer_address": "0x1",
+			"l1_gas_price":      map[string]any{"price_in_fri": "0x1", "price_in_wei": "0x2"},
+			"l1_data_gas_price": map[string]any{"price_in_fri": "0x3", "price_in_wei": "0x4"},
+			"l2_gas_price":      map[string]any{"price_in_fri": "0x5", "price_in_wei": "0x6"},
+			"l1_da_mode":        "BLOB",
+			"starknet_version":  "0.13.4",
+			"transactions":      []string{"0x1"},
+		},
+	}
+}
+
+func TestNewChain(t *testing.T) {
+	t.Run("chain id of another network", func(t *testing.T) {
+		_, server := newFakeUpstream(t, upstreamResults())
+		_, err := fork.NewChain(context.Background(), fork.NewUpstream(server.URL, forkBlock), &utils.Sepolia)
+		require.ErrorContains(t, err, "does not match network")
+	})
+
+	t.Run("fork block", func(t *testing.T) {
+		_, server := newFakeUpstream(t, upstreamResults())
+		chain, err := fork.NewChain(context.Background(), fork.NewUpstream(server.URL, forkBlock), &utils.Mainnet)
+		require.NoError(t, err)
+
+		height, err := chain.Height()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(forkBlock), height)
+
+		header := chain.ForkHeader()
+		assert.Equal(t, utils.HexToFelt(t, "0xb5"), header.Hash)
+		assert.Equal(t, uint64(100), header.Timestamp)
+		assert.Equal(t, uint64(1), header.TransactionCount)
+		assert.Equal(t, core.Blob, header.L1DAMode)
+		assert.Equal(t, &core.GasPrice{
+			PriceInFri: utils.HexToFelt(t, "0x5"),
+			PriceInWei: utils.HexToFelt(t, "0x6"),
+		}, header.L2GasPrice)
+
+		_, err = chain.BlockHeaderByNumber(forkBlock + 1)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+		_, _, err = chain.StateAtBlockNumber(forkBlock - 1)
+		require.ErrorIs(t, err, fork.ErrStateBeforeForkBlock)
+	})
+}
+
+func TestStateFetchesOnce(t *testing.T) {
+	upstream, server := newFakeUpstream(t, upstreamResults())
+	state := fork.NewState(fork.NewUpstream(server.URL, forkBlock))
+	addr, key := new(felt.Felt).SetUint64(1), new(felt.Felt).SetUint64(2)
+
+	for range 2 {
+		value, err := state.ContractStorage(addr, key)
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(7), value)
+
+		nonce, err := state.ContractNonce(addr)
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(2), nonce)
+
+		classHash, err := state.ContractClassHash(addr)
+		require.NoError(t, err)
+		assert.Equal(t, utils.HexToFelt(t, "0xc1a55"), classHash)
+
+		_, err = state.Class(classHash)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	}
+
+	for _, method := range []string{
+		"starknet_getStorageAt", "starknet_getNonce", "starknet_getClassHashAt", "starknet_getClass",
+	} {
+		assert.Equal(t, 1, upstream.callCount(method), method)
+	}
+
+	_, err := state.ContractStorageTrie(addr)
+	require.ErrorIs(t, err, fork.ErrTriesUnsupported)
+}
+
+func TestStateBlockNotFound(t *testing.T) {
+	results := upstreamResults()
+	results["starknet_getStorageAt"] = &fork.UpstreamError{Code: 24, Message: "Block not found"}
+	upstream, server := newFakeUpstream(t, results)
+	state := fork.NewState(fork.NewUpstream(server.URL, forkBlock))
+	addr, key := new(felt.Felt).SetUint64(1), new(felt.Felt).SetUint64(2)
+
+	for range 2 {
+		_, err := state.ContractStorage(addr, key)
+		var upstreamErr *fork.UpstreamError
+		require.ErrorAs(t, err, &upstreamErr)
+		assert.Equal(t, 24, upstreamErr.Code)
+		assert.NotErrorIs(t, err, db.ErrKeyNotFound)
+	}
+	assert.Equal(t, 2, upstream.callCount("starknet_getStorageAt"))
+}
+
+func TestSequencerAddTransaction(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockVM := mocks.NewMockVM(mockCtrl)
+
+	upstream, server := newFakeUpstream(t, upstreamResults())
+	chain, err := fork.NewChain(context.Background(), fork.NewUpstream(server.URL, forkBlock), &utils.Mainnet)
+	require.NoError(t, err)
+	sequencer := fork.NewSequencer(chain, mockVM, utils.NewNopZapLogger())
+
+	sender := new(felt.Felt).SetUint64(0xacc)
+	storageKey := new(felt.Felt).SetUint64(2)
+	txnJSON := json.RawMessage(`{
+		"type": "INVOKE_FUNCTION",
+		"version": "0x1",
+		"sender_address": "0xacc",
+		"calldata": ["0x1"],
+		"signature": [],
+		"max_fee": "0x10",
+		"nonce": "0x2"
+	}`)
+
+	mockVM.EXPECT().Execute(gomock.Len(1), gomock.Len(0), nil, gomock.Any(), gomock.Any(), &utils.Mainnet,
+		false, false, false, true).DoAndReturn(func(_ []core.Transaction, _ []core.Class, _ []*felt.Felt,
+		blockInfo *vm.BlockInfo, state core.StateReader, _ *utils.Network, _, _, _, _ bool,
+	) (vm.ExecutionResults, error) {
+		assert.Equal(t, uint64(forkBlock+1), blockInfo.Header.Number)
+		assert.Nil(t, blockInfo.BlockHashToBeRevealed)
+
+		nonce, err := state.ContractNonce(sender)
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(2), nonce)
+
+		return vm.ExecutionResults{
+			OverallFees:      []*felt.Felt{new(felt.Felt).SetUint64(3)},
+			DataAvailability: []core.DataAvailability{{}},
+			GasConsumed:      []core.GasConsumed{{L1Gas: 1}},
+			Traces: []vm.TransactionTrace{{
+				Type: vm.TxnInvoke,
+				StateDiff: &vm.StateDiff{
+					StorageDiffs: []vm.StorageDiff{{
+						Address:        *sender,
+						StorageEntries: []vm.Entry{{Key: *storageKey, Value: *new(felt.Felt).SetUint64(9)}},
+					}},
+					Nonces: []vm.Nonce{{ContractAddress: *sender, Nonce: *new(felt.Felt).SetUint64(3)}},
+				},
+			}},
+		}, nil
+	})
+
+	resJSON, err := sequencer.AddTransaction(context.Background(), txnJSON)
+	require.NoError(t, err)
+	var res struct {
+		TransactionHash *felt.Felt `json:"transaction_hash"`
+	}
+	require.NoError(t, json.Unmarshal(resJSON, &res))
+	require.NotNil(t, res.TransactionHash)
+
+	head, err := chain.Head()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(forkBlock+1), head.Number)
+	assert.Equal(t, utils.HexToFelt(t, "0xb5"), head.ParentHash)
+	assert.Equal(t, res.TransactionHash, head.Transactions[0].Hash())
+
+	receipt, blockHash, blockNumber, err := chain.Receipt(res.TransactionHash)
+	require.NoError(t, err)
+	assert.Equal(t, head.Hash, blockHash)
+	assert.Equal(t, head.Number, blockNumber)
+	assert.Equal(t, new(felt.Felt).SetUint64(3), receipt.Fee)
+	assert.Equal(t, core.WEI, receipt.FeeUnit)
+
+	t.Run("local changes are layered on the upstream state", func(t *testing.T) {
+		state, closer, err := chain.HeadState()
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, closer()) })
+
+		value, err := state.ContractStorage(sender, storageKey)
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(9), value)
+		nonce, err := state.ContractNonce(sender)
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(3), nonce)
+
+		forkState, closer, err := chain.StateAtBlockNumber(forkBlock)
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, closer()) })
+		value, err = forkState.ContractStorage(sender, storageKey)
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(7), value)
+
+		assert.Equal(t, 1, upstream.callCount("starknet_getNonce"))
+	})
+
+	t.Run("duplicate transaction", func(t *testing.T) {
+		_, err := sequencer.AddTransaction(context.Background(), txnJSON)
+		var gatewayErr *gateway.Error
+		require.ErrorAs(t, err, &gatewayErr)
+		assert.Equal(t, gateway.DuplicatedTransaction, gatewayErr.Code)
+	})
+
+	t.Run("pending block is on top of the head", func(t *testing.T) {
+		pending, err := chain.Pending()
+		require.NoError(t, err)
+		assert.Equal(t, head.Number+1, pending.Block.Number)
+		assert.Equal(t, head.Hash, pending.Block.ParentHash)
+		assert.Empty(t, pending.Block.Transactions)
+	})
+}
@@ -0,0 +1,265 @@
+package fork
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/adapters/rpc2core"
+	"github.com/NethermindEth/juno/adapters/sn2core"
+	"github.com/NethermindEth/juno/adapters/vm2core"
+	"github.com/NethermindEth/juno/clients/gateway"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/rpc/rpccore"
+	"github.com/NethermindEth/juno/starknet"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
+)
+
+var _ rpccore.Gateway = (*Sequencer)(nil)
+
+// Sequencer executes the transactions added to a fork. It takes the place of the gateway for the RPC handlers
+// and adds every transaction to the chain in a block of its own, so that it is accepted as soon as it is added.
+type Sequencer struct {
+	chain *Chain
+	vm    vm.VM
+	log   utils.SimpleLogger
+	// mu makes sure that transactions are executed on top of the latest block
+	mu sync.Mutex
+}
+
+func NewSequencer(chain *Chain, virtualMachine vm.VM, log utils.SimpleLogger) *Sequencer {
+	return &Sequencer{
+		chain: chain,
+		vm:    virtualMachine,
+		log:   log,
+	}
+}
+
+type addTransactionResponse struct {
+	Code            string     `json:"code"`
+	TransactionHash *felt.Felt `json:"transaction_hash"`
+	ContractAddress *felt.Felt `json:"address,omitempty"`
+	ClassHash       *felt.Felt `json:"class_hash,omitempty"`
+}
+
+// AddTransaction executes a transaction in the format of the gateway on top of the head and adds it to the chain.
+// Transactions failing validation are rejected with the gateway error the RPC handlers expect, reverted ones are
+// added like on Starknet.
+func (s *Sequencer) AddTransaction(ctx context.Context, txnJSON json.RawMessage) (json.RawMessage, error) {
+	txn, class, err := adaptGatewayTransaction(txnJSON, s.chain.Network())
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err = s.chain.TransactionByHash(txn.Hash()); err == nil {
+		return nil, &gateway.Error{
+			Code:    gateway.DuplicatedTransaction,
+			Message: fmt.Sprintf("Transaction with hash %s already exists", txn.Hash()),
+		}
+	}
+
+	block, err := s.execute(ctx, txn, class)
+	if err != nil {
+		return nil, err
+	}
+	s.log.Infow("Added local block", "number", block.Number, "hash", block.Hash.ShortString(),
+		"transaction", txn.Hash().ShortString())
+
+	response := addTransactionResponse{
+		Code:            "TRANSACTION_RECEIVED",
+		TransactionHash: txn.Hash(),
+	}
+	switch t := txn.(type) {
+	case *core.DeclareTransaction:
+		response.ClassHash = t.ClassHash
+	case *core.DeployAccountTransaction:
+		response.ContractAddress = t.ContractAddress
+	}
+	return json.Marshal(response)
+}
+
+func (s *Sequencer) execute(ctx context.Context, txn core.Transaction, class core.Class) (*core.Block, error) {
+	head, err := s.chain.HeadsHeader()
+	if err != nil {
+		return nil, err
+	}
+	header := nextHeader(head, uint64(time.Now().Unix()))
+
+	var blockHashToBeRevealed *felt.Felt
+	if header.Number >= core.BlockHashLag {
+		revealedHeader, err := s.chain.BlockHeaderByNumber(header.Number - core.BlockHashLag)
+		if err != nil {
+			return nil, fmt.Errorf("get block %d: %w", header.Number-core.BlockHashLag, err)
+		}
+		blockHashToBeRevealed = revealedHeader.Hash
+	}
+
+	state, closer, err := s.chain.HeadState()
+	if err != nil {
+		return nil, err
+	}
+	defer closer() //nolint:errcheck
+
+	var (
+		classes    []core.Class
+		newClasses = make(map[felt.Felt]core.Class)
+	)
+	if declare, ok := txn.(*core.DeclareTransaction); ok {
+		if _, err = state.Class(declare.ClassHash); err == nil {
+			return nil, &gateway.Error{
+				Code:    gateway.ClassAlreadyDeclared,
+				Message: fmt.Sprintf("Class with hash %s is already declared", declare.ClassHash),
+			}
+		}
+		classes = append(classes, class)
+		newClasses[*declare.ClassHash] = class
+	}
+
+	network := s.chain.Network()
+	results, err := vm.WithContext(ctx, s.vm).Execute([]core.Transaction{txn}, classes, nil,
+		&vm.BlockInfo{Header: header, BlockHashToBeRevealed: blockHashToBeRevealed},
+		state, network, false, false, false, true)
+	if err != nil {
+		var txnExecutionError vm.TransactionExecutionError
+		if errors.As(err, &txnExecutionError) {
+			return nil, &gateway.Error{Code: gateway.ValidateFailure, Message: string(txnExecutionError.Cause)}
+		}
+		return nil, err
+	}
+	if len(results.Traces) != 1 || len(results.OverallFees) != 1 {
+		return nil, fmt.Errorf("unexpected execution results for %d traces", len(results.Traces))
+	}
+
+	trace := &results.Traces[0]
+	receipt := &core.TransactionReceipt{
+		Fee:             results.OverallFees[0],
+		FeeUnit:         feeUnit(txn),
+		Events:          vm2core.AdaptOrderedEvents(trace.AllEvents()),
+		L2ToL1Message:   vm2core.AdaptOrderedMessagesToL1(trace.AllMessages()),
+		TransactionHash: txn.Hash(),
+		Reverted:        trace.RevertReason() != "",
+		RevertReason:    trace.RevertReason(),
+	}
+	if len(results.GasConsumed) == 1 && len(results.DataAvailability) == 1 {
+		receipt.ExecutionResources = &core.ExecutionResources{
+			DataAvailability: &results.DataAvailability[0],
+			TotalGasConsumed: &results.GasConsumed[0],
+		}
+	}
+
+	diff := core.EmptyStateDiff()
+	if blockHashToBeRevealed != nil {
+		diff.StorageDiffs[*core.BlockHashContract] = map[felt.Felt]*felt.Felt{
+			*new(felt.Felt).SetUint64(header.Number - core.BlockHashLag): blockHashToBeRevealed,
+		}
+	}
+	txnDiff := vm2core.AdaptStateDiff(trace.StateDiff)
+	diff.Merge(&txnDiff)
+
+	header.TransactionCount = 1
+	header.EventCount = uint64(len(receipt.Events))
+	block := &core.Block{
+		Header:       header,
+		Transactions: []core.Transaction{txn},
+		Receipts:     []*core.TransactionReceipt{receipt},
+	}
+	block.EventsBloom = core.EventsBloom(block.Receipts)
+	// the state root is not recomputed in fork mode, so blocks keep the one of the fork block
+	blockHash, commitments, err := core.Post0132Hash(block, &diff)
+	if err != nil {
+		return nil, err
+	}
+	block.Hash = blockHash
+
+	if err = s.chain.appendBlock(block, commitments, &diff, newClasses); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func feeUnit(txn core.Transaction) core.FeeUnit {
+	if version := txn.TxVersion(); version != nil && version.Is(3) {
+		return core.STRK
+	}
+	return core.WEI
+}
+
+// adaptGatewayTransaction adapts a transaction sent to the gateway and computes its hash.
+func adaptGatewayTransaction(txnJSON json.RawMessage, network *utils.Network) (core.Transaction, core.Class, error) {
+	var request struct {
+		starknet.Transaction
+		ContractClass json.RawMessage `json:"contract_class,omitempty"`
+	}
+	if err := json.Unmarshal(txnJSON, &request); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal transaction: %w", err)
+	}
+	if request.Type == starknet.TxnL1Handler {
+		return nil, nil, errors.New("L1 handler transactions cannot be added")
+	}
+
+	txn, err := sn2core.AdaptTransaction(&request.Transaction)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var class core.Class
+	if declare, ok := txn.(*core.DeclareTransaction); ok {
+		if class, err = adaptGatewayClass(request.ContractClass); err != nil {
+			return nil, nil, &gateway.Error{Code: gateway.InvalidContractClass, Message: err.Error()}
+		}
+		if declare.ClassHash, err = class.Hash(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	txnHash, err := core.TransactionHash(txn, network)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch t := txn.(type) {
+	case *core.DeclareTransaction:
+		t.TransactionHash = txnHash
+	case *core.InvokeTransaction:
+		t.TransactionHash = txnHash
+	case *core.DeployAccountTransaction:
+		t.TransactionHash = txnHash
+	default:
+		return nil, nil, errors.New("unsupported transaction")
+	}
+	return txn, class, nil
+}
+
+// adaptGatewayClass adapts a class sent to the gateway, which differs from the RPC format only by its
+// compressed Sierra program.
+func adaptGatewayClass(classJSON json.RawMessage) (core.Class, error) {
+	if len(classJSON) == 0 {
+		return nil, errors.New("declare without a class definition")
+	}
+
+	var definition map[string]json.RawMessage
+	if err := json.Unmarshal(classJSON, &definition); err != nil {
+		return nil, err
+	}
+	if program, ok := definition["sierra_program"]; ok {
+		var compressed string
+		if err := json.Unmarshal(program, &compressed); err == nil {
+			if definition["sierra_program"], err = utils.Gzip64Decode(compressed); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	classJSON, err := json.Marshal(definition)
+	if err != nil {
+		return nil, err
+	}
+	return rpc2core.AdaptDeclaredClass(classJSON)
+}
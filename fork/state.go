@@ -0,0 +1,119 @@
+package fork
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/NethermindEth/juno/db"
+	"github.com/ethereum/go-ethereum/common/lru"
+)
+
+// Number of upstream values kept in memory, the least recently used ones are fetched again when needed.
+const (
+	valueCacheSize = 1 << 16
+	classCacheSize = 1 << 10
+)
+
+var ErrTriesUnsupported = errors.New("tries are not available in fork mode")
+
+var _ core.StateReader = (*State)(nil)
+
+type storageKey struct {
+	addr felt.Felt
+	key  felt.Felt
+}
+
+// cached is a value fetched from the upstream, found is false if it does not exist there.
+type cached[T any] struct {
+	value T
+	found bool
+}
+
+// State is the state of the upstream at the fork block. Every value is fetched from the upstream the first
+// time it is read and served from memory afterwards, including values that do not exist upstream. The values
+// kept in memory are bounded, the least recently used ones are evicted.
+type State struct {
+	upstream *Upstream
+
+	classHashes *lru.Cache[felt.Felt, cached[*felt.Felt]]
+	nonces      *lru.Cache[felt.Felt, cached[*felt.Felt]]
+	storage     *lru.Cache[storageKey, cached[*felt.Felt]]
+	classes     *lru.Cache[felt.Felt, cached[*core.DeclaredClass]]
+}
+
+func NewState(upstream *Upstream) *State {
+	return &State{
+		upstream:    upstream,
+		classHashes: lru.NewCache[felt.Felt, cached[*felt.Felt]](valueCacheSize),
+		nonces:      lru.NewCache[felt.Felt, cached[*felt.Felt]](valueCacheSize),
+		storage:     lru.NewCache[storageKey, cached[*felt.Felt]](valueCacheSize),
+		classes:     lru.NewCache[felt.Felt, cached[*core.DeclaredClass]](classCacheSize),
+	}
+}
+
+// fetchOnce returns the cached value of key or fetches it. Upstream failures other than the value not existing
+// are not cached, so that they are retried on the next read.
+func fetchOnce[K comparable, V any](values *lru.Cache[K, cached[V]], key K, fetch func() (V, error)) (V, error) {
+	entry, ok := values.Get(key)
+	if !ok {
+		value, err := fetch()
+		switch {
+		case err == nil:
+			entry = cached[V]{value: value, found: true}
+		case !errors.Is(err, db.ErrKeyNotFound):
+			return value, err
+		}
+		values.Add(key, entry)
+	}
+
+	if !entry.found {
+		var empty V
+		return empty, db.ErrKeyNotFound
+	}
+	return entry.value, nil
+}
+
+func (s *State) ContractClassHash(addr *felt.Felt) (*felt.Felt, error) {
+	return fetchOnce(s.classHashes, *addr, func() (*felt.Felt, error) {
+		return s.upstream.ClassHashAt(context.Background(), addr)
+	})
+}
+
+func (s *State) ContractNonce(addr *felt.Felt) (*felt.Felt, error) {
+	return fetchOnce(s.nonces, *addr, func() (*felt.Felt, error) {
+		return s.upstream.Nonce(context.Background(), addr)
+	})
+}
+
+func (s *State) ContractStorage(addr, key *felt.Felt) (*felt.Felt, error) {
+	return fetchOnce(s.storage, storageKey{addr: *addr, key: *key}, func() (*felt.Felt, error) {
+		return s.upstream.StorageAt(context.Background(), addr, key)
+	})
+}
+
+// Class returns a class declared upstream. As the upstream does not tell when the class was declared, it is
+// reported as declared at the fork block.
+func (s *State) Class(classHash *felt.Felt) (*core.DeclaredClass, error) {
+	return fetchOnce(s.classes, *classHash, func() (*core.DeclaredClass, error) {
+		class, err := s.upstream.Class(context.Background(), classHash)
+		if err != nil {
+			return nil, err
+		}
+		return &core.DeclaredClass{At: s.upstream.ForkBlock(), Class: class}, nil
+	})
+}
+
+func (s *State) ClassTrie() (*trie.Trie, error) {
+	return nil, ErrTriesUnsupported
+}
+
+func (s *State) ContractTrie() (*trie.Trie, error) {
+	return nil, ErrTriesUnsupported
+}
+
+func (s *State) ContractStorageTrie(addr *felt.Felt) (*trie.Trie, error) {
+	return nil, ErrTriesUnsupported
+}
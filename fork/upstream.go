@@ -0,0 +1,272 @@
+package fork
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/NethermindEth/juno/adapters/rpc2core"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	rpcv6 "github.com/NethermindEth/juno/rpc/v6"
+)
+
+const upstreamTimeout = 30 * time.Second
+
+// Error codes of the upstream that mean that what was asked for does not exist. A missing block is not
+// treated as empty state, since the state is always read at the fork block.
+const (
+	contractNotFoundCode  = 20
+	blockNotFoundCode     = 24
+	classHashNotFoundCode = 28
+)
+
+// UpstreamError is an error returned by the upstream node.
+type UpstreamError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *UpstreamError) Error() string {
+	if len(e.Data) > 0 {
+		return fmt.Sprintf("upstream error %d: %s: %s", e.Code, e.Message, e.Data)
+	}
+	return fmt.Sprintf("upstream error %d: %s", e.Code, e.Message)
+}
+
+// Upstream is a client of the Starknet RPC node a fork is made from. State is always read at the fork block.
+type Upstream struct {
+	url    string
+	client *http.Client
+	block  uint64
+	nextID atomic.Uint64
+}
+
+func NewUpstream(url string, forkBlock uint64) *Upstream {
+	return &Upstream{
+		url:    url,
+		client: &http.Client{Timeout: upstreamTimeout},
+		block:  forkBlock,
+	}
+}
+
+func (u *Upstream) WithHTTPClient(client *http.Client) *Upstream {
+	u.client = client
+	return u
+}
+
+// ForkBlock returns the number of the block the upstream state is read at.
+func (u *Upstream) ForkBlock() uint64 {
+	return u.block
+}
+
+type blockID struct {
+	Number *uint64    `json:"block_number,omitempty"`
+	Hash   *felt.Felt `json:"block_hash,omitempty"`
+}
+
+func (u *Upstream) forkBlockID() blockID {
+	return blockID{Number: &u.block}
+}
+
+type rpcRequest struct {
+	Version string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+	ID      uint64 `json:"id"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *UpstreamError  `json:"error"`
+}
+
+// call sends a request to the upstream and decodes its result into result. Upstream errors meaning that
+// the contract or class does not exist are returned as db.ErrKeyNotFound.
+func (u *Upstream) call(ctx context.Context, method string, params, result any) error {
+	body, err := json.Marshal(rpcRequest{
+		Version: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      u.nextID.Add(1),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := u.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		resBody, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("%s: unexpected status %s: %s", method, res.Status, resBody)
+	}
+
+	var response rpcResponse
+	if err = json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return fmt.Errorf("%s: decode response: %w", method, err)
+	}
+	if response.Error != nil {
+		switch response.Error.Code {
+		case contractNotFoundCode, classHashNotFoundCode:
+			return db.ErrKeyNotFound
+		default:
+			return fmt.Errorf("%s: %w", method, response.Error)
+		}
+	}
+	if err = json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("%s: decode result: %w", method, err)
+	}
+	return nil
+}
+
+func (u *Upstream) ChainID(ctx context.Context) (*felt.Felt, error) {
+	var chainID felt.Felt
+	if err := u.call(ctx, "starknet_chainId", []any{}, &chainID); err != nil {
+		return nil, err
+	}
+	return &chainID, nil
+}
+
+func (u *Upstream) StorageAt(ctx context.Context, addr, key *felt.Felt) (*felt.Felt, error) {
+	var value felt.Felt
+	if err := u.call(ctx, "starknet_getStorageAt", map[string]any{
+		"contract_address": addr,
+		"key":              key,
+		"block_id":         u.forkBlockID(),
+	}, &value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+func (u *Upstream) Nonce(ctx context.Context, addr *felt.Felt) (*felt.Felt, error) {
+	var nonce felt.Felt
+	if err := u.call(ctx, "starknet_getNonce", map[string]any{
+		"contract_address": addr,
+		"block_id":         u.forkBlockID(),
+	}, &nonce); err != nil {
+		return nil, err
+	}
+	return &nonce, nil
+}
+
+func (u *Upstream) ClassHashAt(ctx context.Context, addr *felt.Felt) (*felt.Felt, error) {
+	var classHash felt.Felt
+	if err := u.call(ctx, "starknet_getClassHashAt", map[string]any{
+		"contract_address": addr,
+		"block_id":         u.forkBlockID(),
+	}, &classHash); err != nil {
+		return nil, err
+	}
+	return &classHash, nil
+}
+
+// Class fetches the definition of a class and adapts it, compiling Sierra classes.
+func (u *Upstream) Class(ctx context.Context, classHash *felt.Felt) (core.Class, error) {
+	var definition json.RawMessage
+	if err := u.call(ctx, "starknet_getClass", map[string]any{
+		"class_hash": classHash,
+		"block_id":   u.forkBlockID(),
+	}, &definition); err != nil {
+		return nil, err
+	}
+
+	class, err := rpc2core.AdaptDeclaredClass(definition)
+	if err != nil {
+		return nil, fmt.Errorf("adapt class %s: %w", classHash, err)
+	}
+	return class, nil
+}
+
+type upstreamHeader struct {
+	Hash             *felt.Felt          `json:"block_hash"`
+	ParentHash       *felt.Felt          `json:"parent_hash"`
+	Number           uint64              `json:"block_number"`
+	NewRoot          *felt.Felt          `json:"new_root"`
+	Timestamp        uint64              `json:"timestamp"`
+	SequencerAddress *felt.Felt          `json:"sequencer_address"`
+	L1GasPrice       rpcv6.ResourcePrice `json:"l1_gas_price"`
+	L1DataGasPrice   rpcv6.ResourcePrice `json:"l1_data_gas_price"`
+	L2GasPrice       rpcv6.ResourcePrice `json:"l2_gas_price"`
+	L1DAMode         string              `json:"l1_da_mode"`
+	StarknetVersion  string              `json:"starknet_version"`
+	Transactions     []*felt.Felt        `json:"transactions"`
+}
+
+// HeaderByNumber fetches the header of a block, which must not be after the fork block.
+func (u *Upstream) HeaderByNumber(ctx context.Context, number uint64) (*core.Header, error) {
+	if number > u.block {
+		return nil, db.ErrKeyNotFound
+	}
+	return u.header(ctx, blockID{Number: &number})
+}
+
+// HeaderByHash fetches the header of a block, which must not be after the fork block.
+func (u *Upstream) HeaderByHash(ctx context.Context, hash *felt.Felt) (*core.Header, error) {
+	header, err := u.header(ctx, blockID{Hash: hash})
+	if err != nil {
+		return nil, err
+	}
+	if header.Number > u.block {
+		return nil, db.ErrKeyNotFound
+	}
+	return header, nil
+}
+
+func (u *Upstream) header(ctx context.Context, id blockID) (*core.Header, error) {
+	var block upstreamHeader
+	if err := u.call(ctx, "starknet_getBlockWithTxHashes", map[string]any{"block_id": id}, &block); err != nil {
+		if upstreamErr := (*UpstreamError)(nil); errors.As(err, &upstreamErr) && upstreamErr.Code == blockNotFoundCode {
+			return nil, db.ErrKeyNotFound
+		}
+		return nil, err
+	}
+	if block.Hash == nil {
+		return nil, errors.New("upstream returned a block without a hash")
+	}
+
+	l1DAMode := core.Calldata
+	if block.L1DAMode == "BLOB" {
+		l1DAMode = core.Blob
+	}
+	return &core.Header{
+		Hash:             block.Hash,
+		ParentHash:       block.ParentHash,
+		Number:           block.Number,
+		GlobalStateRoot:  block.NewRoot,
+		SequencerAddress: block.SequencerAddress,
+		TransactionCount: uint64(len(block.Transactions)),
+		Timestamp:        block.Timestamp,
+		ProtocolVersion:  block.StarknetVersion,
+		L1GasPriceETH:    block.L1GasPrice.InWei,
+		L1GasPriceSTRK:   block.L1GasPrice.InFri,
+		L1DAMode:         l1DAMode,
+		L1DataGasPrice:   adaptGasPrice(block.L1DataGasPrice),
+		L2GasPrice:       adaptGasPrice(block.L2GasPrice),
+	}, nil
+}
+
+func adaptGasPrice(price rpcv6.ResourcePrice) *core.GasPrice {
+	return &core.GasPrice{
+		PriceInWei: price.InWei,
+		PriceInFri: price.InFri,
+	}
+}
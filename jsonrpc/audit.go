@@ -0,0 +1,165 @@
+package jsonrpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/utils"
+)
+
+// apiKeyFingerprintSize is the number of bytes of the API key hash kept in audit entries.
+const apiKeyFingerprintSize = 8
+
+// AuditConfig controls which requests are recorded in an AuditLog and what is recorded about them.
+type AuditConfig struct {
+	// SampleRate is the fraction of requests that are recorded, between 0 and 1.
+	SampleRate float64
+	// RedactParams omits request parameters from the entries.
+	RedactParams bool
+	// MaxParamsSize is the number of bytes the encoded parameters are truncated to. Zero means no limit.
+	MaxParamsSize int
+	// APIKeyHeader is the HTTP header identifying the client. Only a fingerprint of its value is recorded,
+	// so that the log can be attributed to clients without leaking their keys.
+	APIKeyHeader string
+}
+
+// AuditEntry describes a single handled request.
+type AuditEntry struct {
+	Time            time.Time `json:"time"`
+	RemoteAddr      string    `json:"remote_addr,omitempty"`
+	APIKey          string    `json:"api_key,omitempty"`
+	Method          string    `json:"method"`
+	Params          string    `json:"params,omitempty"`
+	ParamsTruncated bool      `json:"params_truncated,omitempty"`
+	DurationMs      float64   `json:"duration_ms"`
+	ResponseSize    uint64    `json:"response_size"`
+	ErrorCode       int       `json:"error_code,omitempty"`
+}
+
+// AuditLog writes an entry for every sampled request as a line of JSON. Entries are written once
+// the response has been sent, so that its size is known.
+type AuditLog struct {
+	cfg AuditConfig
+	log utils.SimpleLogger
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewAuditLog(w io.Writer, cfg AuditConfig, log utils.SimpleLogger) *AuditLog {
+	return &AuditLog{
+		cfg: cfg,
+		log: log,
+		w:   w,
+	}
+}
+
+// newEntry returns the entry for req, or nil if req is not sampled.
+func (a *AuditLog) newEntry(ctx context.Context, req *Request, started time.Time) *AuditEntry {
+	if a.cfg.SampleRate < 1 && rand.Float64() >= a.cfg.SampleRate { //nolint:gosec
+		return nil
+	}
+
+	entry := &AuditEntry{
+		Time:   started.UTC(),
+		Method: req.Method,
+	}
+	if client, ok := ctx.Value(clientInfoKey{}).(*clientInfo); ok {
+		entry.RemoteAddr = client.remoteAddr
+		if a.cfg.APIKeyHeader != "" {
+			if key := client.header.Get(a.cfg.APIKeyHeader); key != "" {
+				hash := sha256.Sum256([]byte(key))
+				entry.APIKey = hex.EncodeToString(hash[:apiKeyFingerprintSize])
+			}
+		}
+	}
+
+	if !a.cfg.RedactParams && req.Params != nil {
+		if params, err := json.Marshal(req.Params); err == nil {
+			entry.Params = string(params)
+			if a.cfg.MaxParamsSize > 0 && len(entry.Params) > a.cfg.MaxParamsSize {
+				// Avoid splitting multi-byte characters.
+				entry.Params = strings.ToValidUTF8(entry.Params[:a.cfg.MaxParamsSize], "")
+				entry.ParamsTruncated = true
+			}
+		}
+	}
+	return entry
+}
+
+func (a *AuditLog) record(entry *AuditEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		a.log.Warnw("Failed to encode audit entry", "err", err)
+		return
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err = a.w.Write(line); err != nil {
+		a.log.Warnw("Failed to write audit entry", "err", err)
+	}
+}
+
+// recordResponses records the entries of res, either a *response or a []*response, once it has
+// been written. A non-nil err means the responses did not reach the client as encoded.
+func (a *AuditLog) recordResponses(res any, err error) {
+	var responses []*response
+	switch res := res.(type) {
+	case *response:
+		responses = []*response{res}
+	case []*response:
+		responses = res
+	}
+
+	for _, r := range responses {
+		if r.audit == nil {
+			continue
+		}
+		if err != nil && r.audit.ErrorCode == 0 {
+			r.audit.ErrorCode = InternalError
+			if errors.Is(err, ErrResponseTooLarge) {
+				r.audit.ErrorCode = ResponseTooLarge
+			}
+		}
+		a.record(r.audit)
+	}
+}
+
+type clientInfoKey struct{}
+
+// clientInfo describes the client a request was received from.
+type clientInfo struct {
+	remoteAddr string
+	header     http.Header
+}
+
+// withClientInfo attaches information about the client that sent req to ctx, for the audit log.
+func withClientInfo(ctx context.Context, req *http.Request) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, &clientInfo{
+		remoteAddr: req.RemoteAddr,
+		header:     req.Header,
+	})
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w     io.Writer
+	count uint64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.count += uint64(n)
+	return n, err
+}
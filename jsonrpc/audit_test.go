@@ -0,0 +1,112 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	newServer := func(t *testing.T, cfg jsonrpc.AuditConfig) (*jsonrpc.Server, *bytes.Buffer) {
+		t.Helper()
+		var out bytes.Buffer
+		server := jsonrpc.NewServer(1, utils.NewNopZapLogger()).
+			WithAuditLog(jsonrpc.NewAuditLog(&out, cfg, utils.NewNopZapLogger()))
+		require.NoError(t, server.RegisterMethods(jsonrpc.Method{
+			Name:    "echo",
+			Handler: func(msg string) (string, *jsonrpc.Error) { return msg, nil },
+			Params:  []jsonrpc.Parameter{{Name: "msg"}},
+		}, jsonrpc.Method{
+			Name:    "fail",
+			Handler: func() (any, *jsonrpc.Error) { return nil, jsonrpc.Err(jsonrpc.InternalError, "failed") },
+		}))
+		return server, &out
+	}
+
+	entries := func(t *testing.T, out *bytes.Buffer) []jsonrpc.AuditEntry {
+		t.Helper()
+		var result []jsonrpc.AuditEntry
+		dec := json.NewDecoder(out)
+		for dec.More() {
+			var entry jsonrpc.AuditEntry
+			require.NoError(t, dec.Decode(&entry))
+			result = append(result, entry)
+		}
+		return result
+	}
+
+	t.Run("http request", func(t *testing.T) {
+		server, out := newServer(t, jsonrpc.AuditConfig{SampleRate: 1, APIKeyHeader: "X-Api-Key"})
+
+		body := `{"jsonrpc":"2.0","id":1,"method":"echo","params":["hello"]}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Api-Key", "secret")
+		rr := httptest.NewRecorder()
+		jsonrpc.NewHTTP(server, utils.NewNopZapLogger()).ServeHTTP(rr, req)
+
+		got := entries(t, out)
+		require.Len(t, got, 1)
+		assert.Equal(t, "10.0.0.1:1234", got[0].RemoteAddr)
+		assert.Equal(t, "2bb80d537b1da3e3", got[0].APIKey)
+		assert.Equal(t, "echo", got[0].Method)
+		assert.Equal(t, `["hello"]`, got[0].Params)
+		assert.Equal(t, uint64(rr.Body.Len()), got[0].ResponseSize)
+		assert.Zero(t, got[0].ErrorCode)
+		assert.NotContains(t, out.String(), "secret")
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		server, out := newServer(t, jsonrpc.AuditConfig{SampleRate: 1})
+
+		body := `[{"jsonrpc":"2.0","id":1,"method":"fail"},{"jsonrpc":"2.0","method":"echo","params":["hi"]}]`
+		_, _, err := server.HandleReader(t.Context(), strings.NewReader(body))
+		require.NoError(t, err)
+
+		got := entries(t, out)
+		require.Len(t, got, 2)
+		byMethod := map[string]jsonrpc.AuditEntry{got[0].Method: got[0], got[1].Method: got[1]}
+		assert.Equal(t, jsonrpc.InternalError, byMethod["fail"].ErrorCode)
+		assert.NotZero(t, byMethod["fail"].ResponseSize)
+		// notifications have no response
+		assert.Zero(t, byMethod["echo"].ResponseSize)
+	})
+
+	t.Run("params", func(t *testing.T) {
+		body := `{"jsonrpc":"2.0","id":1,"method":"echo","params":{"msg":"hello"}}`
+		for _, test := range []struct {
+			cfg       jsonrpc.AuditConfig
+			params    string
+			truncated bool
+		}{
+			{cfg: jsonrpc.AuditConfig{SampleRate: 1}, params: `{"msg":"hello"}`},
+			{cfg: jsonrpc.AuditConfig{SampleRate: 1, MaxParamsSize: 8}, params: `{"msg":"`, truncated: true},
+			{cfg: jsonrpc.AuditConfig{SampleRate: 1, RedactParams: true}, params: ""},
+		} {
+			server, out := newServer(t, test.cfg)
+			_, _, err := server.HandleReader(t.Context(), strings.NewReader(body))
+			require.NoError(t, err)
+
+			got := entries(t, out)
+			require.Len(t, got, 1)
+			assert.Equal(t, test.params, got[0].Params)
+			assert.Equal(t, test.truncated, got[0].ParamsTruncated)
+		}
+	})
+
+	t.Run("sampling", func(t *testing.T) {
+		server, out := newServer(t, jsonrpc.AuditConfig{SampleRate: 0})
+		body := `{"jsonrpc":"2.0","id":1,"method":"echo","params":["hello"]}`
+		_, _, err := server.HandleReader(t.Context(), strings.NewReader(body))
+		require.NoError(t, err)
+		assert.Empty(t, entries(t, out))
+	})
+}
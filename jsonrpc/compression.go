@@ -0,0 +1,128 @@
+package jsonrpc
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	encodingGzip = "gzip"
+	encodingZstd = "zstd"
+)
+
+// supportedEncodings lists the content encodings in the order of server preference.
+var supportedEncodings = []string{encodingZstd, encodingGzip}
+
+var (
+	gzipWriters = sync.Pool{
+		New: func() any {
+			return gzip.NewWriter(nil)
+		},
+	}
+	zstdWriters = sync.Pool{
+		New: func() any {
+			// Errors are only returned for invalid options.
+			enc, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+			return enc
+		},
+	}
+)
+
+// negotiateEncoding picks the preferred content encoding accepted by the client
+// according to its Accept-Encoding header. An empty string means no compression.
+func negotiateEncoding(header http.Header) string {
+	accepted := make(map[string]float64)
+	for _, value := range header.Values("Accept-Encoding") {
+		for _, part := range strings.Split(value, ",") {
+			coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			quality := 1.0
+			if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+				parsed, err := strconv.ParseFloat(q, 64)
+				if err != nil {
+					continue
+				}
+				quality = parsed
+			}
+			accepted[strings.ToLower(coding)] = quality
+		}
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range supportedEncodings {
+		quality, ok := accepted[encoding]
+		if !ok {
+			quality, ok = accepted["*"]
+		}
+		if ok && quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+// compressWriter compresses the data written to it with the given content encoding, but only once
+// at least threshold bytes have been written. Smaller responses are sent uncompressed on Close.
+type compressWriter struct {
+	w         http.ResponseWriter
+	encoding  string
+	threshold int
+	pending   []byte
+	enc       io.WriteCloser
+	release   func()
+}
+
+func newCompressWriter(w http.ResponseWriter, encoding string, threshold int) *compressWriter {
+	return &compressWriter{
+		w:         w,
+		encoding:  encoding,
+		threshold: threshold,
+	}
+}
+
+func (c *compressWriter) Write(p []byte) (int, error) {
+	if c.enc != nil {
+		return c.enc.Write(p)
+	}
+
+	c.pending = append(c.pending, p...)
+	if len(c.pending) < c.threshold {
+		return len(p), nil
+	}
+
+	// The threshold is reached, headers must be set before anything is written.
+	c.w.Header().Set("Content-Encoding", c.encoding)
+	switch c.encoding {
+	case encodingZstd:
+		enc := zstdWriters.Get().(*zstd.Encoder)
+		enc.Reset(c.w)
+		c.enc, c.release = enc, func() { zstdWriters.Put(enc) }
+	case encodingGzip:
+		gz := gzipWriters.Get().(*gzip.Writer)
+		gz.Reset(c.w)
+		c.enc, c.release = gz, func() { gzipWriters.Put(gz) }
+	}
+
+	pending := c.pending
+	c.pending = nil
+	if _, err := c.enc.Write(pending); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close flushes any pending data to the underlying writer.
+func (c *compressWriter) Close() error {
+	if c.enc == nil {
+		_, err := c.w.Write(c.pending)
+		return err
+	}
+
+	defer c.release()
+	return c.enc.Close()
+}
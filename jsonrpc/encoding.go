@@ -0,0 +1,231 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/NethermindEth/juno/utils"
+)
+
+// responseBufferSize is the amount of an encoded response held in memory before it is
+// written out. Responses that fail to encode within it can still be replaced by an error.
+const responseBufferSize = 64 * utils.Kilobyte
+
+var ErrResponseTooLarge = errors.New("response too large")
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// messageWriter is implemented by connections that frame messages, such as websockets,
+// so that a single message can be streamed over multiple writes.
+type messageWriter interface {
+	MessageWriter() (io.WriteCloser, error)
+}
+
+// writeResponse streams the encoding of res, either a *response or a []*response, to w.
+//
+// If encoding fails before anything has been written to w, e.g. because the response
+// exceeds the maximum size, an error response is written instead. Otherwise, the output is
+// truncated and the error is returned, the caller should then abort the transmission.
+func (s *Server) writeResponse(w io.Writer, res any) error {
+	sink := &trackingWriter{w: w}
+	buf := bufio.NewWriterSize(sink, responseBufferSize)
+
+	encodeErr := encodeResponse(&limitedWriter{w: buf, limit: s.maxResponseSize}, res)
+	err := encodeErr
+	if err != nil && !sink.written {
+		s.log.Warnw("Failed to encode response", "err", err)
+		buf.Reset(sink)
+		err = encodeResponse(buf, failedResponse(res, err, s.maxResponseSize))
+	}
+	if err == nil {
+		err = buf.Flush()
+	}
+
+	if s.audit != nil {
+		auditErr := err
+		if encodeErr != nil {
+			auditErr = encodeErr
+		}
+		s.audit.recordResponses(res, auditErr)
+	}
+	return err
+}
+
+// writeMessage writes res as a single message if w supports streaming messages.
+//
+// The message is closed even if writing it fails, so that the connection is not left with a
+// half-written frame. The caller should then abort the connection, as the message may be truncated.
+func (s *Server) writeMessage(w io.Writer, res any) (err error) {
+	mw, ok := w.(messageWriter)
+	if !ok {
+		return s.writeResponse(w, res)
+	}
+
+	msg, err := mw.MessageWriter()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, msg.Close())
+	}()
+	return s.writeResponse(msg, res)
+}
+
+func failedResponse(res any, err error, maxResponseSize uint64) *response {
+	failed := &response{Version: "2.0"}
+	if single, ok := res.(*response); ok {
+		failed.ID = single.ID
+	}
+
+	if errors.Is(err, ErrResponseTooLarge) {
+		failed.Error = Err(ResponseTooLarge, fmt.Sprintf("response exceeds the limit of %d bytes", maxResponseSize))
+	} else {
+		failed.Error = Err(InternalError, err.Error())
+	}
+	return failed
+}
+
+func encodeResponse(w io.Writer, res any) error {
+	switch res := res.(type) {
+	case *response:
+		return encodeSingleResponse(w, res)
+	case []*response:
+		if err := writeString(w, "["); err != nil {
+			return err
+		}
+		for idx, r := range res {
+			if idx > 0 {
+				if err := writeString(w, ","); err != nil {
+					return err
+				}
+			}
+			if err := encodeSingleResponse(w, r); err != nil {
+				return err
+			}
+		}
+		return writeString(w, "]")
+	default:
+		return fmt.Errorf("unexpected response type %T", res)
+	}
+}
+
+// encodeSingleResponse produces the same output as json.Marshal(res), streaming the result.
+func encodeSingleResponse(w io.Writer, res *response) error {
+	if res.audit != nil {
+		counter := &countingWriter{w: w}
+		defer func() {
+			res.audit.ResponseSize = counter.count
+		}()
+		w = counter
+	}
+
+	if err := writeString(w, `{"jsonrpc":`); err != nil {
+		return err
+	}
+	if err := writeJSON(w, res.Version); err != nil {
+		return err
+	}
+
+	if res.Result != nil {
+		if err := writeString(w, `,"result":`); err != nil {
+			return err
+		}
+		if err := encodeValue(w, res.Result); err != nil {
+			return err
+		}
+	}
+
+	if res.Error != nil {
+		if err := writeString(w, `,"error":`); err != nil {
+			return err
+		}
+		if err := writeJSON(w, res.Error); err != nil {
+			return err
+		}
+	}
+
+	if err := writeString(w, `,"id":`); err != nil {
+		return err
+	}
+	if err := writeJSON(w, res.ID); err != nil {
+		return err
+	}
+	return writeString(w, "}")
+}
+
+// encodeValue encodes slices one element at a time, so that only a single element
+// is held in memory in its encoded form. Other values are encoded as a whole.
+func encodeValue(w io.Writer, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || rv.IsNil() || rv.Type().Elem().Kind() == reflect.Uint8 ||
+		rv.Type().Implements(jsonMarshalerType) || rv.Type().Implements(textMarshalerType) {
+		return writeJSON(w, v)
+	}
+
+	if err := writeString(w, "["); err != nil {
+		return err
+	}
+	for idx := range rv.Len() {
+		if idx > 0 {
+			if err := writeString(w, ","); err != nil {
+				return err
+			}
+		}
+		// Slice elements are addressable, encode through a pointer like encoding/json does
+		// so that marshalers with pointer receivers are used.
+		if err := writeJSON(w, rv.Index(idx).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	return writeString(w, "]")
+}
+
+func writeJSON(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func writeString(w io.Writer, s string) error {
+	_, err := io.WriteString(w, s)
+	return err
+}
+
+// limitedWriter fails with ErrResponseTooLarge once more than limit bytes are written to it.
+// A zero limit means no limit.
+type limitedWriter struct {
+	w       io.Writer
+	limit   uint64
+	written uint64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.limit > 0 && l.written+uint64(len(p)) > l.limit {
+		return 0, ErrResponseTooLarge
+	}
+	n, err := l.w.Write(p)
+	l.written += uint64(n)
+	return n, err
+}
+
+// trackingWriter records whether anything has been written to the underlying writer.
+type trackingWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	t.written = true
+	return t.w.Write(p)
+}
@@ -0,0 +1,172 @@
+package jsonrpc_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type streamedItem struct {
+	Hash  felt.Felt  `json:"hash"`
+	Value *felt.Felt `json:"value,omitempty"`
+	Note  string     `json:"note"`
+}
+
+func TestStreamingEncoding(t *testing.T) {
+	items := []streamedItem{
+		{Hash: *new(felt.Felt).SetUint64(1), Note: "<escaped>"},
+		{Hash: *new(felt.Felt).SetUint64(2), Value: new(felt.Felt).SetUint64(3)},
+	}
+	felts := []felt.Felt{*new(felt.Felt).SetUint64(4), *new(felt.Felt).SetUint64(5)}
+
+	server := jsonrpc.NewServer(1, utils.NewNopZapLogger())
+	require.NoError(t, server.RegisterMethods(jsonrpc.Method{
+		Name:    "items",
+		Handler: func() ([]streamedItem, *jsonrpc.Error) { return items, nil },
+	}, jsonrpc.Method{
+		Name:    "felts",
+		Handler: func() ([]felt.Felt, *jsonrpc.Error) { return felts, nil },
+	}, jsonrpc.Method{
+		Name:    "empty",
+		Handler: func() ([]felt.Felt, *jsonrpc.Error) { return []felt.Felt{}, nil },
+	}, jsonrpc.Method{
+		Name:    "nil",
+		Handler: func() ([]felt.Felt, *jsonrpc.Error) { return nil, nil },
+	}, jsonrpc.Method{
+		Name:    "bytes",
+		Handler: func() ([]byte, *jsonrpc.Error) { return []byte("abc"), nil },
+	}))
+
+	tests := map[string]any{
+		"items": items,
+		"felts": felts,
+		"empty": []felt.Felt{},
+		"nil":   []felt.Felt(nil),
+		"bytes": []byte("abc"),
+	}
+	for method, result := range tests {
+		t.Run(method, func(t *testing.T) {
+			want, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "result": result, "id": 1})
+			require.NoError(t, err)
+
+			req := `{"jsonrpc":"2.0","id":1,"method":"` + method + `"}`
+			got, _, err := server.HandleReader(t.Context(), strings.NewReader(req))
+			require.NoError(t, err)
+			assert.JSONEq(t, string(want), string(got))
+		})
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	server := jsonrpc.NewServer(1, utils.NewNopZapLogger()).WithMaxResponseSize(utils.Megabyte)
+	require.NoError(t, server.RegisterMethods(jsonrpc.Method{
+		Name: "kilobytes",
+		Handler: func(count int) ([]string, *jsonrpc.Error) {
+			result := make([]string, count)
+			for idx := range result {
+				result[idx] = strings.Repeat("a", utils.Kilobyte)
+			}
+			return result, nil
+		},
+		Params: []jsonrpc.Parameter{{Name: "count"}},
+	}))
+
+	call := func(t *testing.T, count string) string {
+		t.Helper()
+		req := `{"jsonrpc":"2.0","id":1,"method":"kilobytes","params":[` + count + `]}`
+		res, _, err := server.HandleReader(t.Context(), strings.NewReader(req))
+		require.NoError(t, err)
+		return string(res)
+	}
+
+	t.Run("within limit", func(t *testing.T) {
+		assert.Contains(t, call(t, "16"), `"result":["aaa`)
+	})
+
+	t.Run("exceeding limit before anything is sent", func(t *testing.T) {
+		small := jsonrpc.NewServer(1, utils.NewNopZapLogger()).WithMaxResponseSize(utils.Kilobyte)
+		require.NoError(t, small.RegisterMethods(jsonrpc.Method{
+			Name:    "big",
+			Handler: func() (string, *jsonrpc.Error) { return strings.Repeat("a", 2*utils.Kilobyte), nil },
+		}))
+
+		for _, req := range []struct {
+			body string
+			want string
+		}{
+			{
+				body: `{"jsonrpc":"2.0","id":1,"method":"big"}`,
+				want: `{"jsonrpc":"2.0","error":{"code":-32002,"message":"Response too large",` +
+					`"data":"response exceeds the limit of 1024 bytes"},"id":1}`,
+			},
+			{
+				body: `[{"jsonrpc":"2.0","id":1,"method":"big"}]`,
+				want: `{"jsonrpc":"2.0","error":{"code":-32002,"message":"Response too large",` +
+					`"data":"response exceeds the limit of 1024 bytes"},"id":null}`,
+			},
+		} {
+			res, _, err := small.HandleReader(t.Context(), strings.NewReader(req.body))
+			require.NoError(t, err)
+			assert.Equal(t, req.want, string(res))
+		}
+	})
+
+	t.Run("exceeding limit while streaming aborts the response", func(t *testing.T) {
+		srv := httptest.NewServer(jsonrpc.NewHTTP(server, utils.NewNopZapLogger()))
+		t.Cleanup(srv.Close)
+
+		body := `{"jsonrpc":"2.0","id":1,"method":"kilobytes","params":[2048]}`
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, srv.URL, strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, resp.Body.Close())
+		})
+
+		_, err = io.ReadAll(resp.Body)
+		require.Error(t, err)
+	})
+
+	t.Run("exceeding limit while streaming closes the message", func(t *testing.T) {
+		body := `{"jsonrpc":"2.0","id":1,"method":"kilobytes","params":[2048]}`
+		conn := &messageConn{Reader: strings.NewReader(body)}
+		require.ErrorIs(t, server.HandleReadWriter(t.Context(), conn), jsonrpc.ErrResponseTooLarge)
+		require.NotNil(t, conn.msg)
+		assert.True(t, conn.msg.closed)
+	})
+}
+
+// messageConn is a connection that frames messages, like a websocket.
+type messageConn struct {
+	io.Reader
+	msg *recordedMessage
+}
+
+func (c *messageConn) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (c *messageConn) MessageWriter() (io.WriteCloser, error) {
+	c.msg = new(recordedMessage)
+	return c.msg, nil
+}
+
+type recordedMessage struct {
+	strings.Builder
+	closed bool
+}
+
+func (m *recordedMessage) Close() error {
+	m.closed = true
+	return nil
+}
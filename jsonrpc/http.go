@@ -1,6 +1,7 @@
 package jsonrpc
 
 import (
+	"io"
 	"maps"
 	"net/http"
 
@@ -14,6 +15,9 @@ type HTTP struct {
 	log utils.SimpleLogger
 
 	listener NewRequestListener
+
+	compression          bool
+	compressionThreshold int
 }
 
 func NewHTTP(rpc *Server, log utils.SimpleLogger) *HTTP {
@@ -31,6 +35,14 @@ func (h *HTTP) WithListener(listener NewRequestListener) *HTTP {
 	return h
 }
 
+// WithCompression enables gzip and zstd compression of responses that are at least
+// threshold bytes long, using the encoding negotiated with the client's Accept-Encoding header.
+func (h *HTTP) WithCompression(threshold int) *HTTP {
+	h.compression = true
+	h.compressionThreshold = threshold
+	return h
+}
+
 // ServeHTTP processes an incoming HTTP request
 func (h *HTTP) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
 	if req.Method == http.MethodGet {
@@ -47,19 +59,46 @@ func (h *HTTP) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
 
 	req.Body = http.MaxBytesReader(writer, req.Body, MaxRequestBodySize)
 	h.listener.OnNewRequest("any")
-	resp, header, err := h.rpc.HandleReader(req.Context(), req.Body)
+	resp, header := h.rpc.handleReader(withClientInfo(req.Context(), req), req.Body)
 
 	writer.Header().Set("Content-Type", "application/json")
 	maps.Copy(writer.Header(), header) // overwrites duplicate headers
+	if h.compression {
+		writer.Header().Add("Vary", "Accept-Encoding")
+	}
+
+	if resp == nil {
+		return
+	}
 
+	var out io.Writer = writer
+	compressor := h.compressor(writer, req)
+	if compressor != nil {
+		out = compressor
+	}
+
+	err := h.rpc.writeResponse(out, resp)
+	if err == nil && compressor != nil {
+		err = compressor.Close()
+	}
 	if err != nil {
-		h.log.Errorw("Handler failure", "err", err)
-		writer.WriteHeader(http.StatusInternalServerError)
+		h.log.Warnw("Failed writing response", "err", err)
+		// Part of the response might have been sent already, abort so that the client
+		// doesn't mistake a truncated response for a complete one.
+		panic(http.ErrAbortHandler)
 	}
-	if resp != nil {
-		_, err = writer.Write(resp)
-		if err != nil {
-			h.log.Warnw("Failed writing response", "err", err)
-		}
+}
+
+// compressor returns a writer compressing the response if compression is enabled and
+// the client accepts one of the supported encodings, nil otherwise.
+func (h *HTTP) compressor(writer http.ResponseWriter, req *http.Request) *compressWriter {
+	if !h.compression {
+		return nil
+	}
+
+	encoding := negotiateEncoding(req.Header)
+	if encoding == "" {
+		return nil
 	}
+	return newCompressWriter(writer, encoding, h.compressionThreshold)
 }
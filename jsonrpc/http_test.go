@@ -2,14 +2,17 @@ package jsonrpc_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/NethermindEth/juno/jsonrpc"
 	"github.com/NethermindEth/juno/utils"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -74,3 +77,82 @@ func TestHTTP(t *testing.T) {
 		assert.Len(t, listener.OnNewRequestLogs, 1)
 	})
 }
+
+func TestHTTPCompression(t *testing.T) {
+	method := jsonrpc.Method{
+		Name: "echo",
+		Handler: func(msg string) (string, *jsonrpc.Error) {
+			return msg, nil
+		},
+		Params: []jsonrpc.Parameter{{Name: "msg"}},
+	}
+	log := utils.NewNopZapLogger()
+	rpc := jsonrpc.NewServer(1, log)
+	require.NoError(t, rpc.RegisterMethods(method))
+
+	srv := httptest.NewServer(jsonrpc.NewHTTP(rpc, log).WithCompression(100))
+	t.Cleanup(srv.Close)
+
+	// The transport would otherwise transparently request and decode gzip.
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	post := func(t *testing.T, msg, acceptEncoding string) *http.Response {
+		t.Helper()
+		body := `{"jsonrpc" : "2.0", "method" : "echo", "params" : [ "` + msg + `" ], "id" : 1}`
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, srv.URL, strings.NewReader(body))
+		require.NoError(t, err)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, resp.Body.Close())
+		})
+		return resp
+	}
+
+	longMsg := strings.Repeat("a", 1000)
+	want := `{"jsonrpc":"2.0","result":"` + longMsg + `","id":1}`
+
+	t.Run("gzip", func(t *testing.T) {
+		resp := post(t, longMsg, "gzip")
+		require.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+		gz, err := gzip.NewReader(resp.Body)
+		require.NoError(t, err)
+		got, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(got))
+	})
+
+	t.Run("zstd is preferred", func(t *testing.T) {
+		resp := post(t, longMsg, "gzip, deflate, zstd")
+		require.Equal(t, "zstd", resp.Header.Get("Content-Encoding"))
+		dec, err := zstd.NewReader(resp.Body)
+		require.NoError(t, err)
+		defer dec.Close()
+		got, err := io.ReadAll(dec)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(got))
+	})
+
+	t.Run("q-values are respected", func(t *testing.T) {
+		resp := post(t, longMsg, "zstd;q=0, gzip;q=0.5")
+		require.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	})
+
+	t.Run("no accepted encoding", func(t *testing.T) {
+		resp := post(t, longMsg, "br")
+		require.Empty(t, resp.Header.Get("Content-Encoding"))
+		got, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(got))
+	})
+
+	t.Run("below threshold", func(t *testing.T) {
+		resp := post(t, "abc123", "gzip")
+		require.Empty(t, resp.Header.Get("Content-Encoding"))
+		got, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"jsonrpc":"2.0","result":"abc123","id":1}`, string(got))
+	})
+}
@@ -20,11 +20,13 @@ import (
 )
 
 const (
-	InvalidJSON    = -32700 // Invalid JSON was received by the server.
-	InvalidRequest = -32600 // The JSON sent is not a valid Request object.
-	MethodNotFound = -32601 // The method does not exist / is not available.
-	InvalidParams  = -32602 // Invalid method parameter(s).
-	InternalError  = -32603 // Internal JSON-RPC error.
+	InvalidJSON      = -32700 // Invalid JSON was received by the server.
+	InvalidRequest   = -32600 // The JSON sent is not a valid Request object.
+	MethodNotFound   = -32601 // The method does not exist / is not available.
+	InvalidParams    = -32602 // Invalid method parameter(s).
+	InternalError    = -32603 // Internal JSON-RPC error.
+	RequestTimeout   = -32001 // The request did not complete within the time allowed for the method.
+	ResponseTooLarge = -32002 // The response exceeds the maximum size allowed by the server.
 )
 
 var (
@@ -46,6 +48,9 @@ type response struct {
 	Result  any    `json:"result,omitempty"`
 	Error   *Error `json:"error,omitempty"`
 	ID      any    `json:"id"`
+
+	// audit is the entry recorded once the response has been written, if any.
+	audit *AuditEntry
 }
 
 type Error struct {
@@ -71,6 +76,10 @@ func Err(code int, data any) *Error {
 		return &Error{Code: MethodNotFound, Message: "Method Not Found", Data: data}
 	case InvalidParams:
 		return &Error{Code: InvalidParams, Message: "Invalid Params", Data: data}
+	case RequestTimeout:
+		return &Error{Code: RequestTimeout, Message: "Request timed out", Data: data}
+	case ResponseTooLarge:
+		return &Error{Code: ResponseTooLarge, Message: "Response too large", Data: data}
 	default:
 		return &Error{Code: InternalError, Message: "Internal error", Data: data}
 	}
@@ -122,11 +131,14 @@ type Method struct {
 }
 
 type Server struct {
-	methods   map[string]Method
-	validator Validator
-	pool      *pool.Pool
-	log       utils.SimpleLogger
-	listener  EventListener
+	methods         map[string]Method
+	timeouts        map[string]time.Duration
+	maxResponseSize uint64
+	validator       Validator
+	pool            *pool.Pool
+	log             utils.SimpleLogger
+	listener        EventListener
+	audit           *AuditLog
 }
 
 type Validator interface {
@@ -151,6 +163,34 @@ func (s *Server) WithValidator(validator Validator) *Server {
 	return s
 }
 
+// WithMethodTimeouts sets the maximum duration a request for the given methods may run for.
+// Method names are matched case-insensitively, as configuration keys may have been lowercased.
+// Only handlers that take a context are subject to timeouts, since others could not be stopped: they
+// receive a context with the corresponding deadline, and if the deadline expires before the handler
+// returns, a RequestTimeout error is sent back to the client.
+func (s *Server) WithMethodTimeouts(timeouts map[string]time.Duration) *Server {
+	s.timeouts = make(map[string]time.Duration, len(timeouts))
+	for method, timeout := range timeouts {
+		s.timeouts[strings.ToLower(method)] = timeout
+	}
+	return s
+}
+
+// WithMaxResponseSize sets the maximum size in bytes of an encoded response, including batch
+// responses as a whole. Responses are streamed to the client as they are encoded, so a response
+// exceeding the limit is replaced with a ResponseTooLarge error if none of it has been sent yet,
+// otherwise its transmission is aborted. Zero means no limit.
+func (s *Server) WithMaxResponseSize(size uint64) *Server {
+	s.maxResponseSize = size
+	return s
+}
+
+// WithAuditLog records the handled requests in the given AuditLog.
+func (s *Server) WithAuditLog(audit *AuditLog) *Server {
+	s.audit = audit
+	return s
+}
+
 // WithListener registers an EventListener
 func (s *Server) WithListener(listener EventListener) *Server {
 	s.listener = listener
@@ -276,13 +316,9 @@ func (s *Server) HandleReadWriter(ctx context.Context, rw io.ReadWriter) error {
 	}
 	msgCtx := context.WithValue(ctx, ConnKey{}, conn)
 	// header is unnecessary for read-writer(websocket)
-	resp, _, err := s.HandleReader(msgCtx, rw)
-	if err != nil {
-		conn.initialErr = err
-		return err
-	}
+	resp, _ := s.handleReader(msgCtx, rw)
 	if resp != nil {
-		if _, err = rw.Write(resp); err != nil {
+		if err := s.writeMessage(rw, resp); err != nil {
 			conn.initialErr = err
 			return err
 		}
@@ -294,6 +330,21 @@ func (s *Server) HandleReadWriter(ctx context.Context, rw io.ReadWriter) error {
 // It returns the response in a byte array, only returns an
 // error if it can not create the response byte array
 func (s *Server) HandleReader(ctx context.Context, reader io.Reader) ([]byte, http.Header, error) {
+	resp, header := s.handleReader(ctx, reader)
+	if resp == nil {
+		return nil, header, nil
+	}
+
+	var buf bytes.Buffer
+	if err := s.writeResponse(&buf, resp); err != nil {
+		return nil, header, err
+	}
+	return buf.Bytes(), header, nil
+}
+
+// handleReader processes a request to the server and returns the response to be encoded,
+// either a *response or a []*response. A nil response means nothing should be sent back.
+func (s *Server) handleReader(ctx context.Context, reader io.Reader) (any, http.Header) {
 	bufferedReader := bufio.NewReaderSize(reader, bufferSize)
 	requestIsBatch := isBatch(bufferedReader)
 	res := &response{
@@ -332,29 +383,23 @@ func (s *Server) HandleReader(ctx context.Context, reader io.Reader) ([]byte, ht
 	}
 
 	if res == nil {
-		return nil, header, nil
+		return nil, header
 	}
-
-	result, err := json.Marshal(res)
-	return result, header, err
+	return res, header
 }
 
-func (s *Server) handleBatchRequest(ctx context.Context, batchReq []json.RawMessage) ([]byte, http.Header, error) {
+func (s *Server) handleBatchRequest(ctx context.Context, batchReq []json.RawMessage) (any, http.Header) {
 	var (
 		mutex     sync.Mutex
-		responses []json.RawMessage
+		responses []*response
 		headers   []http.Header
 	)
 
-	addResponse := func(response any, header http.Header) {
-		if responseJSON, err := json.Marshal(response); err != nil {
-			s.log.Errorw("failed to marshal response", "err", err)
-		} else {
-			mutex.Lock()
-			responses = append(responses, responseJSON)
-			headers = append(headers, header)
-			mutex.Unlock()
-		}
+	addResponse := func(response *response, header http.Header) {
+		mutex.Lock()
+		responses = append(responses, response)
+		headers = append(headers, header)
+		mutex.Unlock()
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -409,12 +454,10 @@ func (s *Server) handleBatchRequest(ctx context.Context, batchReq []json.RawMess
 
 	// according to the spec if there are no response objects server must not return empty array
 	if len(responses) == 0 {
-		return nil, finalHeaders, nil
+		return nil, finalHeaders
 	}
 
-	result, err := json.Marshal(responses)
-
-	return result, finalHeaders, err // todo: fix batch request aggregate header
+	return responses, finalHeaders // todo: fix batch request aggregate header
 }
 
 func isBatch(reader *bufio.Reader) bool {
@@ -448,6 +491,35 @@ func isNilOrEmpty(i any) (bool, error) {
 }
 
 func (s *Server) handleRequest(ctx context.Context, req *Request) (*response, http.Header, error) {
+	if s.audit == nil {
+		return s.processRequest(ctx, req)
+	}
+
+	started := time.Now()
+	entry := s.audit.newEntry(ctx, req, started)
+	res, header, err := s.processRequest(ctx, req)
+	if entry == nil {
+		return res, header, err
+	}
+
+	entry.DurationMs = float64(time.Since(started).Microseconds()) / 1000
+	switch {
+	case err != nil:
+		entry.ErrorCode = InvalidRequest
+		s.audit.record(entry)
+	case res == nil: // notification
+		s.audit.record(entry)
+	default:
+		if res.Error != nil {
+			entry.ErrorCode = res.Error.Code
+		}
+		// Recorded once the response is written.
+		res.audit = entry
+	}
+	return res, header, err
+}
+
+func (s *Server) processRequest(ctx context.Context, req *Request) (*response, http.Header, error) {
 	s.log.Tracew("Received request", "req", req)
 
 	header := http.Header{}
@@ -470,6 +542,20 @@ func (s *Server) handleRequest(ctx context.Context, req *Request) (*response, ht
 
 	handlerTimer := time.Now()
 	s.listener.OnNewRequest(req.Method)
+
+	var (
+		timeout    time.Duration
+		hasTimeout bool
+	)
+	if len(s.timeouts) > 0 && calledMethod.needsContext {
+		timeout, hasTimeout = s.timeouts[strings.ToLower(req.Method)]
+	}
+	if hasTimeout {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	args, err := s.buildArguments(ctx, req.Params, calledMethod)
 	if err != nil {
 		res.Error = Err(InvalidParams, err.Error())
@@ -480,12 +566,24 @@ func (s *Server) handleRequest(ctx context.Context, req *Request) (*response, ht
 		s.listener.OnRequestHandled(req.Method, time.Since(handlerTimer))
 	}()
 
-	tuple := reflect.ValueOf(calledMethod.Handler).Call(args)
+	var tuple []reflect.Value
+	if hasTimeout {
+		tuple = callWithDeadline(ctx, calledMethod.Handler, args)
+	} else {
+		tuple = reflect.ValueOf(calledMethod.Handler).Call(args)
+	}
 	if res.ID == nil { // notification
 		s.log.Tracew("Notification received, no response expected")
 		return nil, header, nil
 	}
 
+	if tuple == nil {
+		res.Error = Err(RequestTimeout, fmt.Sprintf("%s exceeded its %s timeout", req.Method, timeout))
+		s.listener.OnRequestFailed(req.Method, res.Error)
+		s.log.Debugw("RPC request timed out", "method", req.Method, "timeout", timeout)
+		return res, header, nil
+	}
+
 	errorIndex := 1
 	if len(tuple) == 3 {
 		errorIndex = 2
@@ -507,6 +605,27 @@ func (s *Server) handleRequest(ctx context.Context, req *Request) (*response, ht
 	return res, header, nil
 }
 
+// callWithDeadline calls the handler, which takes ctx, in a separate goroutine and waits for it until
+// ctx expires. A nil result means the deadline was exceeded. The handler is expected to observe the
+// cancellation of ctx and return early.
+func callWithDeadline(ctx context.Context, handler any, args []reflect.Value) []reflect.Value {
+	done := make(chan []reflect.Value, 1)
+	go func() {
+		done <- reflect.ValueOf(handler).Call(args)
+	}()
+
+	select {
+	case tuple := <-done:
+		// The handler may have returned early because of the deadline.
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil
+		}
+		return tuple
+	case <-ctx.Done():
+		return nil
+	}
+}
+
 //nolint:gocyclo
 func (s *Server) buildArguments(ctx context.Context, params any, method Method) ([]reflect.Value, error) {
 	handlerType := reflect.TypeOf(method.Handler)
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/NethermindEth/juno/jsonrpc"
 	"github.com/NethermindEth/juno/utils"
@@ -591,6 +592,54 @@ func TestCannotWriteToConnInHandler(t *testing.T) {
 	require.NotNil(t, header)
 }
 
+func TestMethodTimeouts(t *testing.T) {
+	server := jsonrpc.NewServer(1, utils.NewNopZapLogger()).WithMethodTimeouts(map[string]time.Duration{
+		"slow":        10 * time.Millisecond,
+		"without-ctx": 10 * time.Millisecond,
+		"fast":        time.Minute,
+	})
+	require.NoError(t, server.RegisterMethods(jsonrpc.Method{
+		Name: "slow",
+		Handler: func(ctx context.Context) (int, *jsonrpc.Error) {
+			<-ctx.Done()
+			return 0, jsonrpc.Err(jsonrpc.InternalError, ctx.Err().Error())
+		},
+	}, jsonrpc.Method{
+		Name: "without-ctx",
+		Handler: func() (int, *jsonrpc.Error) {
+			time.Sleep(20 * time.Millisecond)
+			return 2, nil
+		},
+	}, jsonrpc.Method{
+		Name: "fast",
+		Handler: func(ctx context.Context) (int, *jsonrpc.Error) {
+			_, hasDeadline := ctx.Deadline()
+			require.True(t, hasDeadline)
+			return 1, nil
+		},
+	}))
+
+	t.Run("slow", func(t *testing.T) {
+		res, _, err := server.HandleReader(t.Context(), strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"slow"}`))
+		require.NoError(t, err)
+		timedOut := `{"jsonrpc":"2.0","error":{"code":-32001,"message":"Request timed out","data":"slow exceeded its 10ms timeout"},"id":1}`
+		assert.Equal(t, timedOut, string(res))
+	})
+
+	// handlers without a context could not be stopped, so they are not timed out
+	t.Run("without context", func(t *testing.T) {
+		res, _, err := server.HandleReader(t.Context(), strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"without-ctx"}`))
+		require.NoError(t, err)
+		assert.Equal(t, `{"jsonrpc":"2.0","result":2,"id":1}`, string(res))
+	})
+
+	t.Run("within deadline", func(t *testing.T) {
+		res, _, err := server.HandleReader(t.Context(), strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"fast"}`))
+		require.NoError(t, err)
+		assert.Equal(t, `{"jsonrpc":"2.0","result":1,"id":1}`, string(res))
+	})
+}
+
 type fakeConn struct{}
 
 func (fc *fakeConn) Write(p []byte) (int, error) {
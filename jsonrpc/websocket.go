@@ -54,6 +54,14 @@ func (ws *Websocket) WithConnParams(p *WebsocketConnParams) *Websocket {
 	return ws
 }
 
+// WithCompression negotiates permessage-deflate compression with clients that support it.
+// Messages shorter than threshold bytes are sent uncompressed.
+func (ws *Websocket) WithCompression(threshold int) *Websocket {
+	ws.connParams.Compression = true
+	ws.connParams.CompressionThreshold = threshold
+	return ws
+}
+
 // WithListener registers a NewRequestListener
 func (ws *Websocket) WithListener(listener NewRequestListener) *Websocket {
 	ws.listener = listener
@@ -80,7 +88,7 @@ func (ws *Websocket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	defer ws.connSem.Release(1)
 
-	conn, err := websocket.Accept(w, r, nil /* TODO: options */)
+	conn, err := websocket.Accept(w, r, ws.acceptOptions())
 	if err != nil {
 		ws.log.Errorw("Failed to upgrade connection", "err", err)
 		return
@@ -88,7 +96,7 @@ func (ws *Websocket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// TODO include connection information, such as the remote address, in the logs.
 
-	ctx, cancel := context.WithCancel(r.Context())
+	ctx, cancel := context.WithCancel(withClientInfo(r.Context(), r))
 	defer cancel()
 	go func() {
 		select {
@@ -138,11 +146,26 @@ func (ws *Websocket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (ws *Websocket) acceptOptions() *websocket.AcceptOptions {
+	if !ws.connParams.Compression {
+		return nil
+	}
+	return &websocket.AcceptOptions{
+		// Context takeover is not used to avoid keeping a sliding window in memory for every connection.
+		CompressionMode:      websocket.CompressionNoContextTakeover,
+		CompressionThreshold: ws.connParams.CompressionThreshold,
+	}
+}
+
 type WebsocketConnParams struct {
 	// Maximum message size allowed.
 	ReadLimit int64
 	// Maximum time to write a message.
 	WriteDuration time.Duration
+	// Negotiate permessage-deflate compression with clients that support it.
+	Compression bool
+	// Minimum size of a message before compression is applied.
+	CompressionThreshold int
 }
 
 func DefaultWebsocketConnParams() *WebsocketConnParams {
@@ -185,3 +208,27 @@ func (wsc *websocketConn) Write(p []byte) (int, error) {
 	}
 	return len(p), nil
 }
+
+var _ messageWriter = (*websocketConn)(nil)
+
+// MessageWriter returns a writer for a single text message, which is sent once the writer is closed.
+// The whole message must be written within the connection's write duration.
+func (wsc *websocketConn) MessageWriter() (io.WriteCloser, error) {
+	writeCtx, writeCancel := context.WithTimeout(wsc.ctx, wsc.params.WriteDuration)
+	w, err := wsc.conn.Writer(writeCtx, websocket.MessageText)
+	if err != nil {
+		writeCancel()
+		return nil, err
+	}
+	return &websocketMessageWriter{WriteCloser: w, cancel: writeCancel}, nil
+}
+
+type websocketMessageWriter struct {
+	io.WriteCloser
+	cancel context.CancelFunc
+}
+
+func (w *websocketMessageWriter) Close() error {
+	defer w.cancel()
+	return w.WriteCloser.Close()
+}
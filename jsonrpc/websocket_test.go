@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -129,3 +130,33 @@ func TestWebsocketConnectionLimit(t *testing.T) {
 	require.Equal(t, http.StatusSwitchingProtocols, resp4.StatusCode)
 	require.NoError(t, conn4.Close(websocket.StatusNormalClosure, ""))
 }
+
+func TestWebsocketCompression(t *testing.T) {
+	method := jsonrpc.Method{
+		Name:   "test_echo",
+		Params: []jsonrpc.Parameter{{Name: "msg"}},
+		Handler: func(msg string) (string, *jsonrpc.Error) {
+			return msg, nil
+		},
+	}
+	rpc := jsonrpc.NewServer(1, utils.NewNopZapLogger())
+	require.NoError(t, rpc.RegisterMethods(method))
+	srv := httptest.NewServer(jsonrpc.NewWebsocket(rpc, nil, utils.NewNopZapLogger()).WithCompression(100))
+	t.Cleanup(srv.Close)
+
+	conn, resp, err := websocket.Dial(t.Context(), srv.URL, &websocket.DialOptions{ //nolint:bodyclose // websocket package closes resp.Body for us.
+		CompressionMode: websocket.CompressionNoContextTakeover,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+
+	longMsg := strings.Repeat("a", 1000)
+	msg := `{"jsonrpc" : "2.0", "method" : "test_echo", "params" : [ "` + longMsg + `" ], "id" : 1}`
+	require.NoError(t, conn.Write(t.Context(), websocket.MessageText, []byte(msg)))
+
+	_, got, err := conn.Read(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":"`+longMsg+`","id":1}`, string(got))
+
+	require.NoError(t, conn.Close(websocket.StatusNormalClosure, ""))
+}
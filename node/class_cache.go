@@ -0,0 +1,119 @@
+package node
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
+)
+
+// classCacheWarmClasses is the number of most used classes the class cache is warmed with.
+const classCacheWarmClasses = 64
+
+// classCacheWarmer fills the VM class cache with the classes used the most in recent blocks.
+type classCacheWarmer struct {
+	cache  *vm.ClassCache
+	chain  *blockchain.Blockchain
+	blocks uint64
+	log    utils.SimpleLogger
+}
+
+func (w *classCacheWarmer) Run(ctx context.Context) error {
+	if err := w.warm(ctx); err != nil {
+		w.log.Warnw("Failed to warm the VM class cache", "err", err)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (w *classCacheWarmer) warm(ctx context.Context) error {
+	head, err := w.chain.Height()
+	if err != nil {
+		// nothing to warm the cache with yet
+		return nil
+	}
+
+	// contracts are counted by the transactions they sent or received and by the events they emitted
+	uses := make(map[felt.Felt]int)
+	for blockNumber := head; blockNumber+w.blocks > head; blockNumber-- {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		block, err := w.chain.BlockByNumber(blockNumber)
+		if err != nil {
+			return err
+		}
+		for _, txn := range block.Transactions {
+			if addr := transactionContract(txn); addr != nil {
+				uses[*addr]++
+			}
+		}
+		for _, receipt := range block.Receipts {
+			for _, event := range receipt.Events {
+				uses[*event.From]++
+			}
+		}
+
+		if blockNumber == 0 {
+			break
+		}
+	}
+
+	state, closer, err := w.chain.HeadState()
+	if err != nil {
+		return err
+	}
+	defer closer() //nolint:errcheck
+
+	classUses := make(map[felt.Felt]int)
+	for addr, count := range uses {
+		classHash, err := state.ContractClassHash(&addr)
+		if err != nil {
+			continue
+		}
+		classUses[*classHash] += count
+	}
+
+	classHashes := slices.SortedFunc(maps.Keys(classUses), func(a, b felt.Felt) int {
+		return cmp.Compare(classUses[b], classUses[a])
+	})
+	if len(classHashes) > classCacheWarmClasses {
+		classHashes = classHashes[:classCacheWarmClasses]
+	}
+	// the most used classes are added last so that they are evicted last
+	slices.Reverse(classHashes)
+
+	if err = w.cache.Warm(state, utils.Map(classHashes, func(classHash felt.Felt) *felt.Felt {
+		return &classHash
+	})); err != nil {
+		return err
+	}
+	w.log.Infow("Warmed the VM class cache", "classes", len(classHashes))
+	return nil
+}
+
+// transactionContract returns the contract a transaction is sent from, or to for L1 handlers.
+func transactionContract(txn core.Transaction) *felt.Felt {
+	switch t := txn.(type) {
+	case *core.InvokeTransaction:
+		if t.SenderAddress != nil {
+			return t.SenderAddress
+		}
+		return t.ContractAddress
+	case *core.DeclareTransaction:
+		return t.SenderAddress
+	case *core.DeployAccountTransaction:
+		return t.ContractAddress
+	case *core.L1HandlerTransaction:
+		return t.ContractAddress
+	default:
+		return nil
+	}
+}
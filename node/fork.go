@@ -0,0 +1,96 @@
+package node
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+
+	"github.com/NethermindEth/juno/fork"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/rpc"
+	"github.com/NethermindEth/juno/service"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/validator"
+	"github.com/NethermindEth/juno/vm"
+	"github.com/sourcegraph/conc"
+)
+
+// ForkConfig is the configuration of a node forking an upstream Starknet RPC node.
+type ForkConfig struct {
+	Upstream        string
+	AtBlock         uint64
+	Network         utils.Network
+	HTTPHost        string
+	HTTPPort        uint16
+	MaxVMs          uint
+	MaxVMQueue      uint
+	RPCCallMaxSteps uint
+}
+
+// Fork is a node serving the RPC on top of the state of an upstream node at a given block. Transactions added
+// through the RPC are executed locally and never sent to the upstream.
+type Fork struct {
+	chain    *fork.Chain
+	services []service.Service
+	log      utils.SimpleLogger
+}
+
+func NewFork(ctx context.Context, cfg *ForkConfig, version string, log utils.Logger) (*Fork, error) {
+	chain, err := fork.NewChain(ctx, fork.NewUpstream(cfg.Upstream, cfg.AtBlock), &cfg.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	throttledVM := NewThrottledVM(vm.New(false, log), cfg.MaxVMs, int32(cfg.MaxVMQueue))
+	rpcHandler := rpc.New(chain, chain, throttledVM, version, log, &cfg.Network).
+		WithGateway(fork.NewSequencer(chain, throttledVM, log)).
+		WithCallMaxSteps(uint64(cfg.RPCCallMaxSteps))
+
+	maxGoroutines := 2 * runtime.GOMAXPROCS(0)
+	rpcServers := make(map[string]*jsonrpc.Server)
+	for _, methods := range []func() ([]jsonrpc.Method, string){
+		rpcHandler.MethodsV0_8, rpcHandler.MethodsV0_7, rpcHandler.MethodsV0_6,
+	} {
+		server := jsonrpc.NewServer(maxGoroutines, log).WithValidator(validator.Validator())
+		versionMethods, path := methods()
+		if err = server.RegisterMethods(versionMethods...); err != nil {
+			return nil, err
+		}
+		rpcServers[path] = server
+		rpcServers["/rpc"+path] = server
+	}
+	_, pathV08 := rpcHandler.MethodsV0_8()
+	rpcServers["/"] = rpcServers[pathV08]
+	rpcServers["/rpc"] = rpcServers[pathV08]
+
+	return &Fork{
+		chain: chain,
+		services: []service.Service{
+			rpcHandler,
+			makeRPCOverHTTP(cfg.HTTPHost, cfg.HTTPPort, rpcServers, nil, log, false, false, nil),
+		},
+		log: log,
+	}, nil
+}
+
+func (f *Fork) Run(ctx context.Context) {
+	forkHeader := f.chain.ForkHeader()
+	f.log.Infow("Forked upstream", "number", forkHeader.Number, "hash", forkHeader.Hash.ShortString())
+
+	wg := conc.NewWaitGroup()
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for _, s := range f.services {
+		wg.Go(func() {
+			defer cancel()
+			if err := s.Run(ctx); err != nil {
+				f.log.Errorw("Service error", "name", reflect.TypeOf(s), "err", err)
+			}
+		})
+	}
+
+	<-ctx.Done()
+	f.log.Infow("Shutting down the fork...")
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -24,6 +26,7 @@ import (
 	"github.com/rs/cors"
 	"github.com/sourcegraph/conc"
 	"google.golang.org/grpc"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 const (
@@ -77,6 +80,44 @@ func makeHTTPService(host string, port uint16, handler http.Handler) *httpServic
 	}
 }
 
+// compressionConfig enables compression of RPC responses that are at least threshold bytes long.
+type compressionConfig struct {
+	threshold int
+}
+
+// auditLogService closes the RPC audit log file once the node shuts down.
+type auditLogService struct {
+	file io.Closer
+}
+
+var _ service.Service = (*auditLogService)(nil)
+
+func (a *auditLogService) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return a.file.Close()
+}
+
+// makeRPCAuditLog creates the audit log shared by the RPC servers. It is written to stdout, or to
+// a file that is rotated once it grows too large, in which case a service closing it is returned.
+func makeRPCAuditLog(cfg *Config, log utils.SimpleLogger) (*jsonrpc.AuditLog, *auditLogService) {
+	auditCfg := jsonrpc.AuditConfig{
+		SampleRate:    cfg.RPCAuditSampleRate,
+		RedactParams:  cfg.RPCAuditRedactParams,
+		MaxParamsSize: int(cfg.RPCAuditParamsMaxSize),
+		APIKeyHeader:  cfg.RPCAuditAPIKeyHeader,
+	}
+	if cfg.RPCAuditLog == "stdout" {
+		return jsonrpc.NewAuditLog(os.Stdout, auditCfg, log), nil
+	}
+
+	file := &lumberjack.Logger{
+		Filename:   cfg.RPCAuditLog,
+		MaxSize:    int(cfg.RPCAuditLogMaxSize),
+		MaxBackups: int(cfg.RPCAuditLogMaxBackups),
+	}
+	return jsonrpc.NewAuditLog(file, auditCfg, log), &auditLogService{file: file}
+}
+
 func exactPathServer(path string, handler http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != path {
@@ -89,6 +130,7 @@ func exactPathServer(path string, handler http.Handler) http.HandlerFunc {
 
 func makeRPCOverHTTP(host string, port uint16, servers map[string]*jsonrpc.Server,
 	httpHandlers map[string]http.HandlerFunc, log utils.SimpleLogger, metricsEnabled bool, corsEnabled bool,
+	compression *compressionConfig,
 ) *httpService {
 	var listener jsonrpc.NewRequestListener
 	if metricsEnabled {
@@ -101,6 +143,9 @@ func makeRPCOverHTTP(host string, port uint16, servers map[string]*jsonrpc.Serve
 		if listener != nil {
 			httpHandler = httpHandler.WithListener(listener)
 		}
+		if compression != nil {
+			httpHandler = httpHandler.WithCompression(compression.threshold)
+		}
 		mux.Handle(path, exactPathServer(path, httpHandler))
 	}
 	for path, handler := range httpHandlers {
@@ -115,7 +160,7 @@ func makeRPCOverHTTP(host string, port uint16, servers map[string]*jsonrpc.Serve
 }
 
 func makeRPCOverWebsocket(host string, port uint16, servers map[string]*jsonrpc.Server,
-	log utils.SimpleLogger, metricsEnabled bool, corsEnabled bool,
+	log utils.SimpleLogger, metricsEnabled bool, corsEnabled bool, compression *compressionConfig,
 ) *httpService {
 	var listener jsonrpc.NewRequestListener
 	if metricsEnabled {
@@ -130,6 +175,9 @@ func makeRPCOverWebsocket(host string, port uint16, servers map[string]*jsonrpc.
 		if listener != nil {
 			wsHandler = wsHandler.WithListener(listener)
 		}
+		if compression != nil {
+			wsHandler = wsHandler.WithCompression(compression.threshold)
+		}
 		mux.Handle(path, exactPathServer(path, wsHandler))
 
 		wsPrefixedPath := strings.TrimSuffix("/ws"+path, "/")
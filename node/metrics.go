@@ -1,6 +1,7 @@
 package node
 
 import (
+	"errors"
 	"math"
 	"strconv"
 	"time"
@@ -13,7 +14,9 @@ import (
 	"github.com/NethermindEth/juno/jemalloc"
 	"github.com/NethermindEth/juno/jsonrpc"
 	"github.com/NethermindEth/juno/l1"
+	"github.com/NethermindEth/juno/rpc"
 	"github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/vm"
 	"github.com/cockroachdb/pebble"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -162,6 +165,29 @@ func makeRPCMetrics(version1, version2, version3 string) (jsonrpc.EventListener,
 		}
 }
 
+func makeRPCCacheMetrics() rpc.EventListener {
+	hits := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "rpc",
+		Subsystem: "cache",
+		Name:      "hits",
+	}, []string{"method", "version"})
+	misses := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "rpc",
+		Subsystem: "cache",
+		Name:      "misses",
+	}, []string{"method", "version"})
+	prometheus.MustRegister(hits, misses)
+
+	return &rpc.SelectiveListener{
+		OnCacheHitCb: func(version, method string) {
+			hits.WithLabelValues(method, version).Inc()
+		},
+		OnCacheMissCb: func(version, method string) {
+			misses.WithLabelValues(method, version).Inc()
+		},
+	}
+}
+
 func makeSyncMetrics(syncReader sync.Reader, bcReader blockchain.Reader) sync.EventListener {
 	opTimerHistogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "sync",
@@ -261,11 +287,34 @@ func makeFeederMetrics() feeder.EventListener {
 		Name:      "request_latency",
 	}, []string{"method", "status"})
 	prometheus.MustRegister(requestLatencies)
+	sourceLatencies := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "feeder",
+		Subsystem: "source",
+		Name:      "request_latency",
+	}, []string{"source", "method", "status"})
+	sourceDisagreements := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "feeder",
+		Subsystem: "source",
+		Name:      "disagreements",
+	}, []string{"source"})
+	prometheus.MustRegister(sourceLatencies, sourceDisagreements)
 	return &feeder.SelectiveListener{
 		OnResponseCb: func(urlPath string, status int, took time.Duration) {
 			statusString := strconv.FormatInt(int64(status), 10)
 			requestLatencies.WithLabelValues(urlPath, statusString).Observe(took.Seconds())
 		},
+		OnSourceResponseCb: func(source, method string, err error, took time.Duration) {
+			status := "ok"
+			if errors.Is(err, db.ErrKeyNotFound) {
+				status = "not_found"
+			} else if err != nil {
+				status = "error"
+			}
+			sourceLatencies.WithLabelValues(source, method, status).Observe(took.Seconds())
+		},
+		OnSourceDisagreementCb: func(source string) {
+			sourceDisagreements.WithLabelValues(source).Inc()
+		},
 	}
 }
 
@@ -348,3 +397,45 @@ func makeVMThrottlerMetrics(throttledVM *ThrottledVM) {
 	})
 	prometheus.MustRegister(vmJobs, vmQueue)
 }
+
+func makeVMClassCacheMetrics() vm.ClassCacheListener {
+	hits := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "vm",
+		Subsystem: "class_cache",
+		Name:      "hits",
+	})
+	misses := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "vm",
+		Subsystem: "class_cache",
+		Name:      "misses",
+	})
+	prometheus.MustRegister(hits, misses)
+
+	return &vm.SelectiveClassCacheListener{
+		OnClassCacheHitCb:  hits.Inc,
+		OnClassCacheMissCb: misses.Inc,
+	}
+}
+
+func makeVMDifferentialMetrics() vm.DifferentialListener {
+	comparisons := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "vm",
+		Subsystem: "differential",
+		Name:      "comparisons",
+	}, []string{"method"})
+	mismatches := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "vm",
+		Subsystem: "differential",
+		Name:      "mismatches",
+	}, []string{"method", "field"})
+	prometheus.MustRegister(comparisons, mismatches)
+
+	return &vm.SelectiveDifferentialListener{
+		OnComparisonCb: func(method string) {
+			comparisons.WithLabelValues(method).Inc()
+		},
+		OnMismatchCb: func(method, field string) {
+			mismatches.WithLabelValues(method, field).Inc()
+		},
+	}
+}
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"reflect"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
@@ -15,6 +18,7 @@ import (
 	"github.com/NethermindEth/juno/clients/feeder"
 	"github.com/NethermindEth/juno/clients/gateway"
 	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/db"
 	"github.com/NethermindEth/juno/db/pebble"
 	"github.com/NethermindEth/juno/db/remote"
@@ -26,6 +30,8 @@ import (
 	"github.com/NethermindEth/juno/rpc"
 	"github.com/NethermindEth/juno/service"
 	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/starknetdata/multisource"
+	adaptrpc "github.com/NethermindEth/juno/starknetdata/rpc"
 	"github.com/NethermindEth/juno/sync"
 	"github.com/NethermindEth/juno/upgrader"
 	"github.com/NethermindEth/juno/utils"
@@ -44,6 +50,9 @@ const (
 	latestReleaseURL = "https://github.com/NethermindEth/juno/releases/latest"
 )
 
+// multiSourceMaxRetries is the number of times a request is retried by a source when syncing from several.
+const multiSourceMaxRetries = 2
+
 // Config is the top-level juno configuration.
 type Config struct {
 	LogLevel               string        `mapstructure:"log-level"`
@@ -80,10 +89,33 @@ type Config struct {
 	P2PFeederNode bool   `mapstructure:"p2p-feeder-node"`
 	P2PPrivateKey string `mapstructure:"p2p-private-key"`
 
-	MaxVMs          uint `mapstructure:"max-vms"`
-	MaxVMQueue      uint `mapstructure:"max-vm-queue"`
-	RPCMaxBlockScan uint `mapstructure:"rpc-max-block-scan"`
-	RPCCallMaxSteps uint `mapstructure:"rpc-call-max-steps"`
+	MaxVMs     uint `mapstructure:"max-vms"`
+	MaxVMQueue uint `mapstructure:"max-vm-queue"`
+
+	VMClassCacheSize       uint `mapstructure:"vm-class-cache-size"`
+	VMClassCacheWarmBlocks uint `mapstructure:"vm-class-cache-warm-blocks"`
+	RPCMaxBlockScan        uint `mapstructure:"rpc-max-block-scan"`
+	RPCCallMaxSteps        uint `mapstructure:"rpc-call-max-steps"`
+
+	VMBackend                      string `mapstructure:"vm-backend"`
+	VMShadowBackend                string `mapstructure:"vm-shadow-backend"`
+	VMShadowVersionedConstantsFile string `mapstructure:"vm-shadow-versioned-constants-file"`
+
+	RPCResponseCacheSize uint `mapstructure:"rpc-response-cache-size"`
+
+	RPCCompression          bool `mapstructure:"rpc-compression"`
+	RPCCompressionThreshold uint `mapstructure:"rpc-compression-threshold"`
+
+	RPCMethodTimeouts  map[string]time.Duration `mapstructure:"rpc-method-timeouts"`
+	RPCMaxResponseSize uint                     `mapstructure:"rpc-max-response-size"`
+
+	RPCAuditLog           string  `mapstructure:"rpc-audit-log"`
+	RPCAuditLogMaxSize    uint    `mapstructure:"rpc-audit-log-max-size"`
+	RPCAuditLogMaxBackups uint    `mapstructure:"rpc-audit-log-max-backups"`
+	RPCAuditSampleRate    float64 `mapstructure:"rpc-audit-sample-rate"`
+	RPCAuditRedactParams  bool    `mapstructure:"rpc-audit-redact-params"`
+	RPCAuditParamsMaxSize uint    `mapstructure:"rpc-audit-params-max-size"`
+	RPCAuditAPIKeyHeader  string  `mapstructure:"rpc-audit-api-key-header"`
 
 	DBCacheSize  uint `mapstructure:"db-cache-size"`
 	DBMaxHandles int  `mapstructure:"db-max-handles"`
@@ -91,6 +123,19 @@ type Config struct {
 	GatewayAPIKey  string        `mapstructure:"gw-api-key"`
 	GatewayTimeout time.Duration `mapstructure:"gw-timeout"`
 
+	SyncRPCURL     string        `mapstructure:"sync-rpc-url"`
+	SyncFeederURLs string        `mapstructure:"sync-feeder-urls"`
+	SyncQuorum     uint          `mapstructure:"sync-quorum"`
+	SyncHedgeDelay time.Duration `mapstructure:"sync-hedge-delay"`
+	SyncBatchSize  uint          `mapstructure:"sync-batch-size"`
+	SyncFinality   string        `mapstructure:"sync-finality"`
+	SyncUpstreamWS string        `mapstructure:"sync-upstream-ws"`
+	Checkpoint     string        `mapstructure:"checkpoint"`
+	Backfill       bool          `mapstructure:"backfill"`
+
+	FeederCacheDir  string `mapstructure:"feeder-cache-dir"`
+	FeederCacheSize uint   `mapstructure:"feeder-cache-size"`
+
 	PluginPath string `mapstructure:"plugin-path"`
 
 	LogHost string `mapstructure:"log-host"`
@@ -101,6 +146,7 @@ type Node struct {
 	cfg        *Config
 	db         db.DB
 	blockchain *blockchain.Blockchain
+	checkpoint *blockchain.Checkpoint
 
 	earlyServices []service.Service // Services that needs to start before than other services and before migration.
 	services      []service.Service
@@ -159,9 +205,79 @@ func New(cfg *Config, version string, logLevel *utils.LogLevel) (*Node, error) {
 		}
 	}
 
+	var feederCache *feeder.DiskCache
+	if cfg.FeederCacheDir != "" {
+		// Blocks are cached by number, which is only unique within a network.
+		feederCacheDir := filepath.Join(cfg.FeederCacheDir, cfg.Network.Name)
+		if feederCache, err = feeder.NewDiskCache(feederCacheDir, int64(cfg.FeederCacheSize)*utils.Megabyte); err != nil {
+			return nil, fmt.Errorf("open feeder cache: %w", err)
+		}
+	}
+
 	client := feeder.NewClient(cfg.Network.FeederURL).WithUserAgent(ua).WithLogger(log).
-		WithTimeout(cfg.GatewayTimeout).WithAPIKey(cfg.GatewayAPIKey)
-	synchronizer := sync.New(chain, adaptfeeder.New(client), log, cfg.PendingPollInterval, dbIsRemote, database)
+		WithTimeout(cfg.GatewayTimeout).WithAPIKey(cfg.GatewayAPIKey).WithDiskCache(feederCache)
+	// Blocks are synced from the feeder of the network, unless RPC upstreams are given, and from all the
+	// given sources combined if there are several.
+	rpcURLs, feederURLs := splitList(cfg.SyncRPCURL), splitList(cfg.SyncFeederURLs)
+	if len(rpcURLs) == 0 {
+		feederURLs = append([]string{cfg.Network.FeederURL}, feederURLs...)
+	}
+	multipleSources := len(rpcURLs)+len(feederURLs) > 1
+	if cfg.SyncQuorum > uint(len(rpcURLs)+len(feederURLs)) {
+		return nil, fmt.Errorf("sync quorum of %d is larger than the number of sources", cfg.SyncQuorum)
+	}
+
+	finality := sync.Finality(cfg.SyncFinality)
+	switch finality {
+	case "":
+		finality = sync.FinalityL2
+	case sync.FinalityL2:
+	case sync.FinalityL1:
+		if cfg.DisableL1Verification {
+			return nil, fmt.Errorf("sync finality %q needs L1 verification", finality)
+		}
+	default:
+		return nil, fmt.Errorf("unknown sync finality %q", cfg.SyncFinality)
+	}
+
+	var checkpoint *blockchain.Checkpoint
+	if cfg.Checkpoint != "" {
+		if checkpoint, err = parseCheckpoint(cfg.Checkpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	feederClients := []*feeder.Client{client}
+	sources := make([]multisource.Source, 0, len(rpcURLs)+len(feederURLs))
+	for idx, feederURL := range feederURLs {
+		sourceClient := client
+		if idx > 0 || len(rpcURLs) > 0 {
+			sourceClient = feeder.NewClient(feederURL).WithUserAgent(ua).WithLogger(log).
+				WithTimeout(cfg.GatewayTimeout).WithAPIKey(cfg.GatewayAPIKey).WithDiskCache(feederCache)
+			feederClients = append(feederClients, sourceClient)
+		}
+		if multipleSources {
+			// Fail over to the other sources rather than retrying for long.
+			sourceClient.WithMaxRetries(multiSourceMaxRetries)
+		}
+		sources = append(sources, multisource.Source{Name: feederURL, Data: adaptfeeder.New(sourceClient)})
+	}
+	for _, rpcURL := range rpcURLs {
+		rpcData := adaptrpc.New(rpcURL).WithUserAgent(ua).WithLogger(log).WithTimeout(cfg.GatewayTimeout)
+		if multipleSources {
+			rpcData.WithMaxRetries(multiSourceMaxRetries)
+		}
+		sources = append(sources, multisource.Source{Name: rpcURL, Data: rpcData})
+	}
+
+	starknetData := sources[0].Data
+	var multiSource *multisource.MultiSource
+	if multipleSources {
+		multiSource = multisource.New(sources, log).WithHedgeDelay(cfg.SyncHedgeDelay).WithQuorum(int(cfg.SyncQuorum))
+		starknetData = multiSource
+	}
+	synchronizer := sync.New(chain, starknetData, log, cfg.PendingPollInterval, dbIsRemote, database).
+		WithCatchUpBatchSize(cfg.SyncBatchSize).WithFinality(finality).WithUpstreamHeads(cfg.SyncUpstreamWS)
 	chain.WithPendingBlockFn(synchronizer.PendingBlock)
 	gatewayClient := gateway.NewClient(cfg.Network.GatewayURL, log).WithUserAgent(ua).WithAPIKey(cfg.GatewayAPIKey)
 
@@ -196,8 +312,31 @@ func New(cfg *Config, version string, logLevel *utils.LogLevel) (*Node, error) {
 	if synchronizer != nil {
 		services = append(services, synchronizer)
 	}
+	if cfg.Backfill {
+		if dbIsRemote {
+			return nil, errors.New("backfill needs a local database")
+		}
+		services = append(services, sync.NewBackfiller(chain, starknetData, log))
+	}
 
-	throttledVM := NewThrottledVM(vm.New(false, log), cfg.MaxVMs, int32(cfg.MaxVMQueue))
+	virtualMachine, differentialVM, err := newVM(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+	throttledVM := NewThrottledVM(virtualMachine, cfg.MaxVMs, int32(cfg.MaxVMQueue))
+	var classCache *vm.ClassCache
+	if cfg.VMClassCacheSize > 0 {
+		classCache = vm.NewClassCache(uint64(cfg.VMClassCacheSize) * utils.Megabyte)
+		vm.SetClassCache(classCache)
+		if cfg.VMClassCacheWarmBlocks > 0 {
+			services = append(services, &classCacheWarmer{
+				cache:  classCache,
+				chain:  chain,
+				blocks: uint64(cfg.VMClassCacheWarmBlocks),
+				log:    log,
+			})
+		}
+	}
 
 	var syncReader sync.Reader = &sync.NoopSynchronizer{}
 	if synchronizer != nil {
@@ -206,24 +345,42 @@ func New(cfg *Config, version string, logLevel *utils.LogLevel) (*Node, error) {
 
 	rpcHandler := rpc.New(chain, syncReader, throttledVM, version, log, &cfg.Network).WithGateway(gatewayClient).WithFeeder(client)
 	rpcHandler = rpcHandler.WithFilterLimit(cfg.RPCMaxBlockScan).WithCallMaxSteps(uint64(cfg.RPCCallMaxSteps))
+	var responseCache *rpc.ResponseCache
+	if cfg.RPCResponseCacheSize > 0 {
+		responseCache = rpc.NewResponseCache(chain, syncReader, uint64(cfg.RPCResponseCacheSize)*utils.Megabyte)
+		rpcHandler = rpcHandler.WithResponseCache(responseCache)
+	}
 	services = append(services, rpcHandler)
 	// to improve RPC throughput we double GOMAXPROCS
 	maxGoroutines := 2 * runtime.GOMAXPROCS(0)
-	jsonrpcServerV08 := jsonrpc.NewServer(maxGoroutines, log).WithValidator(validator.Validator())
+	maxResponseSize := uint64(cfg.RPCMaxResponseSize) * utils.Megabyte
+	jsonrpcServerV08 := jsonrpc.NewServer(maxGoroutines, log).WithValidator(validator.Validator()).
+		WithMethodTimeouts(cfg.RPCMethodTimeouts).WithMaxResponseSize(maxResponseSize)
 	methodsV08, pathV08 := rpcHandler.MethodsV0_8()
 	if err = jsonrpcServerV08.RegisterMethods(methodsV08...); err != nil {
 		return nil, err
 	}
-	jsonrpcServerV07 := jsonrpc.NewServer(maxGoroutines, log)
+	jsonrpcServerV07 := jsonrpc.NewServer(maxGoroutines, log).WithMethodTimeouts(cfg.RPCMethodTimeouts).
+		WithMaxResponseSize(maxResponseSize)
 	methodsV07, pathV07 := rpcHandler.MethodsV0_7()
 	if err = jsonrpcServerV07.RegisterMethods(methodsV07...); err != nil {
 		return nil, err
 	}
-	jsonrpcServerV06 := jsonrpc.NewServer(maxGoroutines, log)
+	jsonrpcServerV06 := jsonrpc.NewServer(maxGoroutines, log).WithMethodTimeouts(cfg.RPCMethodTimeouts).
+		WithMaxResponseSize(maxResponseSize)
 	methodsV06, pathV06 := rpcHandler.MethodsV0_6()
 	if err = jsonrpcServerV06.RegisterMethods(methodsV06...); err != nil {
 		return nil, err
 	}
+	if cfg.RPCAuditLog != "" {
+		auditLog, auditService := makeRPCAuditLog(cfg, log)
+		jsonrpcServerV08.WithAuditLog(auditLog)
+		jsonrpcServerV07.WithAuditLog(auditLog)
+		jsonrpcServerV06.WithAuditLog(auditLog)
+		if auditService != nil {
+			services = append(services, auditService)
+		}
+	}
 	rpcServers := map[string]*jsonrpc.Server{
 		"/":              jsonrpcServerV08,
 		pathV08:          jsonrpcServerV08,
@@ -234,16 +391,22 @@ func New(cfg *Config, version string, logLevel *utils.LogLevel) (*Node, error) {
 		"/rpc" + pathV07: jsonrpcServerV07,
 		"/rpc" + pathV06: jsonrpcServerV06,
 	}
+	var compression *compressionConfig
+	if cfg.RPCCompression {
+		compression = &compressionConfig{threshold: int(cfg.RPCCompressionThreshold)}
+	}
 	if cfg.HTTP {
 		readinessHandlers := NewReadinessHandlers(chain, synchronizer)
 		httpHandlers := map[string]http.HandlerFunc{
 			"/ready/sync": readinessHandlers.HandleReadySync,
 		}
-		services = append(services, makeRPCOverHTTP(cfg.HTTPHost, cfg.HTTPPort, rpcServers, httpHandlers, log, cfg.Metrics, cfg.RPCCorsEnable))
+		services = append(services, makeRPCOverHTTP(cfg.HTTPHost, cfg.HTTPPort, rpcServers, httpHandlers, log, cfg.Metrics, cfg.RPCCorsEnable,
+			compression))
 	}
 	if cfg.Websocket {
 		services = append(services,
-			makeRPCOverWebsocket(cfg.WebsocketHost, cfg.WebsocketPort, rpcServers, log, cfg.Metrics, cfg.RPCCorsEnable))
+			makeRPCOverWebsocket(cfg.WebsocketHost, cfg.WebsocketPort, rpcServers, log, cfg.Metrics, cfg.RPCCorsEnable,
+				compression))
 	}
 	if cfg.LogPort != 0 {
 		log.Infow("Log level can be changed via HTTP PUT request to " + cfg.LogHost + ":" + fmt.Sprintf("%d", cfg.LogPort) + "/log/level")
@@ -252,6 +415,12 @@ func New(cfg *Config, version string, logLevel *utils.LogLevel) (*Node, error) {
 	if cfg.Metrics {
 		makeJeMallocMetrics()
 		makeVMThrottlerMetrics(throttledVM)
+		if differentialVM != nil {
+			differentialVM.WithListener(makeVMDifferentialMetrics())
+		}
+		if classCache != nil {
+			classCache.WithListener(makeVMClassCacheMetrics())
+		}
 		makePebbleMetrics(database)
 		chain.WithListener(makeBlockchainMetrics())
 		makeJunoMetrics(version)
@@ -260,7 +429,16 @@ func New(cfg *Config, version string, logLevel *utils.LogLevel) (*Node, error) {
 		jsonrpcServerV08.WithListener(rpcMetricsV08)
 		jsonrpcServerV07.WithListener(rpcMetricsV07)
 		jsonrpcServerV06.WithListener(rpcMetricsV06)
-		client.WithListener(makeFeederMetrics())
+		if responseCache != nil {
+			responseCache.WithListener(makeRPCCacheMetrics())
+		}
+		feederMetrics := makeFeederMetrics()
+		for _, feederClient := range feederClients {
+			feederClient.WithListener(feederMetrics)
+		}
+		if multiSource != nil {
+			multiSource.WithListener(feederMetrics)
+		}
 		gatewayClient.WithListener(makeGatewayMetrics())
 		earlyServices = append(earlyServices, makeMetrics(cfg.MetricsHost, cfg.MetricsPort))
 
@@ -285,6 +463,7 @@ func New(cfg *Config, version string, logLevel *utils.LogLevel) (*Node, error) {
 		version:       version,
 		db:            database,
 		blockchain:    chain,
+		checkpoint:    checkpoint,
 		services:      services,
 		earlyServices: earlyServices,
 	}
@@ -314,6 +493,49 @@ func New(cfg *Config, version string, logLevel *utils.LogLevel) (*Node, error) {
 	return n, nil
 }
 
+// parseCheckpoint parses a checkpoint given as <number>:<hash>.
+func parseCheckpoint(checkpoint string) (*blockchain.Checkpoint, error) {
+	numberStr, hashStr, found := strings.Cut(checkpoint, ":")
+	if !found {
+		return nil, fmt.Errorf("checkpoint %q is not in the <number>:<hash> format", checkpoint)
+	}
+	number, err := strconv.ParseUint(numberStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint block number %q: %v", numberStr, err)
+	}
+	hash, err := new(felt.Felt).SetString(hashStr)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint block hash %q: %v", hashStr, err)
+	}
+	return &blockchain.Checkpoint{Number: number, Hash: hash}, nil
+}
+
+// newVM creates the VM of the configured backend, run alongside a shadow VM if one is configured. The
+// differential VM comparing them is returned too, nil if there is no shadow VM.
+func newVM(cfg *Config, log utils.SimpleLogger) (vm.VM, *vm.DifferentialVM, error) {
+	backend := cfg.VMBackend
+	if backend == "" {
+		backend = vm.RustBackend
+	}
+	primary, err := vm.NewBackend(backend, &vm.BackendConfig{}, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.VMShadowBackend == "" {
+		return primary, nil, nil
+	}
+
+	shadow, err := vm.NewBackend(cfg.VMShadowBackend, &vm.BackendConfig{
+		VersionedConstantsFile: cfg.VMShadowVersionedConstantsFile,
+	}, log)
+	if err != nil {
+		return nil, nil, fmt.Errorf("set up shadow VM: %w", err)
+	}
+	log.Infow("Comparing VM results with a shadow VM", "backend", backend, "shadow", cfg.VMShadowBackend)
+	differentialVM := vm.NewDifferential(primary, shadow, log)
+	return differentialVM, differentialVM, nil
+}
+
 func newL1Client(ethNode string, includeMetrics bool, chain *blockchain.Blockchain, log utils.SimpleLogger) (*l1.Client, error) {
 	ethNodeURL, err := url.Parse(ethNode)
 	if err != nil {
@@ -339,6 +561,17 @@ func newL1Client(ethNode string, includeMetrics bool, chain *blockchain.Blockcha
 	return l1Client, nil
 }
 
+// splitList splits a comma-separated list, ignoring empty elements.
+func splitList(list string) []string {
+	var elements []string
+	for element := range strings.SplitSeq(list, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
 // Run starts Juno node by opening the DB, initialising services.
 // All the services blocking and any errors returned by service run function is logged.
 // Run will wait for all services to return before exiting.
@@ -380,6 +613,15 @@ func (n *Node) Run(ctx context.Context) {
 		return
 	}
 
+	if n.checkpoint != nil {
+		n.log.Infow("Verifying the chain from the checkpoint", "number", n.checkpoint.Number,
+			"hash", n.checkpoint.Hash.ShortString())
+		if err := n.blockchain.SetCheckpoint(ctx, n.checkpoint); err != nil {
+			n.log.Errorw("Error while verifying the chain from the checkpoint", "err", err)
+			return
+		}
+	}
+
 	for _, s := range n.services {
 		n.StartService(wg, ctx, cancel, s)
 	}
@@ -1,29 +1,54 @@
 package node
 
 import (
+	"context"
+
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/utils"
 	"github.com/NethermindEth/juno/vm"
 )
 
-var _ vm.VM = (*ThrottledVM)(nil)
+var (
+	_ vm.VM            = (*ThrottledVM)(nil)
+	_ vm.ContextBinder = (*ThrottledVM)(nil)
+	_ vm.Batcher       = (*ThrottledVM)(nil)
+)
 
 type ThrottledVM struct {
 	*utils.Throttler[vm.VM]
+	ctx context.Context
 }
 
 func NewThrottledVM(res vm.VM, concurrenyBudget uint, maxQueueLen int32) *ThrottledVM {
 	return &ThrottledVM{
 		Throttler: utils.NewThrottler(concurrenyBudget, &res).WithMaxQueueLen(maxQueueLen),
+		ctx:       context.Background(),
 	}
 }
 
+// WithContext returns a ThrottledVM sharing the same throttler that stops waiting
+// for an execution slot once ctx is done.
+func (tvm *ThrottledVM) WithContext(ctx context.Context) vm.VM {
+	return &ThrottledVM{
+		Throttler: tvm.Throttler,
+		ctx:       ctx,
+	}
+}
+
+// Batch runs fn in a single execution slot, fn is given the underlying VM so that its executions
+// are not queued again.
+func (tvm *ThrottledVM) Batch(fn func(vm.VM) error) error {
+	return tvm.DoContext(tvm.ctx, func(vm *vm.VM) error {
+		return fn(*vm)
+	})
+}
+
 func (tvm *ThrottledVM) Call(callInfo *vm.CallInfo, blockInfo *vm.BlockInfo, state core.StateReader,
 	network *utils.Network, maxSteps uint64, sierraVersion string, errStack, returnStateDiff bool,
 ) (vm.CallResult, error) {
 	ret := vm.CallResult{}
-	return ret, tvm.Do(func(vm *vm.VM) error {
+	return ret, tvm.DoContext(tvm.ctx, func(vm *vm.VM) error {
 		var err error
 		ret, err = (*vm).Call(callInfo, blockInfo, state, network, maxSteps, sierraVersion, errStack, returnStateDiff)
 		return err
@@ -34,7 +59,7 @@ func (tvm *ThrottledVM) Execute(txns []core.Transaction, declaredClasses []core.
 	blockInfo *vm.BlockInfo, state core.StateReader, network *utils.Network, skipChargeFee, skipValidate, errOnRevert, errStack bool,
 ) (vm.ExecutionResults, error) {
 	var executionResult vm.ExecutionResults
-	return executionResult, tvm.Do(func(vm *vm.VM) error {
+	return executionResult, tvm.DoContext(tvm.ctx, func(vm *vm.VM) error {
 		var err error
 		executionResult, err = (*vm).Execute(txns, declaredClasses, paidFeesOnL1, blockInfo, state, network,
 			skipChargeFee, skipValidate, errOnRevert, errStack)
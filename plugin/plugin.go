@@ -18,6 +18,12 @@ type JunoPlugin interface {
 	RevertBlock(from, to *BlockAndStateUpdate, reverseStateDiff *core.StateDiff) error
 }
 
+// PendingDeltaPlugin is implemented by plugins that follow the pending block. PendingDelta is called with the
+// transactions added to and retracted from the pending block every time it changes.
+type PendingDeltaPlugin interface {
+	PendingDelta(delta *core.PendingDelta) error
+}
+
 type BlockAndStateUpdate struct {
 	Block       *core.Block
 	StateUpdate *core.StateUpdate
@@ -0,0 +1,311 @@
+// Package profiler aggregates the execution resources of transaction traces by contract, class and entry point,
+// and exports them as folded stacks or pprof profiles to be viewed as flamegraphs.
+package profiler
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
+	"github.com/google/pprof/profile"
+)
+
+// Cost is what calls consumed. The resources of an invocation include the ones of its inner calls, costs only
+// count what the calls consumed themselves so that they can be summed.
+type Cost struct {
+	Calls uint64 `json:"calls"`
+	vm.ComputationResources
+	L1Gas     uint64 `json:"l1_gas"`
+	L1DataGas uint64 `json:"l1_data_gas"`
+	L2Gas     uint64 `json:"l2_gas"`
+	// Calldata is the number of calldata felts the calls were made with.
+	Calldata uint64 `json:"calldata"`
+}
+
+func (c *Cost) add(other *Cost) {
+	c.Calls += other.Calls
+	c.L1Gas += other.L1Gas
+	c.L1DataGas += other.L1DataGas
+	c.L2Gas += other.L2Gas
+	c.Calldata += other.Calldata
+	c.ComputationResources = combineResources(&c.ComputationResources, &other.ComputationResources,
+		func(a, b uint64) uint64 { return a + b })
+}
+
+// subtractResources removes the execution resources of an inner call, never going below zero.
+func (c *Cost) subtractResources(inner *vm.ExecutionResources) {
+	sub := func(a, b uint64) uint64 {
+		if b > a {
+			return 0
+		}
+		return a - b
+	}
+	c.L1Gas = sub(c.L1Gas, inner.L1Gas)
+	c.L1DataGas = sub(c.L1DataGas, inner.L1DataGas)
+	c.L2Gas = sub(c.L2Gas, inner.L2Gas)
+	c.ComputationResources = combineResources(&c.ComputationResources, &inner.ComputationResources, sub)
+}
+
+func combineResources(a, b *vm.ComputationResources, op func(a, b uint64) uint64) vm.ComputationResources {
+	return vm.ComputationResources{
+		Steps:        op(a.Steps, b.Steps),
+		MemoryHoles:  op(a.MemoryHoles, b.MemoryHoles),
+		Pedersen:     op(a.Pedersen, b.Pedersen),
+		RangeCheck:   op(a.RangeCheck, b.RangeCheck),
+		Bitwise:      op(a.Bitwise, b.Bitwise),
+		Ecdsa:        op(a.Ecdsa, b.Ecdsa),
+		EcOp:         op(a.EcOp, b.EcOp),
+		Keccak:       op(a.Keccak, b.Keccak),
+		Poseidon:     op(a.Poseidon, b.Poseidon),
+		SegmentArena: op(a.SegmentArena, b.SegmentArena),
+		AddMod:       op(a.AddMod, b.AddMod),
+		MulMod:       op(a.MulMod, b.MulMod),
+		RangeCheck96: op(a.RangeCheck96, b.RangeCheck96),
+		Output:       op(a.Output, b.Output),
+	}
+}
+
+// Metric is a single value of a cost, used for the outputs that only carry one value per stack.
+type Metric string
+
+const (
+	Steps     Metric = "steps"
+	L1Gas     Metric = "l1_gas"
+	L1DataGas Metric = "l1_data_gas"
+	L2Gas     Metric = "l2_gas"
+	Calldata  Metric = "calldata"
+	Calls     Metric = "calls"
+)
+
+// Metrics are all the metrics, in the order of the sample types of pprof profiles.
+var Metrics = []Metric{Steps, L1Gas, L1DataGas, L2Gas, Calldata, Calls}
+
+func (m *Metric) UnmarshalText(text []byte) error {
+	metric := Metric(text)
+	if !slices.Contains(Metrics, metric) {
+		return fmt.Errorf("unknown metric %q", text)
+	}
+	*m = metric
+	return nil
+}
+
+func (m Metric) Value(c *Cost) uint64 {
+	switch m {
+	case Steps:
+		return c.Steps
+	case L1Gas:
+		return c.L1Gas
+	case L1DataGas:
+		return c.L1DataGas
+	case L2Gas:
+		return c.L2Gas
+	case Calldata:
+		return c.Calldata
+	case Calls:
+		return c.Calls
+	default:
+		return 0
+	}
+}
+
+func (m Metric) unit() string {
+	switch m {
+	case Steps:
+		return "steps"
+	case Calldata:
+		return "felts"
+	case Calls:
+		return "count"
+	default:
+		return "gas"
+	}
+}
+
+// Entry is the cost of all calls to a contract, a class or an entry point.
+type Entry struct {
+	ContractAddress *felt.Felt `json:"contract_address,omitempty"`
+	ClassHash       *felt.Felt `json:"class_hash,omitempty"`
+	Selector        *felt.Felt `json:"entry_point_selector,omitempty"`
+	Cost
+}
+
+// Profile is the aggregated cost of the call trees of one or more transactions.
+type Profile struct {
+	Total      Cost    `json:"total"`
+	ByContract []Entry `json:"by_contract"`
+	ByClass    []Entry `json:"by_class"`
+	BySelector []Entry `json:"by_selector"`
+
+	// stacks are the costs of the calls by call stack, keyed by the frames joined by ';'
+	stacks map[string]*Cost
+}
+
+type builder struct {
+	profile    *Profile
+	byContract map[felt.Felt]*Cost
+	byClass    map[felt.Felt]*Cost
+	bySelector map[felt.Felt]*Cost
+}
+
+// New profiles the call trees of the given traces.
+func New(traces ...*vm.TransactionTrace) *Profile {
+	b := &builder{
+		profile:    &Profile{stacks: make(map[string]*Cost)},
+		byContract: make(map[felt.Felt]*Cost),
+		byClass:    make(map[felt.Felt]*Cost),
+		bySelector: make(map[felt.Felt]*Cost),
+	}
+	for _, trace := range traces {
+		phases := []struct {
+			name       string
+			invocation *vm.FunctionInvocation
+		}{
+			{"validate", trace.ValidateInvocation},
+			{"execute", executeInvocation(trace)},
+			{"fee_transfer", trace.FeeTransferInvocation},
+			{"constructor", trace.ConstructorInvocation},
+			{"l1_handler", trace.FunctionInvocation},
+		}
+		// the root frame of every call stack is the phase of the transaction the call was made in
+		for _, phase := range phases {
+			if phase.invocation != nil {
+				b.add(phase.invocation, []string{phase.name})
+			}
+		}
+	}
+
+	b.profile.ByContract = entries(b.byContract, func(key felt.Felt) Entry { return Entry{ContractAddress: &key} })
+	b.profile.ByClass = entries(b.byClass, func(key felt.Felt) Entry { return Entry{ClassHash: &key} })
+	b.profile.BySelector = entries(b.bySelector, func(key felt.Felt) Entry { return Entry{Selector: &key} })
+	return b.profile
+}
+
+func executeInvocation(trace *vm.TransactionTrace) *vm.FunctionInvocation {
+	if trace.ExecuteInvocation == nil {
+		return nil
+	}
+	return trace.ExecuteInvocation.FunctionInvocation
+}
+
+func (b *builder) add(invocation *vm.FunctionInvocation, stack []string) {
+	stack = append(stack, frameName(invocation))
+
+	self := Cost{Calls: 1, Calldata: uint64(len(invocation.Calldata))}
+	if resources := invocation.ExecutionResources; resources != nil {
+		self.ComputationResources = resources.ComputationResources
+		self.L1Gas, self.L1DataGas, self.L2Gas = resources.L1Gas, resources.L1DataGas, resources.L2Gas
+	}
+	for idx := range invocation.Calls {
+		call := &invocation.Calls[idx]
+		if call.ExecutionResources != nil {
+			self.subtractResources(call.ExecutionResources)
+		}
+		b.add(call, stack)
+	}
+
+	b.profile.Total.add(&self)
+	addTo(b.profile.stacks, strings.Join(stack, ";"), &self)
+	addTo(b.byContract, invocation.ContractAddress, &self)
+	if invocation.ClassHash != nil {
+		addTo(b.byClass, *invocation.ClassHash, &self)
+	}
+	if invocation.EntryPointSelector != nil {
+		addTo(b.bySelector, *invocation.EntryPointSelector, &self)
+	}
+}
+
+func addTo[K comparable](costs map[K]*Cost, key K, cost *Cost) {
+	total, ok := costs[key]
+	if !ok {
+		total = new(Cost)
+		costs[key] = total
+	}
+	total.add(cost)
+}
+
+// frameName names a call by its contract and selector, which is what flamegraphs show.
+func frameName(invocation *vm.FunctionInvocation) string {
+	selector := "<unknown>"
+	if invocation.EntryPointSelector != nil {
+		selector = invocation.EntryPointSelector.String()
+	}
+	return invocation.ContractAddress.String() + ":" + selector
+}
+
+// entries lists the costs, the most expensive in steps first.
+func entries(costs map[felt.Felt]*Cost, entry func(key felt.Felt) Entry) []Entry {
+	keys := slices.SortedFunc(maps.Keys(costs), func(a, b felt.Felt) int {
+		if c := cmp.Compare(costs[b].Steps, costs[a].Steps); c != 0 {
+			return c
+		}
+		return a.Cmp(&b)
+	})
+	return utils.Map(keys, func(key felt.Felt) Entry {
+		e := entry(key)
+		e.Cost = *costs[key]
+		return e
+	})
+}
+
+// WriteFolded writes the profile in the folded stacks format of flamegraph tools: a line per call stack with
+// the frames separated by ';' followed by the value of the metric. Stacks with a zero value are left out.
+func (p *Profile) WriteFolded(w io.Writer, metric Metric) error {
+	for _, stack := range slices.Sorted(maps.Keys(p.stacks)) {
+		value := metric.Value(p.stacks[stack])
+		if value == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s %d\n", stack, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WritePProf writes the profile as a gzipped pprof profile with a sample type per metric.
+func (p *Profile) WritePProf(w io.Writer) error {
+	prof := &profile.Profile{
+		SampleType: utils.Map(Metrics, func(metric Metric) *profile.ValueType {
+			return &profile.ValueType{Type: string(metric), Unit: metric.unit()}
+		}),
+	}
+
+	locations := make(map[string]*profile.Location)
+	location := func(name string) *profile.Location {
+		if loc, ok := locations[name]; ok {
+			return loc
+		}
+		id := uint64(len(locations) + 1)
+		function := &profile.Function{ID: id, Name: name, SystemName: name}
+		loc := &profile.Location{ID: id, Line: []profile.Line{{Function: function}}}
+		prof.Function = append(prof.Function, function)
+		prof.Location = append(prof.Location, loc)
+		locations[name] = loc
+		return loc
+	}
+
+	for _, stack := range slices.Sorted(maps.Keys(p.stacks)) {
+		frames := strings.Split(stack, ";")
+		sample := &profile.Sample{Location: make([]*profile.Location, len(frames))}
+		// pprof lists the locations of a sample from the leaf to the root
+		for idx, name := range frames {
+			sample.Location[len(frames)-1-idx] = location(name)
+		}
+		cost := p.stacks[stack]
+		sample.Value = utils.Map(Metrics, func(metric Metric) int64 {
+			return int64(metric.Value(cost)) //nolint:gosec
+		})
+		prof.Sample = append(prof.Sample, sample)
+	}
+
+	if err := prof.CheckValid(); err != nil {
+		return err
+	}
+	return prof.Write(w)
+}
@@ -0,0 +1,148 @@
+package profiler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/profiler"
+	"github.com/NethermindEth/juno/vm"
+	"github.com/google/pprof/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func invocation(address, classHash, selector uint64, steps, l2Gas uint64, calls ...vm.FunctionInvocation,
+) vm.FunctionInvocation {
+	return vm.FunctionInvocation{
+		ContractAddress:    *new(felt.Felt).SetUint64(address),
+		ClassHash:          new(felt.Felt).SetUint64(classHash),
+		EntryPointSelector: new(felt.Felt).SetUint64(selector),
+		Calldata:           []felt.Felt{{}},
+		Calls:              calls,
+		ExecutionResources: &vm.ExecutionResources{
+			L2Gas:                l2Gas,
+			ComputationResources: vm.ComputationResources{Steps: steps},
+		},
+	}
+}
+
+func testTrace() *vm.TransactionTrace {
+	validate := invocation(0xa, 0xca, 0x1, 10, 100)
+	// the account calls the same token twice, the resources of the calls are included in the ones of the account
+	execute := invocation(0xa, 0xca, 0x2, 100, 1000,
+		invocation(0xb, 0xcb, 0x3, 30, 300),
+		invocation(0xb, 0xcb, 0x3, 40, 400, invocation(0xc, 0xcb, 0x4, 5, 50)),
+	)
+	return &vm.TransactionTrace{
+		Type:               vm.TxnInvoke,
+		ValidateInvocation: &validate,
+		ExecuteInvocation:  &vm.ExecuteInvocation{FunctionInvocation: &execute},
+	}
+}
+
+func TestNew(t *testing.T) {
+	p := profiler.New(testTrace())
+
+	assert.Equal(t, uint64(110), p.Total.Steps)
+	assert.Equal(t, uint64(1100), p.Total.L2Gas)
+	assert.Equal(t, uint64(5), p.Total.Calls)
+	assert.Equal(t, uint64(5), p.Total.Calldata)
+
+	type cost struct{ steps, calls uint64 }
+	costs := func(entries []profiler.Entry, key func(*profiler.Entry) *felt.Felt) map[string]cost {
+		result := make(map[string]cost)
+		for idx := range entries {
+			result[key(&entries[idx]).String()] = cost{entries[idx].Steps, entries[idx].Calls}
+		}
+		return result
+	}
+
+	assert.Equal(t, map[string]cost{
+		"0xa": {40, 2},
+		"0xb": {65, 2},
+		"0xc": {5, 1},
+	}, costs(p.ByContract, func(e *profiler.Entry) *felt.Felt { return e.ContractAddress }))
+	assert.Equal(t, map[string]cost{
+		"0xca": {40, 2},
+		"0xcb": {70, 3},
+	}, costs(p.ByClass, func(e *profiler.Entry) *felt.Felt { return e.ClassHash }))
+	assert.Equal(t, map[string]cost{
+		"0x1": {10, 1},
+		"0x2": {30, 1},
+		"0x3": {65, 2},
+		"0x4": {5, 1},
+	}, costs(p.BySelector, func(e *profiler.Entry) *felt.Felt { return e.Selector }))
+
+	t.Run("entries are sorted by steps", func(t *testing.T) {
+		require.Len(t, p.ByClass, 2)
+		assert.Equal(t, "0xcb", p.ByClass[0].ClassHash.String())
+		assert.Equal(t, "0xca", p.ByClass[1].ClassHash.String())
+	})
+
+	t.Run("inner calls consuming more than their caller", func(t *testing.T) {
+		outer := invocation(0xa, 0xca, 0x1, 10, 0, invocation(0xb, 0xcb, 0x2, 20, 0))
+		p := profiler.New(&vm.TransactionTrace{ValidateInvocation: &outer})
+		assert.Equal(t, uint64(20), p.Total.Steps)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		profileJSON, err := json.Marshal(p.ByContract[0])
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"contract_address": "0xb",
+			"calls": 2,
+			"steps": 65,
+			"l1_gas": 0,
+			"l1_data_gas": 0,
+			"l2_gas": 650,
+			"calldata": 2
+		}`, string(profileJSON))
+	})
+}
+
+func TestWriteFolded(t *testing.T) {
+	p := profiler.New(testTrace())
+
+	var folded bytes.Buffer
+	require.NoError(t, p.WriteFolded(&folded, profiler.Steps))
+	assert.Equal(t, `execute;0xa:0x2 30
+execute;0xa:0x2;0xb:0x3 65
+execute;0xa:0x2;0xb:0x3;0xc:0x4 5
+validate;0xa:0x1 10
+`, folded.String())
+
+	folded.Reset()
+	require.NoError(t, p.WriteFolded(&folded, profiler.Calls))
+	assert.Contains(t, folded.String(), "execute;0xa:0x2;0xb:0x3 2\n")
+}
+
+func TestWritePProf(t *testing.T) {
+	p := profiler.New(testTrace())
+
+	var out bytes.Buffer
+	require.NoError(t, p.WritePProf(&out))
+	prof, err := profile.Parse(&out)
+	require.NoError(t, err)
+
+	require.Len(t, prof.SampleType, len(profiler.Metrics))
+	assert.Equal(t, "steps", prof.SampleType[0].Type)
+	require.Len(t, prof.Sample, 4)
+
+	var steps int64
+	for _, sample := range prof.Sample {
+		steps += sample.Value[0]
+		// the root of every stack is the phase of the transaction
+		root := sample.Location[len(sample.Location)-1].Line[0].Function.Name
+		assert.Contains(t, []string{"validate", "execute"}, root)
+	}
+	assert.Equal(t, int64(110), steps)
+}
+
+func TestMetricUnmarshalText(t *testing.T) {
+	var metric profiler.Metric
+	require.NoError(t, metric.UnmarshalText([]byte("l2_gas")))
+	assert.Equal(t, profiler.L2Gas, metric)
+	require.Error(t, metric.UnmarshalText([]byte("gas")))
+}
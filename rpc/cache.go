@@ -0,0 +1,306 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	stdsync "sync"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/sync"
+	"github.com/ethereum/go-ethereum/common/lru"
+)
+
+var (
+	rawMessageType   = reflect.TypeOf(json.RawMessage{})
+	contextInterface = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// finalityCheck reports whether the result of a successful call with the given arguments
+// can no longer change, i.e. it only depends on data at or below the L1 head.
+type finalityCheck func(c *ResponseCache, l1Head *core.L1Head, args []reflect.Value) bool
+
+// cacheableMethods lists the methods whose responses are eligible for caching,
+// along with the check deciding whether a particular response is immutable.
+var cacheableMethods = map[string]finalityCheck{
+	"starknet_getBlockWithTxHashes":  finalBlockAt(0),
+	"starknet_getBlockWithTxs":       finalBlockAt(0),
+	"starknet_getBlockWithReceipts":  finalBlockAt(0),
+	"starknet_getStateUpdate":        finalBlockAt(0),
+	"starknet_getTransactionReceipt": finalTransactionAt(0),
+	"starknet_getClass":              finalClassAt(1),
+}
+
+// ResponseCache stores the encoded responses of RPC methods whose results are immutable,
+// i.e. results for blocks that have been accepted on L1.
+//
+// Entries are keyed by API version, method and parameters. The cache is size-bounded and
+// evicts the least recently used entries first. Every reorg invalidates the whole cache
+// and bumps its generation, so that responses computed concurrently with a reorg are not stored.
+type ResponseCache struct {
+	bcReader   blockchain.Reader
+	syncReader sync.Reader
+	listener   EventListener
+	maxSize    uint64
+
+	mu         stdsync.Mutex
+	generation uint64
+	entries    *lru.SizeConstrainedCache[string, json.RawMessage]
+}
+
+// NewResponseCache creates a cache holding at most maxSize bytes of encoded responses.
+func NewResponseCache(bcReader blockchain.Reader, syncReader sync.Reader, maxSize uint64) *ResponseCache {
+	return &ResponseCache{
+		bcReader:   bcReader,
+		syncReader: syncReader,
+		listener:   &SelectiveListener{},
+		maxSize:    maxSize,
+		entries:    lru.NewSizeConstrainedCache[string, json.RawMessage](maxSize),
+	}
+}
+
+// WithListener registers an EventListener
+func (c *ResponseCache) WithListener(listener EventListener) *ResponseCache {
+	c.listener = listener
+	return c
+}
+
+// Run invalidates the cache every time the synchronizer reports a reorg.
+func (c *ResponseCache) Run(ctx context.Context) error {
+	reorgSub := c.syncReader.SubscribeReorg()
+	defer reorgSub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-reorgSub.Recv():
+			if !ok {
+				return nil
+			}
+			c.Purge()
+		}
+	}
+}
+
+// Purge drops all cached responses.
+func (c *ResponseCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.entries = lru.NewSizeConstrainedCache[string, json.RawMessage](c.maxSize)
+}
+
+func (c *ResponseCache) get(key string) (json.RawMessage, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res, found := c.entries.Get(key)
+	return res, c.generation, found
+}
+
+func (c *ResponseCache) add(generation uint64, key string, res json.RawMessage) {
+	if uint64(len(res)) > c.maxSize {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// The cache was invalidated while the response was being built.
+	if generation != c.generation {
+		return
+	}
+	c.entries.Add(key, res)
+}
+
+// wrapMethods wraps the handlers of all cacheable methods so that they serve from the cache.
+func (c *ResponseCache) wrapMethods(methods []jsonrpc.Method, path string) ([]jsonrpc.Method, string) {
+	if c == nil {
+		return methods, path
+	}
+
+	version := strings.TrimPrefix(path, "/")
+	for i := range methods {
+		if isFinal, ok := cacheableMethods[methods[i].Name]; ok {
+			methods[i].Handler = c.wrapHandler(version, methods[i].Name, methods[i].Handler, isFinal)
+		}
+	}
+	return methods, path
+}
+
+// wrapHandler returns a function with the same parameters as handler that returns the
+// JSON encoded result, so that cached responses don't need to be encoded again.
+func (c *ResponseCache) wrapHandler(version, method string, handler any, isFinal finalityCheck) any {
+	handlerV := reflect.ValueOf(handler)
+	handlerT := handlerV.Type()
+	// Handlers that return headers are not cached.
+	if handlerT.NumOut() != 2 {
+		return handler
+	}
+
+	in := make([]reflect.Type, handlerT.NumIn())
+	for i := range in {
+		in[i] = handlerT.In(i)
+	}
+	out := []reflect.Type{rawMessageType, handlerT.Out(1)}
+
+	wrapped := reflect.MakeFunc(reflect.FuncOf(in, out, false), func(args []reflect.Value) []reflect.Value {
+		res, rpcErr := c.serve(version, method, handlerV, args, isFinal)
+		errV := reflect.Zero(handlerT.Out(1))
+		if rpcErr != nil {
+			errV = reflect.ValueOf(rpcErr)
+		}
+		return []reflect.Value{reflect.ValueOf(res), errV}
+	})
+	return wrapped.Interface()
+}
+
+func (c *ResponseCache) serve(version, method string, handler reflect.Value, args []reflect.Value,
+	isFinal finalityCheck,
+) (json.RawMessage, *jsonrpc.Error) {
+	key, keyErr := cacheKey(version, method, args)
+	var generation uint64
+	if keyErr == nil {
+		var (
+			res   json.RawMessage
+			found bool
+		)
+		if res, generation, found = c.get(key); found {
+			c.listener.OnCacheHit(version, method)
+			return res, nil
+		}
+	}
+	c.listener.OnCacheMiss(version, method)
+
+	out := handler.Call(args)
+	if rpcErr, _ := out[1].Interface().(*jsonrpc.Error); rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	res, err := json.Marshal(out[0].Interface())
+	if err != nil {
+		return nil, jsonrpc.Err(jsonrpc.InternalError, err.Error())
+	}
+
+	if keyErr == nil && c.isFinal(isFinal, args) {
+		c.add(generation, key, res)
+	}
+	return res, nil
+}
+
+func (c *ResponseCache) isFinal(isFinal finalityCheck, args []reflect.Value) bool {
+	l1Head, err := c.bcReader.L1Head()
+	if err != nil || l1Head == nil {
+		return false
+	}
+	return isFinal(c, l1Head, args)
+}
+
+func cacheKey(version, method string, args []reflect.Value) (string, error) {
+	params := make([]any, 0, len(args))
+	for _, arg := range args {
+		if arg.Type().Implements(contextInterface) {
+			continue
+		}
+		// Marshal through a pointer so that pointer receiver marshalers (e.g. felt.Felt) are used.
+		ptr := reflect.New(arg.Type())
+		ptr.Elem().Set(arg)
+		params = append(params, ptr.Interface())
+	}
+
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+	return version + "/" + method + "/" + string(paramsJSON), nil
+}
+
+type blockIdentifier interface {
+	IsLatest() bool
+	IsPending() bool
+	GetHash() *felt.Felt
+	GetNumber() uint64
+}
+
+// finalBlockAt accepts responses for blocks explicitly identified by hash or number that are
+// at or below the L1 head. Responses for `latest` and `pending` are never final.
+func finalBlockAt(idx int) finalityCheck {
+	return func(c *ResponseCache, l1Head *core.L1Head, args []reflect.Value) bool {
+		id, ok := asBlockIdentifier(args[idx])
+		if !ok || id.IsLatest() || id.IsPending() {
+			return false
+		}
+
+		number := id.GetNumber()
+		if hash := id.GetHash(); hash != nil {
+			header, err := c.bcReader.BlockHeaderByHash(hash)
+			if err != nil {
+				return false
+			}
+			number = header.Number
+		}
+		return l1Head.BlockNumber >= number
+	}
+}
+
+// finalTransactionAt accepts responses for transactions included in blocks at or below the L1 head.
+func finalTransactionAt(idx int) finalityCheck {
+	return func(c *ResponseCache, l1Head *core.L1Head, args []reflect.Value) bool {
+		hash, ok := asFelt(args[idx])
+		if !ok {
+			return false
+		}
+
+		_, _, number, err := c.bcReader.Receipt(hash)
+		if err != nil {
+			return false
+		}
+		return l1Head.BlockNumber >= number
+	}
+}
+
+// finalClassAt accepts responses for classes declared at or below the L1 head. A class is
+// immutable once declared, so the response is the same for every block it can be queried at.
+func finalClassAt(idx int) finalityCheck {
+	return func(c *ResponseCache, l1Head *core.L1Head, args []reflect.Value) bool {
+		hash, ok := asFelt(args[idx])
+		if !ok {
+			return false
+		}
+
+		state, closer, err := c.bcReader.HeadState()
+		if err != nil {
+			return false
+		}
+		defer func() {
+			_ = closer()
+		}()
+
+		declared, err := state.Class(hash)
+		if err != nil {
+			return false
+		}
+		return l1Head.BlockNumber >= declared.At
+	}
+}
+
+func asBlockIdentifier(v reflect.Value) (blockIdentifier, bool) {
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	id, ok := ptr.Interface().(blockIdentifier)
+	return id, ok
+}
+
+func asFelt(v reflect.Value) (*felt.Felt, bool) {
+	switch f := v.Interface().(type) {
+	case felt.Felt:
+		return &f, true
+	case *felt.Felt:
+		return f, f != nil
+	default:
+		return nil, false
+	}
+}
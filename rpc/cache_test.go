@@ -0,0 +1,107 @@
+package rpc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/mocks"
+	rpcv8 "github.com/NethermindEth/juno/rpc/v8"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResponseCache(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	mockSyncReader := mocks.NewMockSyncReader(mockCtrl)
+	mockReader.EXPECT().L1Head().Return(&core.L1Head{BlockNumber: 5}, nil).AnyTimes()
+
+	var calls int
+	handler := func(id rpcv8.BlockID) (*core.Header, *jsonrpc.Error) {
+		calls++
+		if id.Number == 404 {
+			return nil, &jsonrpc.Error{Code: 24, Message: "Block not found"}
+		}
+		return &core.Header{Number: id.Number}, nil
+	}
+
+	var hits, misses int
+	cache := NewResponseCache(mockReader, mockSyncReader, utils.Megabyte).WithListener(&SelectiveListener{
+		OnCacheHitCb:  func(string, string) { hits++ },
+		OnCacheMissCb: func(string, string) { misses++ },
+	})
+	methods, _ := cache.wrapMethods([]jsonrpc.Method{{
+		Name:    "starknet_getBlockWithTxs",
+		Params:  []jsonrpc.Parameter{{Name: "block_id"}},
+		Handler: handler,
+	}}, "/v0_8")
+	server := jsonrpc.NewServer(1, utils.NewNopZapLogger())
+	require.NoError(t, server.RegisterMethods(methods...))
+
+	call := func(blockID string) string {
+		req := `{"jsonrpc":"2.0","id":1,"method":"starknet_getBlockWithTxs","params":[` + blockID + `]}`
+		res, _, err := server.HandleReader(t.Context(), strings.NewReader(req))
+		require.NoError(t, err)
+		return string(res)
+	}
+
+	t.Run("finalized block is served from cache", func(t *testing.T) {
+		calls, hits, misses = 0, 0, 0
+		first := call(`{"block_number":3}`)
+		second := call(`{"block_number":3}`)
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 1, hits)
+		assert.Equal(t, 1, misses)
+	})
+
+	t.Run("block above l1 head is not cached", func(t *testing.T) {
+		calls = 0
+		call(`{"block_number":6}`)
+		call(`{"block_number":6}`)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("latest is not cached", func(t *testing.T) {
+		calls = 0
+		call(`"latest"`)
+		call(`"latest"`)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		calls = 0
+		res := call(`{"block_number":404}`)
+		call(`{"block_number":404}`)
+		assert.Equal(t, 2, calls)
+		assert.Contains(t, res, "Block not found")
+	})
+
+	t.Run("block by hash", func(t *testing.T) {
+		calls = 0
+		hash := new(felt.Felt).SetUint64(42)
+		mockReader.EXPECT().BlockHeaderByHash(hash).Return(&core.Header{Number: 2}, nil)
+		hashJSON, err := json.Marshal(hash)
+		require.NoError(t, err)
+		blockID := `{"block_hash":` + string(hashJSON) + `}`
+		call(blockID)
+		call(blockID)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("purge invalidates entries", func(t *testing.T) {
+		calls = 0
+		call(`{"block_number":1}`)
+		cache.Purge()
+		call(`{"block_number":1}`)
+		assert.Equal(t, 2, calls)
+	})
+}
@@ -0,0 +1,23 @@
+package rpc
+
+type EventListener interface {
+	OnCacheHit(version, method string)
+	OnCacheMiss(version, method string)
+}
+
+type SelectiveListener struct {
+	OnCacheHitCb  func(version, method string)
+	OnCacheMissCb func(version, method string)
+}
+
+func (l *SelectiveListener) OnCacheHit(version, method string) {
+	if l.OnCacheHitCb != nil {
+		l.OnCacheHitCb(version, method)
+	}
+}
+
+func (l *SelectiveListener) OnCacheMiss(version, method string) {
+	if l.OnCacheMissCb != nil {
+		l.OnCacheMissCb(version, method)
+	}
+}
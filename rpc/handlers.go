@@ -20,6 +20,8 @@ type Handler struct {
 	rpcv6Handler *rpcv6.Handler
 	rpcv7Handler *rpcv7.Handler
 	rpcv8Handler *rpcv8.Handler
+
+	responseCache *ResponseCache
 }
 
 func New(bcReader blockchain.Reader, syncReader sync.Reader, virtualMachine vm.VM, version string,
@@ -77,9 +79,19 @@ func (h *Handler) WithGateway(gatewayClient rpccore.Gateway) *Handler {
 	return h
 }
 
+// WithResponseCache serves immutable responses from the given cache.
+func (h *Handler) WithResponseCache(cache *ResponseCache) *Handler {
+	h.responseCache = cache
+	return h
+}
+
 func (h *Handler) Run(ctx context.Context) error {
 	g, ctx := errgroup.WithContext(ctx)
 
+	if h.responseCache != nil {
+		g.Go(func() error { return h.responseCache.Run(ctx) })
+	}
+
 	g.Go(func() error { return h.rpcv6Handler.Run(ctx) })
 	g.Go(func() error { return h.rpcv7Handler.Run(ctx) })
 	g.Go(func() error { return h.rpcv8Handler.Run(ctx) })
@@ -87,7 +99,11 @@ func (h *Handler) Run(ctx context.Context) error {
 	return g.Wait()
 }
 
-func (h *Handler) MethodsV0_8() ([]jsonrpc.Method, string) { //nolint: funlen
+func (h *Handler) MethodsV0_8() ([]jsonrpc.Method, string) {
+	return h.responseCache.wrapMethods(h.methodsV0_8())
+}
+
+func (h *Handler) methodsV0_8() ([]jsonrpc.Method, string) { //nolint: funlen
 	return []jsonrpc.Method{
 		{
 			Name:    "starknet_chainId",
@@ -196,12 +212,26 @@ func (h *Handler) MethodsV0_8() ([]jsonrpc.Method, string) { //nolint: funlen
 		},
 		{
 			Name:    "starknet_call",
-			Params:  []jsonrpc.Parameter{{Name: "request"}, {Name: "block_id"}},
+			Params:  []jsonrpc.Parameter{{Name: "request"}, {Name: "block_id"}, {Name: "state_override", Optional: true}},
 			Handler: h.rpcv8Handler.Call,
 		},
 		{
-			Name:    "starknet_estimateFee",
-			Params:  []jsonrpc.Parameter{{Name: "request"}, {Name: "simulation_flags"}, {Name: "block_id"}},
+			Name:    "juno_callMany",
+			Params:  []jsonrpc.Parameter{{Name: "calls"}, {Name: "block_id"}, {Name: "state_override", Optional: true}},
+			Handler: h.rpcv8Handler.CallMany,
+		},
+		{
+			Name: "juno_feeHistory",
+			Params: []jsonrpc.Parameter{
+				{Name: "block_count"}, {Name: "newest_block"}, {Name: "reward_percentiles", Optional: true},
+			},
+			Handler: h.rpcv8Handler.FeeHistory,
+		},
+		{
+			Name: "starknet_estimateFee",
+			Params: []jsonrpc.Parameter{
+				{Name: "request"}, {Name: "simulation_flags"}, {Name: "block_id"}, {Name: "state_override", Optional: true},
+			},
 			Handler: h.rpcv8Handler.EstimateFee,
 		},
 		{
@@ -215,10 +245,26 @@ func (h *Handler) MethodsV0_8() ([]jsonrpc.Method, string) { //nolint: funlen
 			Handler: h.rpcv8Handler.TraceTransaction,
 		},
 		{
-			Name:    "starknet_simulateTransactions",
-			Params:  []jsonrpc.Parameter{{Name: "block_id"}, {Name: "transactions"}, {Name: "simulation_flags"}},
+			Name: "juno_profileTransaction",
+			Params: []jsonrpc.Parameter{
+				{Name: "transaction_hash"}, {Name: "format", Optional: true}, {Name: "metric", Optional: true},
+			},
+			Handler: h.rpcv8Handler.ProfileTransaction,
+		},
+		{
+			Name: "starknet_simulateTransactions",
+			Params: []jsonrpc.Parameter{
+				{Name: "block_id"}, {Name: "transactions"}, {Name: "simulation_flags"}, {Name: "state_override", Optional: true},
+			},
 			Handler: h.rpcv8Handler.SimulateTransactions,
 		},
+		{
+			Name: "juno_simulateBlocks",
+			Params: []jsonrpc.Parameter{
+				{Name: "block_id"}, {Name: "simulated_blocks"}, {Name: "simulation_flags"}, {Name: "state_override", Optional: true},
+			},
+			Handler: h.rpcv8Handler.SimulateBlocks,
+		},
 		{
 			Name:    "starknet_traceBlockTransactions",
 			Params:  []jsonrpc.Parameter{{Name: "block_id"}},
@@ -248,6 +294,10 @@ func (h *Handler) MethodsV0_8() ([]jsonrpc.Method, string) { //nolint: funlen
 			Params:  []jsonrpc.Parameter{{Name: "transaction_details", Optional: true}, {Name: "sender_address", Optional: true}},
 			Handler: h.rpcv8Handler.SubscribePendingTxs,
 		},
+		{
+			Name:    "juno_subscribePendingDeltas",
+			Handler: h.rpcv8Handler.SubscribePendingDeltas,
+		},
 		{
 			Name:    "starknet_unsubscribe",
 			Params:  []jsonrpc.Parameter{{Name: "subscription_id"}},
@@ -281,7 +331,11 @@ func (h *Handler) MethodsV0_8() ([]jsonrpc.Method, string) { //nolint: funlen
 	}, "/v0_8"
 }
 
-func (h *Handler) MethodsV0_7() ([]jsonrpc.Method, string) { //nolint: funlen
+func (h *Handler) MethodsV0_7() ([]jsonrpc.Method, string) {
+	return h.responseCache.wrapMethods(h.methodsV0_7())
+}
+
+func (h *Handler) methodsV0_7() ([]jsonrpc.Method, string) { //nolint: funlen
 	return []jsonrpc.Method{
 		{
 			Name:    "starknet_chainId",
@@ -439,7 +493,11 @@ func (h *Handler) MethodsV0_7() ([]jsonrpc.Method, string) { //nolint: funlen
 	}, "/v0_7"
 }
 
-func (h *Handler) MethodsV0_6() ([]jsonrpc.Method, string) { //nolint: funlen
+func (h *Handler) MethodsV0_6() ([]jsonrpc.Method, string) {
+	return h.responseCache.wrapMethods(h.methodsV0_6())
+}
+
+func (h *Handler) methodsV0_6() ([]jsonrpc.Method, string) { //nolint: funlen
 	return []jsonrpc.Method{
 		{
 			Name:    "starknet_chainId",
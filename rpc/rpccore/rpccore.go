@@ -17,6 +17,9 @@ const (
 	TraceCacheSize                = 128
 	ThrottledVMErr                = "VM throughput limit reached"
 	MaxBlocksBack                 = 1024
+	MaxCallManySize               = 1024
+	MaxSimulatedBlocks            = 128
+	MaxFeeHistoryBlocks           = 1024
 	EntrypointNotFoundFelt string = "0x454e545259504f494e545f4e4f545f464f554e44"
 	ErrEPSNotFound                = "Entry point EntryPointSelector(%s) not found in contract."
 )
@@ -53,7 +53,7 @@ type EventsChunk struct {
 //
 // It follows the specification defined here:
 // https://github.com/starkware-libs/starknet-specs/blob/94a969751b31f5d3e25a0c6850c723ddadeeb679/api/starknet_api_openrpc.json#L642
-func (h *Handler) Events(args EventsArg) (*EventsChunk, *jsonrpc.Error) {
+func (h *Handler) Events(ctx context.Context, args EventsArg) (*EventsChunk, *jsonrpc.Error) {
 	if args.ChunkSize > rpccore.MaxEventChunkSize {
 		return nil, rpccore.ErrPageSizeTooBig
 	} else {
@@ -90,7 +90,7 @@ func (h *Handler) Events(args EventsArg) (*EventsChunk, *jsonrpc.Error) {
 		return nil, rpccore.ErrBlockNotFound
 	}
 
-	filteredEvents, cToken, err := filter.Events(cToken, args.ChunkSize)
+	filteredEvents, cToken, err := filter.Events(ctx, cToken, args.ChunkSize)
 	if err != nil {
 		return nil, rpccore.ErrInternal
 	}
@@ -49,6 +49,9 @@ func (h *Handler) blockByID(id *BlockID) (*core.Block, *jsonrpc.Error) {
 		block, err = h.bcReader.BlockByNumber(id.Number)
 	}
 	if err != nil {
+		if errors.Is(err, blockchain.ErrHistoryUnavailable) {
+			return nil, rpccore.ErrBlockNotFound.CloneWithData(err.Error())
+		}
 		if errors.Is(err, db.ErrKeyNotFound) || errors.Is(err, sync.ErrPendingBlockNotFound) {
 			return nil, rpccore.ErrBlockNotFound
 		}
@@ -80,6 +83,9 @@ func (h *Handler) blockHeaderByID(id *BlockID) (*core.Header, *jsonrpc.Error) {
 	}
 
 	if err != nil {
+		if errors.Is(err, blockchain.ErrHistoryUnavailable) {
+			return nil, rpccore.ErrBlockNotFound.CloneWithData(err.Error())
+		}
 		if errors.Is(err, db.ErrKeyNotFound) || errors.Is(err, sync.ErrPendingBlockNotFound) {
 			return nil, rpccore.ErrBlockNotFound
 		}
@@ -121,12 +127,11 @@ func adaptExecutionResources(resources *core.ExecutionResources, v0_6Response bo
 }
 
 func (h *Handler) getRevealedBlockHash(blockNumber uint64) (*felt.Felt, error) {
-	const blockHashLag = 10
-	if blockNumber < blockHashLag {
+	if blockNumber < core.BlockHashLag {
 		return nil, nil
 	}
 
-	header, err := h.bcReader.BlockHeaderByNumber(blockNumber - blockHashLag)
+	header, err := h.bcReader.BlockHeaderByNumber(blockNumber - core.BlockHashLag)
 	if err != nil {
 		return nil, err
 	}
@@ -165,6 +170,9 @@ func (h *Handler) stateByBlockID(id *BlockID) (core.StateReader, blockchain.Stat
 	}
 
 	if err != nil {
+		if errors.Is(err, blockchain.ErrHistoryUnavailable) {
+			return nil, nil, rpccore.ErrBlockNotFound.CloneWithData(err.Error())
+		}
 		if errors.Is(err, db.ErrKeyNotFound) || errors.Is(err, sync.ErrPendingBlockNotFound) {
 			return nil, nil, rpccore.ErrBlockNotFound
 		}
@@ -1,6 +1,7 @@
 package rpcv6
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -49,13 +50,13 @@ type TracedBlockTransaction struct {
 *****************************************************/
 
 // pre 13.1
-func (h *Handler) SimulateTransactions(id BlockID, broadcastedTxns []BroadcastedTransaction,
+func (h *Handler) SimulateTransactions(ctx context.Context, id BlockID, broadcastedTxns []BroadcastedTransaction,
 	simulationFlags []SimulationFlag,
 ) ([]SimulatedTransaction, *jsonrpc.Error) {
-	return h.simulateTransactions(id, broadcastedTxns, simulationFlags, false)
+	return h.simulateTransactions(ctx, id, broadcastedTxns, simulationFlags, false)
 }
 
-func (h *Handler) simulateTransactions(id BlockID, transactions []BroadcastedTransaction,
+func (h *Handler) simulateTransactions(ctx context.Context, id BlockID, transactions []BroadcastedTransaction,
 	simulationFlags []SimulationFlag, errOnRevert bool,
 ) ([]SimulatedTransaction, *jsonrpc.Error) {
 	skipFeeCharge := slices.Contains(simulationFlags, SkipFeeChargeFlag)
@@ -87,7 +88,7 @@ func (h *Handler) simulateTransactions(id BlockID, transactions []BroadcastedTra
 		BlockHashToBeRevealed: blockHashToBeRevealed,
 	}
 
-	executionResults, err := h.vm.Execute(txns, classes, paidFeesOnL1, &blockInfo,
+	executionResults, err := vm.WithContext(ctx, h.vm).Execute(txns, classes, paidFeesOnL1, &blockInfo,
 		state, network, skipFeeCharge, skipValidate, errOnRevert, false)
 	if err != nil {
 		return nil, handleExecutionError(err)
@@ -3,6 +3,7 @@ package rpcv6
 import (
 	"errors"
 
+	"github.com/NethermindEth/juno/blockchain"
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/db"
@@ -87,6 +88,9 @@ func (h *Handler) StateUpdate(id BlockID) (*StateUpdate, *jsonrpc.Error) {
 		update, err = h.bcReader.StateUpdateByNumber(id.Number)
 	}
 	if err != nil {
+		if errors.Is(err, blockchain.ErrHistoryUnavailable) {
+			return nil, rpccore.ErrBlockNotFound.CloneWithData(err.Error())
+		}
 		if errors.Is(err, db.ErrKeyNotFound) || errors.Is(err, sync.ErrPendingBlockNotFound) {
 			return nil, rpccore.ErrBlockNotFound
 		}
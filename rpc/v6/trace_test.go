@@ -1063,7 +1063,7 @@ func TestCall(t *testing.T) {
 	t.Run("empty blockchain", func(t *testing.T) {
 		mockReader.EXPECT().HeadState().Return(nil, nil, db.ErrKeyNotFound)
 
-		res, rpcErr := handler.Call(&rpc.FunctionCall{}, &rpc.BlockID{Latest: true})
+		res, rpcErr := handler.Call(t.Context(), &rpc.FunctionCall{}, &rpc.BlockID{Latest: true})
 		require.Nil(t, res)
 		assert.Equal(t, rpccore.ErrBlockNotFound, rpcErr)
 	})
@@ -1071,7 +1071,7 @@ func TestCall(t *testing.T) {
 	t.Run("non-existent block hash", func(t *testing.T) {
 		mockReader.EXPECT().StateAtBlockHash(&felt.Zero).Return(nil, nil, db.ErrKeyNotFound)
 
-		res, rpcErr := handler.Call(&rpc.FunctionCall{}, &rpc.BlockID{Hash: &felt.Zero})
+		res, rpcErr := handler.Call(t.Context(), &rpc.FunctionCall{}, &rpc.BlockID{Hash: &felt.Zero})
 		require.Nil(t, res)
 		assert.Equal(t, rpccore.ErrBlockNotFound, rpcErr)
 	})
@@ -1079,7 +1079,7 @@ func TestCall(t *testing.T) {
 	t.Run("non-existent block number", func(t *testing.T) {
 		mockReader.EXPECT().StateAtBlockNumber(uint64(0)).Return(nil, nil, db.ErrKeyNotFound)
 
-		res, rpcErr := handler.Call(&rpc.FunctionCall{}, &rpc.BlockID{Number: 0})
+		res, rpcErr := handler.Call(t.Context(), &rpc.FunctionCall{}, &rpc.BlockID{Number: 0})
 		require.Nil(t, res)
 		assert.Equal(t, rpccore.ErrBlockNotFound, rpcErr)
 	})
@@ -1091,7 +1091,7 @@ func TestCall(t *testing.T) {
 		mockReader.EXPECT().HeadsHeader().Return(new(core.Header), nil)
 		mockState.EXPECT().ContractClassHash(&felt.Zero).Return(nil, errors.New("unknown contract"))
 
-		res, rpcErr := handler.Call(&rpc.FunctionCall{}, &rpc.BlockID{Latest: true})
+		res, rpcErr := handler.Call(t.Context(), &rpc.FunctionCall{}, &rpc.BlockID{Latest: true})
 		require.Nil(t, res)
 		assert.Equal(t, rpccore.ErrContractNotFound, rpcErr)
 	})
@@ -1136,7 +1136,7 @@ func TestCall(t *testing.T) {
 			Calldata:        calldata,
 		}, &vm.BlockInfo{Header: headsHeader}, gomock.Any(), &utils.Mainnet, uint64(1337), cairoClass.SierraVersion(), false, false).Return(expectedRes, nil)
 
-		res, rpcErr := handler.Call(&rpc.FunctionCall{
+		res, rpcErr := handler.Call(t.Context(), &rpc.FunctionCall{
 			ContractAddress:    *contractAddr,
 			EntryPointSelector: *selector,
 			Calldata:           calldata,
@@ -1186,7 +1186,7 @@ func TestCall(t *testing.T) {
 		mockReader.EXPECT().Network().Return(n)
 		mockVM.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), false).Return(expectedRes, nil)
 
-		res, rpcErr := handler.Call(&rpc.FunctionCall{
+		res, rpcErr := handler.Call(t.Context(), &rpc.FunctionCall{
 			ContractAddress:    *contractAddr,
 			EntryPointSelector: *selector,
 			Calldata:           calldata,
@@ -1227,7 +1227,7 @@ func TestCall(t *testing.T) {
 		mockReader.EXPECT().Network().Return(n)
 		mockVM.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(expectedRes, nil)
 
-		res, rpcErr := handler.Call(&rpc.FunctionCall{
+		res, rpcErr := handler.Call(t.Context(), &rpc.FunctionCall{
 			ContractAddress:    *contractAddr,
 			EntryPointSelector: *selector,
 			Calldata:           calldata,
@@ -1267,7 +1267,7 @@ func TestCall(t *testing.T) {
 		mockReader.EXPECT().Network().Return(n)
 		mockVM.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(expectedRes, nil)
 
-		res, rpcErr := handler.Call(&rpc.FunctionCall{
+		res, rpcErr := handler.Call(t.Context(), &rpc.FunctionCall{
 			ContractAddress:    *contractAddr,
 			EntryPointSelector: *selector,
 			Calldata:           calldata,
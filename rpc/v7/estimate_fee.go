@@ -1,6 +1,7 @@
 package rpcv7
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -43,10 +44,10 @@ type FeeEstimate struct {
 		Estimate Fee Handlers
 *****************************************************/
 
-func (h *Handler) EstimateFee(broadcastedTxns []BroadcastedTransaction,
+func (h *Handler) EstimateFee(ctx context.Context, broadcastedTxns []BroadcastedTransaction,
 	simulationFlags []rpcv6.SimulationFlag, id BlockID,
 ) ([]FeeEstimate, http.Header, *jsonrpc.Error) {
-	result, httpHeader, err := h.simulateTransactions(id, broadcastedTxns, append(simulationFlags, rpcv6.SkipFeeChargeFlag), true)
+	result, httpHeader, err := h.simulateTransactions(ctx, id, broadcastedTxns, append(simulationFlags, rpcv6.SkipFeeChargeFlag), true)
 	if err != nil {
 		return nil, httpHeader, err
 	}
@@ -56,16 +57,16 @@ func (h *Handler) EstimateFee(broadcastedTxns []BroadcastedTransaction,
 	}), httpHeader, nil
 }
 
-func (h *Handler) EstimateMessageFee(msg rpcv6.MsgFromL1, id BlockID) (*FeeEstimate, http.Header, *jsonrpc.Error) { //nolint:gocritic
-	return h.estimateMessageFee(msg, id, h.EstimateFee)
+func (h *Handler) EstimateMessageFee(ctx context.Context, msg rpcv6.MsgFromL1, id BlockID) (*FeeEstimate, http.Header, *jsonrpc.Error) { //nolint:gocritic
+	return h.estimateMessageFee(ctx, msg, id, h.EstimateFee)
 }
 
-type estimateFeeHandler func(broadcastedTxns []BroadcastedTransaction,
+type estimateFeeHandler func(ctx context.Context, broadcastedTxns []BroadcastedTransaction,
 	simulationFlags []rpcv6.SimulationFlag, id BlockID,
 ) ([]FeeEstimate, http.Header, *jsonrpc.Error)
 
 //nolint:gocritic
-func (h *Handler) estimateMessageFee(msg rpcv6.MsgFromL1, id BlockID, f estimateFeeHandler) (*FeeEstimate,
+func (h *Handler) estimateMessageFee(ctx context.Context, msg rpcv6.MsgFromL1, id BlockID, f estimateFeeHandler) (*FeeEstimate,
 	http.Header, *jsonrpc.Error,
 ) {
 	calldata := make([]*felt.Felt, 0, len(msg.Payload)+1)
@@ -87,7 +88,7 @@ func (h *Handler) estimateMessageFee(msg rpcv6.MsgFromL1, id BlockID, f estimate
 		// Must be greater than zero to successfully execute transaction.
 		PaidFeeOnL1: new(felt.Felt).SetUint64(1),
 	}
-	estimates, httpHeader, rpcErr := f([]BroadcastedTransaction{tx}, nil, id)
+	estimates, httpHeader, rpcErr := f(ctx, []BroadcastedTransaction{tx}, nil, id)
 	if rpcErr != nil {
 		if rpcErr.Code == rpccore.ErrTransactionExecutionError.Code {
 			data := rpcErr.Data.(TransactionExecutionErrorData)
@@ -1,6 +1,7 @@
 package rpcv7
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -33,13 +34,13 @@ type TracedBlockTransaction struct {
 		Simulate Handlers
 *****************************************************/
 
-func (h *Handler) SimulateTransactions(id BlockID, transactions []BroadcastedTransaction,
+func (h *Handler) SimulateTransactions(ctx context.Context, id BlockID, transactions []BroadcastedTransaction,
 	simulationFlags []rpcv6.SimulationFlag,
 ) ([]SimulatedTransaction, http.Header, *jsonrpc.Error) {
-	return h.simulateTransactions(id, transactions, simulationFlags, false)
+	return h.simulateTransactions(ctx, id, transactions, simulationFlags, false)
 }
 
-func (h *Handler) simulateTransactions(id BlockID, transactions []BroadcastedTransaction,
+func (h *Handler) simulateTransactions(ctx context.Context, id BlockID, transactions []BroadcastedTransaction,
 	simulationFlags []rpcv6.SimulationFlag, errOnRevert bool,
 ) ([]SimulatedTransaction, http.Header, *jsonrpc.Error) {
 	skipFeeCharge := slices.Contains(simulationFlags, rpcv6.SkipFeeChargeFlag)
@@ -74,7 +75,7 @@ func (h *Handler) simulateTransactions(id BlockID, transactions []BroadcastedTra
 		BlockHashToBeRevealed: blockHashToBeRevealed,
 	}
 
-	executionResults, err := h.vm.Execute(txns, classes, paidFeesOnL1, &blockInfo,
+	executionResults, err := vm.WithContext(ctx, h.vm).Execute(txns, classes, paidFeesOnL1, &blockInfo,
 		state, network, skipFeeCharge, skipValidate, errOnRevert, false)
 	if err != nil {
 		return nil, httpHeader, handleExecutionError(err)
@@ -1444,7 +1444,7 @@ func TestCall(t *testing.T) {
 	t.Run("empty blockchain", func(t *testing.T) {
 		mockReader.EXPECT().HeadState().Return(nil, nil, db.ErrKeyNotFound)
 
-		res, rpcErr := handler.Call(rpcv7.FunctionCall{}, rpcv7.BlockID{Latest: true})
+		res, rpcErr := handler.Call(t.Context(), rpcv7.FunctionCall{}, rpcv7.BlockID{Latest: true})
 		require.Nil(t, res)
 		assert.Equal(t, rpccore.ErrBlockNotFound, rpcErr)
 	})
@@ -1452,7 +1452,7 @@ func TestCall(t *testing.T) {
 	t.Run("non-existent block hash", func(t *testing.T) {
 		mockReader.EXPECT().StateAtBlockHash(&felt.Zero).Return(nil, nil, db.ErrKeyNotFound)
 
-		res, rpcErr := handler.Call(rpcv7.FunctionCall{}, rpcv7.BlockID{Hash: &felt.Zero})
+		res, rpcErr := handler.Call(t.Context(), rpcv7.FunctionCall{}, rpcv7.BlockID{Hash: &felt.Zero})
 		require.Nil(t, res)
 		assert.Equal(t, rpccore.ErrBlockNotFound, rpcErr)
 	})
@@ -1460,7 +1460,7 @@ func TestCall(t *testing.T) {
 	t.Run("non-existent block number", func(t *testing.T) {
 		mockReader.EXPECT().StateAtBlockNumber(uint64(0)).Return(nil, nil, db.ErrKeyNotFound)
 
-		res, rpcErr := handler.Call(rpcv7.FunctionCall{}, rpcv7.BlockID{Number: 0})
+		res, rpcErr := handler.Call(t.Context(), rpcv7.FunctionCall{}, rpcv7.BlockID{Number: 0})
 		require.Nil(t, res)
 		assert.Equal(t, rpccore.ErrBlockNotFound, rpcErr)
 	})
@@ -1472,7 +1472,7 @@ func TestCall(t *testing.T) {
 		mockReader.EXPECT().HeadsHeader().Return(new(core.Header), nil)
 		mockState.EXPECT().ContractClassHash(&felt.Zero).Return(nil, errors.New("unknown contract"))
 
-		res, rpcErr := handler.Call(rpcv7.FunctionCall{}, rpcv7.BlockID{Latest: true})
+		res, rpcErr := handler.Call(t.Context(), rpcv7.FunctionCall{}, rpcv7.BlockID{Latest: true})
 		require.Nil(t, res)
 		assert.Equal(t, rpccore.ErrContractNotFound, rpcErr)
 	})
@@ -1519,7 +1519,7 @@ func TestCall(t *testing.T) {
 			Calldata:        calldata,
 		}, &vm.BlockInfo{Header: headsHeader}, gomock.Any(), &utils.Mainnet, uint64(1337), cairoClass.SierraVersion(), false, false).Return(expectedRes, nil)
 
-		res, rpcErr := handler.Call(rpcv7.FunctionCall{
+		res, rpcErr := handler.Call(t.Context(), rpcv7.FunctionCall{
 			ContractAddress:    *contractAddr,
 			EntryPointSelector: *selector,
 			Calldata:           calldata,
@@ -1574,7 +1574,7 @@ func TestCall(t *testing.T) {
 			Calldata:        calldata,
 		}, &vm.BlockInfo{Header: headsHeader}, gomock.Any(), &utils.Mainnet, uint64(1337), cairoClass.SierraVersion(), false, false).Return(expectedRes, nil)
 
-		res, rpcErr := handler.Call(rpcv7.FunctionCall{
+		res, rpcErr := handler.Call(t.Context(), rpcv7.FunctionCall{
 			ContractAddress:    *contractAddr,
 			EntryPointSelector: *selector,
 			Calldata:           calldata,
@@ -1615,7 +1615,7 @@ func TestCall(t *testing.T) {
 		mockReader.EXPECT().Network().Return(n)
 		mockVM.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(expectedRes, nil)
 
-		res, rpcErr := handler.Call(rpcv7.FunctionCall{
+		res, rpcErr := handler.Call(t.Context(), rpcv7.FunctionCall{
 			ContractAddress:    *contractAddr,
 			EntryPointSelector: *selector,
 			Calldata:           calldata,
@@ -1655,7 +1655,7 @@ func TestCall(t *testing.T) {
 		mockReader.EXPECT().Network().Return(n)
 		mockVM.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(expectedRes, nil)
 
-		res, rpcErr := handler.Call(rpcv7.FunctionCall{
+		res, rpcErr := handler.Call(t.Context(), rpcv7.FunctionCall{
 			ContractAddress:    *contractAddr,
 			EntryPointSelector: *selector,
 			Calldata:           calldata,
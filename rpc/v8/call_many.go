@@ -0,0 +1,105 @@
+package rpcv8
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/rpc/rpccore"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
+)
+
+// CallResult is the outcome of a single call of a juno_callMany batch, either its result or its error.
+type CallResult struct {
+	Result []*felt.Felt   `json:"result,omitempty"`
+	Error  *jsonrpc.Error `json:"error,omitempty"`
+}
+
+// CallMany evaluates all the calls against the same state snapshot, taking a single VM execution slot
+// for the whole batch. Unlike a batch of starknet_call requests, every call sees the same block even if
+// a new one arrives meanwhile, and a failing call doesn't fail the batch: its error is returned in its
+// result instead.
+func (h *Handler) CallMany(ctx context.Context, calls []FunctionCall, id BlockID, //nolint:gocritic
+	stateOverride *StateOverride,
+) ([]CallResult, *jsonrpc.Error) {
+	if len(calls) > rpccore.MaxCallManySize {
+		return nil, jsonrpc.Err(jsonrpc.InvalidParams,
+			fmt.Sprintf("cannot evaluate more than %d calls at once", rpccore.MaxCallManySize))
+	}
+
+	state, closer, rpcErr := h.stateByBlockID(&id)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	defer h.callAndLogErr(closer, "Failed to close state in juno_callMany")
+
+	state, rpcErr = applyStateOverride(state, stateOverride)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	header, rpcErr := h.blockHeaderByID(&id)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	blockHashToBeRevealed, err := h.getRevealedBlockHash(header.Number)
+	if err != nil {
+		return nil, rpccore.ErrInternal.CloneWithData(err)
+	}
+	blockInfo := &vm.BlockInfo{
+		Header:                header,
+		BlockHashToBeRevealed: blockHashToBeRevealed,
+	}
+
+	state = newClassCachingState(state)
+	results := make([]CallResult, len(calls))
+	err = vm.Batch(vm.WithContext(ctx, h.vm), func(virtualMachine vm.VM) error {
+		for idx := range calls {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			results[idx].Result, results[idx].Error = h.call(virtualMachine, &calls[idx], blockInfo, state)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, utils.ErrResourceBusy) {
+			return nil, rpccore.ErrInternal.CloneWithData(rpccore.ThrottledVMErr)
+		}
+		return nil, rpccore.ErrInternal.CloneWithData(err.Error())
+	}
+	return results, nil
+}
+
+// classCachingState keeps the classes read through it, so that the calls of a batch decode every class
+// only once. It is not safe for concurrent use.
+type classCachingState struct {
+	core.StateReader
+
+	classes map[felt.Felt]*core.DeclaredClass
+}
+
+func newClassCachingState(state core.StateReader) *classCachingState {
+	return &classCachingState{
+		StateReader: state,
+		classes:     make(map[felt.Felt]*core.DeclaredClass),
+	}
+}
+
+func (s *classCachingState) Class(classHash *felt.Felt) (*core.DeclaredClass, error) {
+	if class, found := s.classes[*classHash]; found {
+		return class, nil
+	}
+
+	class, err := s.StateReader.Class(classHash)
+	if err != nil {
+		return nil, err
+	}
+	s.classes[*classHash] = class
+	return class, nil
+}
@@ -0,0 +1,89 @@
+package rpcv8_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/mocks"
+	"github.com/NethermindEth/juno/rpc/rpccore"
+	rpc "github.com/NethermindEth/juno/rpc/v8"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCallMany(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	mockVM := mocks.NewMockVM(mockCtrl)
+	handler := rpc.New(mockReader, nil, mockVM, "", utils.NewNopZapLogger()).WithCallMaxSteps(1337)
+
+	t.Run("too many calls", func(t *testing.T) {
+		calls := make([]rpc.FunctionCall, rpccore.MaxCallManySize+1)
+		res, rpcErr := handler.CallMany(t.Context(), calls, rpc.BlockID{Latest: true}, nil)
+		require.Nil(t, res)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+	})
+
+	t.Run("empty blockchain", func(t *testing.T) {
+		mockReader.EXPECT().HeadState().Return(nil, nil, db.ErrKeyNotFound)
+
+		res, rpcErr := handler.CallMany(t.Context(), []rpc.FunctionCall{{}}, rpc.BlockID{Latest: true}, nil)
+		require.Nil(t, res)
+		assert.Equal(t, rpccore.ErrBlockNotFound, rpcErr)
+	})
+
+	t.Run("per call results", func(t *testing.T) {
+		mockState := mocks.NewMockStateHistoryReader(mockCtrl)
+
+		contractAddr := new(felt.Felt).SetUint64(1)
+		unknownAddr := new(felt.Felt).SetUint64(2)
+		classHash := new(felt.Felt).SetUint64(3)
+		selector := new(felt.Felt).SetUint64(4)
+		headsHeader := &core.Header{Number: 9}
+		cairoClass := core.Cairo1Class{
+			Program: []*felt.Felt{
+				new(felt.Felt).SetUint64(3),
+				new(felt.Felt),
+				new(felt.Felt),
+			},
+		}
+		expectedRes := vm.CallResult{Result: []*felt.Felt{new(felt.Felt).SetUint64(5)}}
+
+		mockReader.EXPECT().HeadState().Return(mockState, nopCloser, nil)
+		mockReader.EXPECT().HeadsHeader().Return(headsHeader, nil)
+		mockReader.EXPECT().Network().Return(&utils.Mainnet).Times(2)
+		mockState.EXPECT().ContractClassHash(contractAddr).Return(classHash, nil).Times(2)
+		mockState.EXPECT().ContractClassHash(unknownAddr).Return(nil, errors.New("unknown contract"))
+		// classes are only read once per batch
+		mockState.EXPECT().Class(classHash).Return(&core.DeclaredClass{Class: &cairoClass}, nil)
+		mockVM.EXPECT().Call(&vm.CallInfo{
+			ContractAddress: contractAddr,
+			ClassHash:       classHash,
+			Selector:        selector,
+		}, &vm.BlockInfo{Header: headsHeader}, gomock.Any(), &utils.Mainnet, uint64(1337),
+			cairoClass.SierraVersion(), true, false).Return(expectedRes, nil).Times(2)
+
+		call := rpc.FunctionCall{ContractAddress: *contractAddr, EntryPointSelector: *selector}
+		res, rpcErr := handler.CallMany(t.Context(), []rpc.FunctionCall{
+			call,
+			{ContractAddress: *unknownAddr, EntryPointSelector: *selector},
+			call,
+		}, rpc.BlockID{Latest: true}, nil)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, []rpc.CallResult{
+			{Result: expectedRes.Result},
+			{Error: rpccore.ErrContractNotFound},
+			{Result: expectedRes.Result},
+		}, res)
+	})
+}
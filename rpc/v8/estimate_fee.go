@@ -1,6 +1,7 @@
 package rpcv8
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -259,10 +260,14 @@ curl --location 'http://localhost:6060/rpc/v0_8' \
 }
 */
 
-func (h *Handler) EstimateFee(broadcastedTxns []BroadcastedTransaction,
-	simulationFlags []rpcv6.SimulationFlag, id BlockID,
+func (h *Handler) EstimateFee(ctx context.Context, broadcastedTxns []BroadcastedTransaction,
+	simulationFlags []rpcv6.SimulationFlag, id BlockID, stateOverride *StateOverride,
 ) ([]FeeEstimate, http.Header, *jsonrpc.Error) {
-	result, httpHeader, err := h.simulateTransactions(id, broadcastedTxns, append(simulationFlags, rpcv6.SkipFeeChargeFlag), true)
+	flags := make([]SimulationFlag, 0, len(simulationFlags)+1)
+	for _, flag := range simulationFlags {
+		flags = append(flags, SimulationFlag(flag))
+	}
+	result, httpHeader, err := h.simulateTransactions(ctx, id, broadcastedTxns, append(flags, SkipFeeChargeFlag), stateOverride, true)
 	if err != nil {
 		return nil, httpHeader, err
 	}
@@ -273,16 +278,16 @@ func (h *Handler) EstimateFee(broadcastedTxns []BroadcastedTransaction,
 }
 
 //nolint:gocritic
-func (h *Handler) EstimateMessageFee(msg rpcv6.MsgFromL1, id BlockID) (*FeeEstimate, http.Header, *jsonrpc.Error) {
-	return estimateMessageFee(msg, id, h.EstimateFee)
+func (h *Handler) EstimateMessageFee(ctx context.Context, msg rpcv6.MsgFromL1, id BlockID) (*FeeEstimate, http.Header, *jsonrpc.Error) {
+	return estimateMessageFee(ctx, msg, id, h.EstimateFee)
 }
 
-type estimateFeeHandler func(broadcastedTxns []BroadcastedTransaction,
-	simulationFlags []rpcv6.SimulationFlag, id BlockID,
+type estimateFeeHandler func(ctx context.Context, broadcastedTxns []BroadcastedTransaction,
+	simulationFlags []rpcv6.SimulationFlag, id BlockID, stateOverride *StateOverride,
 ) ([]FeeEstimate, http.Header, *jsonrpc.Error)
 
 //nolint:gocritic
-func estimateMessageFee(msg rpcv6.MsgFromL1, id BlockID, f estimateFeeHandler) (*FeeEstimate, http.Header, *jsonrpc.Error) {
+func estimateMessageFee(ctx context.Context, msg rpcv6.MsgFromL1, id BlockID, f estimateFeeHandler) (*FeeEstimate, http.Header, *jsonrpc.Error) {
 	calldata := make([]*felt.Felt, 0, len(msg.Payload)+1)
 	// The order of the calldata parameters matters. msg.From must be prepended.
 	calldata = append(calldata, new(felt.Felt).SetBytes(msg.From.Bytes()))
@@ -302,7 +307,7 @@ func estimateMessageFee(msg rpcv6.MsgFromL1, id BlockID, f estimateFeeHandler) (
 		// Must be greater than zero to successfully execute transaction.
 		PaidFeeOnL1: new(felt.Felt).SetUint64(1),
 	}
-	estimates, httpHeader, rpcErr := f([]BroadcastedTransaction{tx}, nil, id)
+	estimates, httpHeader, rpcErr := f(ctx, []BroadcastedTransaction{tx}, nil, id, nil)
 	if rpcErr != nil {
 		if rpcErr.Code == rpccore.ErrTransactionExecutionError.Code {
 			data := rpcErr.Data.(TransactionExecutionErrorData)
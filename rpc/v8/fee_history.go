@@ -0,0 +1,190 @@
+package rpcv8
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/rpc/rpccore"
+	rpcv6 "github.com/NethermindEth/juno/rpc/v6"
+)
+
+// FeeHistory has the gas prices of a range of consecutive blocks, from the oldest to the newest.
+type FeeHistory struct {
+	OldestBlock    uint64                `json:"oldest_block"`
+	L1GasPrice     []rpcv6.ResourcePrice `json:"l1_gas_price"`
+	L1DataGasPrice []rpcv6.ResourcePrice `json:"l1_data_gas_price"`
+	L2GasPrice     []rpcv6.ResourcePrice `json:"l2_gas_price"`
+	// Rewards are only returned if percentiles are requested.
+	Rewards []BlockRewards `json:"rewards,omitempty"`
+}
+
+// BlockRewards has the requested percentiles of the tips of the V3 transactions of a block and of the fees
+// paid by its transactions, per fee unit. Blocks with no transactions have zero percentiles.
+type BlockRewards struct {
+	Tips      []uint64     `json:"tips"`
+	FeesInFri []*felt.Felt `json:"fees_in_fri"`
+	FeesInWei []*felt.Felt `json:"fees_in_wei"`
+}
+
+// FeeHistory returns the gas prices of blockCount blocks up to newestBlock and, if rewardPercentiles are given,
+// the percentiles of the tips and fees paid in each of them. The spec has no equivalent, it is modelled after
+// eth_feeHistory with a price per resource instead of a base fee.
+func (h *Handler) FeeHistory(blockCount uint64, newestBlock BlockID, //nolint:gocritic
+	rewardPercentiles []float64,
+) (*FeeHistory, *jsonrpc.Error) {
+	if blockCount == 0 || blockCount > rpccore.MaxFeeHistoryBlocks {
+		return nil, jsonrpc.Err(jsonrpc.InvalidParams,
+			fmt.Sprintf("block count must be between 1 and %d", rpccore.MaxFeeHistoryBlocks))
+	}
+	for idx, percentile := range rewardPercentiles {
+		if percentile < 0 || percentile > 100 || (idx > 0 && percentile < rewardPercentiles[idx-1]) {
+			return nil, jsonrpc.Err(jsonrpc.InvalidParams, "reward percentiles must be increasing values between 0 and 100")
+		}
+	}
+	withRewards := len(rewardPercentiles) > 0
+
+	var newest *core.Block
+	if withRewards {
+		var rpcErr *jsonrpc.Error
+		if newest, rpcErr = h.blockByID(&newestBlock); rpcErr != nil {
+			return nil, rpcErr
+		}
+	} else {
+		header, rpcErr := h.blockHeaderByID(&newestBlock)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		newest = &core.Block{Header: header}
+	}
+
+	blockCount = min(blockCount, newest.Number+1)
+	history := &FeeHistory{
+		OldestBlock:    newest.Number + 1 - blockCount,
+		L1GasPrice:     make([]rpcv6.ResourcePrice, 0, blockCount),
+		L1DataGasPrice: make([]rpcv6.ResourcePrice, 0, blockCount),
+		L2GasPrice:     make([]rpcv6.ResourcePrice, 0, blockCount),
+	}
+	if withRewards {
+		history.Rewards = make([]BlockRewards, 0, blockCount)
+	}
+
+	for number := history.OldestBlock; number <= newest.Number; number++ {
+		block := newest
+		if number != newest.Number {
+			var err error
+			if block, err = h.feeHistoryBlock(number, withRewards); err != nil {
+				if errors.Is(err, db.ErrKeyNotFound) {
+					return nil, rpccore.ErrBlockNotFound
+				}
+				return nil, rpccore.ErrInternal.CloneWithData(err)
+			}
+		}
+
+		history.L1GasPrice = append(history.L1GasPrice, rpcv6.ResourcePrice{
+			InWei: nilToZero(block.L1GasPriceETH),
+			InFri: nilToZero(block.L1GasPriceSTRK),
+		})
+		history.L1DataGasPrice = append(history.L1DataGasPrice, adaptGasPrice(block.L1DataGasPrice))
+		history.L2GasPrice = append(history.L2GasPrice, adaptGasPrice(block.L2GasPrice))
+		if withRewards {
+			history.Rewards = append(history.Rewards, blockRewards(block, rewardPercentiles))
+		}
+	}
+	return history, nil
+}
+
+// feeHistoryBlock returns the block, or only its header if the transactions are not needed.
+func (h *Handler) feeHistoryBlock(number uint64, withTransactions bool) (*core.Block, error) {
+	if withTransactions {
+		return h.bcReader.BlockByNumber(number)
+	}
+
+	header, err := h.bcReader.BlockHeaderByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+	return &core.Block{Header: header}, nil
+}
+
+func adaptGasPrice(gasPrice *core.GasPrice) rpcv6.ResourcePrice {
+	if gasPrice == nil {
+		return rpcv6.ResourcePrice{InWei: &felt.Zero, InFri: &felt.Zero}
+	}
+	return rpcv6.ResourcePrice{
+		InWei: nilToZero(gasPrice.PriceInWei),
+		InFri: nilToZero(gasPrice.PriceInFri),
+	}
+}
+
+func blockRewards(block *core.Block, percentiles []float64) BlockRewards {
+	var (
+		tips      []uint64
+		feesInFri []*felt.Felt
+		feesInWei []*felt.Felt
+	)
+	for idx, txn := range block.Transactions {
+		if tip, ok := transactionTip(txn); ok {
+			tips = append(tips, tip)
+		}
+		if idx >= len(block.Receipts) {
+			continue
+		}
+
+		receipt := block.Receipts[idx]
+		switch receipt.FeeUnit {
+		case core.STRK:
+			feesInFri = append(feesInFri, nilToZero(receipt.Fee))
+		case core.WEI:
+			feesInWei = append(feesInWei, nilToZero(receipt.Fee))
+		}
+	}
+
+	slices.Sort(tips)
+	feltCmp := func(a, b *felt.Felt) int { return a.Cmp(b) }
+	slices.SortFunc(feesInFri, feltCmp)
+	slices.SortFunc(feesInWei, feltCmp)
+
+	rewards := BlockRewards{
+		Tips:      make([]uint64, len(percentiles)),
+		FeesInFri: make([]*felt.Felt, len(percentiles)),
+		FeesInWei: make([]*felt.Felt, len(percentiles)),
+	}
+	for idx, percentile := range percentiles {
+		rewards.Tips[idx] = percentileOf(tips, percentile, 0)
+		rewards.FeesInFri[idx] = percentileOf(feesInFri, percentile, &felt.Zero)
+		rewards.FeesInWei[idx] = percentileOf(feesInWei, percentile, &felt.Zero)
+	}
+	return rewards
+}
+
+// percentileOf returns the nearest-rank percentile of the sorted values, or empty if there are none.
+func percentileOf[T any](sorted []T, percentile float64, empty T) T {
+	if len(sorted) == 0 {
+		return empty
+	}
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	return sorted[min(max(rank-1, 0), len(sorted)-1)]
+}
+
+// transactionTip returns the tip of V3 transactions.
+func transactionTip(txn core.Transaction) (uint64, bool) {
+	var (
+		tip     uint64
+		version *core.TransactionVersion
+	)
+	switch t := txn.(type) {
+	case *core.InvokeTransaction:
+		tip, version = t.Tip, t.Version
+	case *core.DeclareTransaction:
+		tip, version = t.Tip, t.Version
+	case *core.DeployAccountTransaction:
+		tip, version = t.Tip, t.Version
+	}
+	return tip, version != nil && version.Is(3)
+}
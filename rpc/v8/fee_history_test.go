@@ -0,0 +1,112 @@
+package rpcv8_test
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/mocks"
+	rpcv6 "github.com/NethermindEth/juno/rpc/v6"
+	rpc "github.com/NethermindEth/juno/rpc/v8"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFeeHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	handler := rpc.New(mockReader, nil, nil, "", utils.NewNopZapLogger())
+
+	feltOf := func(v uint64) *felt.Felt { return new(felt.Felt).SetUint64(v) }
+	header := func(number uint64) *core.Header {
+		return &core.Header{
+			Number:         number,
+			L1GasPriceETH:  feltOf(number),
+			L1GasPriceSTRK: feltOf(10 * number),
+			L2GasPrice:     &core.GasPrice{PriceInWei: feltOf(100 * number), PriceInFri: feltOf(1000 * number)},
+		}
+	}
+
+	t.Run("invalid params", func(t *testing.T) {
+		for _, test := range []struct {
+			blockCount  uint64
+			percentiles []float64
+		}{
+			{blockCount: 0},
+			{blockCount: 1025},
+			{blockCount: 1, percentiles: []float64{101}},
+			{blockCount: 1, percentiles: []float64{50, 10}},
+		} {
+			_, rpcErr := handler.FeeHistory(test.blockCount, rpc.BlockID{Latest: true}, test.percentiles)
+			require.NotNil(t, rpcErr)
+			assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+		}
+	})
+
+	t.Run("gas prices", func(t *testing.T) {
+		mockReader.EXPECT().HeadsHeader().Return(header(1), nil)
+		mockReader.EXPECT().BlockHeaderByNumber(uint64(0)).Return(header(0), nil)
+
+		// the block count is capped at the number of blocks up to the newest one
+		history, rpcErr := handler.FeeHistory(5, rpc.BlockID{Latest: true}, nil)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.FeeHistory{
+			OldestBlock: 0,
+			L1GasPrice: []rpcv6.ResourcePrice{
+				{InWei: feltOf(0), InFri: feltOf(0)},
+				{InWei: feltOf(1), InFri: feltOf(10)},
+			},
+			L1DataGasPrice: []rpcv6.ResourcePrice{
+				{InWei: &felt.Zero, InFri: &felt.Zero},
+				{InWei: &felt.Zero, InFri: &felt.Zero},
+			},
+			L2GasPrice: []rpcv6.ResourcePrice{
+				{InWei: feltOf(0), InFri: feltOf(0)},
+				{InWei: feltOf(100), InFri: feltOf(1000)},
+			},
+		}, history)
+	})
+
+	t.Run("rewards", func(t *testing.T) {
+		v3 := new(core.TransactionVersion).SetUint64(3)
+		v1 := new(core.TransactionVersion).SetUint64(1)
+		block := &core.Block{
+			Header: header(7),
+			Transactions: []core.Transaction{
+				&core.InvokeTransaction{Version: v3, Tip: 30},
+				&core.InvokeTransaction{Version: v3, Tip: 10},
+				&core.InvokeTransaction{Version: v1},
+				&core.InvokeTransaction{Version: v3, Tip: 20},
+			},
+			Receipts: []*core.TransactionReceipt{
+				{Fee: feltOf(3), FeeUnit: core.STRK},
+				{Fee: feltOf(1), FeeUnit: core.STRK},
+				{Fee: feltOf(5), FeeUnit: core.WEI},
+				{Fee: feltOf(2), FeeUnit: core.STRK},
+			},
+		}
+		mockReader.EXPECT().BlockByNumber(uint64(6)).Return(&core.Block{Header: header(6)}, nil)
+		mockReader.EXPECT().BlockByNumber(uint64(7)).Return(block, nil)
+
+		history, rpcErr := handler.FeeHistory(2, rpc.BlockID{Number: 7}, []float64{0, 50, 100})
+		require.Nil(t, rpcErr)
+		assert.Equal(t, uint64(6), history.OldestBlock)
+		assert.Equal(t, []rpc.BlockRewards{
+			{
+				Tips:      []uint64{0, 0, 0},
+				FeesInFri: []*felt.Felt{&felt.Zero, &felt.Zero, &felt.Zero},
+				FeesInWei: []*felt.Felt{&felt.Zero, &felt.Zero, &felt.Zero},
+			},
+			{
+				Tips:      []uint64{10, 20, 30},
+				FeesInFri: []*felt.Felt{feltOf(1), feltOf(2), feltOf(3)},
+				FeesInWei: []*felt.Felt{feltOf(5), feltOf(5), feltOf(5)},
+			},
+		}, history.Rewards)
+	})
+}
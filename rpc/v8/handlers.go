@@ -207,12 +207,26 @@ func (h *Handler) methods() ([]jsonrpc.Method, string) { //nolint: funlen
 		},
 		{
 			Name:    "starknet_call",
-			Params:  []jsonrpc.Parameter{{Name: "request"}, {Name: "block_id"}},
+			Params:  []jsonrpc.Parameter{{Name: "request"}, {Name: "block_id"}, {Name: "state_override", Optional: true}},
 			Handler: h.Call,
 		},
 		{
-			Name:    "starknet_estimateFee",
-			Params:  []jsonrpc.Parameter{{Name: "request"}, {Name: "simulation_flags"}, {Name: "block_id"}},
+			Name:    "juno_callMany",
+			Params:  []jsonrpc.Parameter{{Name: "calls"}, {Name: "block_id"}, {Name: "state_override", Optional: true}},
+			Handler: h.CallMany,
+		},
+		{
+			Name: "juno_feeHistory",
+			Params: []jsonrpc.Parameter{
+				{Name: "block_count"}, {Name: "newest_block"}, {Name: "reward_percentiles", Optional: true},
+			},
+			Handler: h.FeeHistory,
+		},
+		{
+			Name: "starknet_estimateFee",
+			Params: []jsonrpc.Parameter{
+				{Name: "request"}, {Name: "simulation_flags"}, {Name: "block_id"}, {Name: "state_override", Optional: true},
+			},
 			Handler: h.EstimateFee,
 		},
 		{
@@ -226,10 +240,26 @@ func (h *Handler) methods() ([]jsonrpc.Method, string) { //nolint: funlen
 			Handler: h.TraceTransaction,
 		},
 		{
-			Name:    "starknet_simulateTransactions",
-			Params:  []jsonrpc.Parameter{{Name: "block_id"}, {Name: "transactions"}, {Name: "simulation_flags"}},
+			Name: "juno_profileTransaction",
+			Params: []jsonrpc.Parameter{
+				{Name: "transaction_hash"}, {Name: "format", Optional: true}, {Name: "metric", Optional: true},
+			},
+			Handler: h.ProfileTransaction,
+		},
+		{
+			Name: "starknet_simulateTransactions",
+			Params: []jsonrpc.Parameter{
+				{Name: "block_id"}, {Name: "transactions"}, {Name: "simulation_flags"}, {Name: "state_override", Optional: true},
+			},
 			Handler: h.SimulateTransactions,
 		},
+		{
+			Name: "juno_simulateBlocks",
+			Params: []jsonrpc.Parameter{
+				{Name: "block_id"}, {Name: "simulated_blocks"}, {Name: "simulation_flags"}, {Name: "state_override", Optional: true},
+			},
+			Handler: h.SimulateBlocks,
+		},
 		{
 			Name:    "starknet_traceBlockTransactions",
 			Params:  []jsonrpc.Parameter{{Name: "block_id"}},
@@ -259,6 +289,10 @@ func (h *Handler) methods() ([]jsonrpc.Method, string) { //nolint: funlen
 			Params:  []jsonrpc.Parameter{{Name: "transaction_details", Optional: true}, {Name: "sender_address", Optional: true}},
 			Handler: h.SubscribePendingTxs,
 		},
+		{
+			Name:    "juno_subscribePendingDeltas",
+			Handler: h.SubscribePendingDeltas,
+		},
 		{
 			Name:    "starknet_unsubscribe",
 			Params:  []jsonrpc.Parameter{{Name: "subscription_id"}},
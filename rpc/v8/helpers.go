@@ -50,6 +50,9 @@ func (h *Handler) blockByID(id *BlockID) (*core.Block, *jsonrpc.Error) {
 	}
 
 	if err != nil {
+		if errors.Is(err, blockchain.ErrHistoryUnavailable) {
+			return nil, rpccore.ErrBlockNotFound.CloneWithData(err.Error())
+		}
 		if errors.Is(err, db.ErrKeyNotFound) || errors.Is(err, sync.ErrPendingBlockNotFound) {
 			return nil, rpccore.ErrBlockNotFound
 		}
@@ -80,6 +83,9 @@ func (h *Handler) blockHeaderByID(id BlockIdentifier) (*core.Header, *jsonrpc.Er
 	}
 
 	if err != nil {
+		if errors.Is(err, blockchain.ErrHistoryUnavailable) {
+			return nil, rpccore.ErrBlockNotFound.CloneWithData(err.Error())
+		}
 		if errors.Is(err, db.ErrKeyNotFound) || errors.Is(err, sync.ErrPendingBlockNotFound) {
 			return nil, rpccore.ErrBlockNotFound
 		}
@@ -106,12 +112,11 @@ func adaptExecutionResources(resources *core.ExecutionResources) *ExecutionResou
 }
 
 func (h *Handler) getRevealedBlockHash(blockNumber uint64) (*felt.Felt, error) {
-	const blockHashLag = 10
-	if blockNumber < blockHashLag {
+	if blockNumber < core.BlockHashLag {
 		return nil, nil
 	}
 
-	header, err := h.bcReader.BlockHeaderByNumber(blockNumber - blockHashLag)
+	header, err := h.bcReader.BlockHeaderByNumber(blockNumber - core.BlockHashLag)
 	if err != nil {
 		return nil, err
 	}
@@ -150,6 +155,9 @@ func (h *Handler) stateByBlockID(id *BlockID) (core.StateReader, blockchain.Stat
 	}
 
 	if err != nil {
+		if errors.Is(err, blockchain.ErrHistoryUnavailable) {
+			return nil, nil, rpccore.ErrBlockNotFound.CloneWithData(err.Error())
+		}
 		if errors.Is(err, db.ErrKeyNotFound) || errors.Is(err, sync.ErrPendingBlockNotFound) {
 			return nil, nil, rpccore.ErrBlockNotFound
 		}
@@ -0,0 +1,93 @@
+package rpcv8
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/profiler"
+	"github.com/NethermindEth/juno/rpc/rpccore"
+)
+
+// ProfileFormat is the format a transaction profile is returned in.
+type ProfileFormat string
+
+const (
+	// ProfileJSON returns the costs aggregated by contract, class and entry point.
+	ProfileJSON ProfileFormat = "json"
+	// ProfileFolded returns the folded call stacks of flamegraph tools for a single metric.
+	ProfileFolded ProfileFormat = "folded"
+	// ProfilePProf returns a gzipped pprof profile with every metric, base64 encoded.
+	ProfilePProf ProfileFormat = "pprof"
+)
+
+func (f *ProfileFormat) UnmarshalText(text []byte) error {
+	switch format := ProfileFormat(text); format {
+	case ProfileJSON, ProfileFolded, ProfilePProf:
+		*f = format
+		return nil
+	default:
+		return fmt.Errorf("unknown profile format %q", text)
+	}
+}
+
+// TransactionProfile holds the profile of a transaction in the requested format.
+type TransactionProfile struct {
+	Profile *profiler.Profile `json:"profile,omitempty"`
+	Folded  string            `json:"folded,omitempty"`
+	PProf   []byte            `json:"pprof,omitempty"`
+}
+
+// ProfileTransaction re-executes a transaction and aggregates the execution resources of its call tree by
+// contract address, class hash and entry point selector. The folded format only carries the given metric,
+// which defaults to steps. starknet_traceTransaction returns the same call tree without aggregating it.
+func (h *Handler) ProfileTransaction(ctx context.Context, hash felt.Felt, format ProfileFormat,
+	metric profiler.Metric,
+) (*TransactionProfile, http.Header, *jsonrpc.Error) {
+	httpHeader := http.Header{}
+	httpHeader.Set(ExecutionStepsHeader, "0")
+
+	if format == "" {
+		format = ProfileJSON
+	}
+	if metric == "" {
+		metric = profiler.Steps
+	}
+
+	block, txIndex, rpcErr := h.transactionBlock(&hash)
+	if rpcErr != nil {
+		return nil, httpHeader, rpcErr
+	}
+
+	// the transactions before the profiled one are executed for it to run on the state it was executed on
+	executionResult, rpcErr := h.executeBlock(ctx, block, block.Transactions[:txIndex+1])
+	httpHeader.Set(ExecutionStepsHeader, strconv.FormatUint(executionResult.NumSteps, 10))
+	if rpcErr != nil {
+		return nil, httpHeader, rpcErr
+	}
+	if len(executionResult.Traces) <= txIndex {
+		return nil, httpHeader, rpccore.ErrUnexpectedError.CloneWithData("missing transaction trace")
+	}
+
+	profile := profiler.New(&executionResult.Traces[txIndex])
+	switch format {
+	case ProfileFolded:
+		var folded bytes.Buffer
+		if err := profile.WriteFolded(&folded, metric); err != nil {
+			return nil, httpHeader, rpccore.ErrInternal.CloneWithData(err.Error())
+		}
+		return &TransactionProfile{Folded: folded.String()}, httpHeader, nil
+	case ProfilePProf:
+		var pprof bytes.Buffer
+		if err := profile.WritePProf(&pprof); err != nil {
+			return nil, httpHeader, rpccore.ErrInternal.CloneWithData(err.Error())
+		}
+		return &TransactionProfile{PProf: pprof.Bytes()}, httpHeader, nil
+	default:
+		return &TransactionProfile{Profile: profile}, httpHeader, nil
+	}
+}
@@ -0,0 +1,87 @@
+package rpcv8_test
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/mocks"
+	"github.com/NethermindEth/juno/profiler"
+	"github.com/NethermindEth/juno/rpc/rpccore"
+	rpc "github.com/NethermindEth/juno/rpc/v8"
+	"github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProfileTransaction(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	mockSyncReader := mocks.NewMockSyncReader(mockCtrl)
+	mockReader.EXPECT().Network().Return(&utils.Mainnet).AnyTimes()
+	mockVM := mocks.NewMockVM(mockCtrl)
+	handler := rpc.New(mockReader, mockSyncReader, mockVM, "", utils.NewNopZapLogger())
+
+	t.Run("not found", func(t *testing.T) {
+		hash := utils.HexToFelt(t, "0xBBBB")
+		mockReader.EXPECT().Receipt(hash).Return(nil, nil, uint64(0), db.ErrKeyNotFound)
+		mockSyncReader.EXPECT().Pending().Return(&sync.Pending{Block: &core.Block{}}, nil)
+
+		profile, httpHeader, rpcErr := handler.ProfileTransaction(t.Context(), *hash, "", "")
+		assert.Nil(t, profile)
+		assert.Equal(t, rpccore.ErrTxnHashNotFound, rpcErr)
+		assert.Equal(t, "0", httpHeader.Get(rpc.ExecutionStepsHeader))
+	})
+
+	t.Run("only the transactions up to the profiled one are executed", func(t *testing.T) {
+		first := &core.InvokeTransaction{TransactionHash: utils.HexToFelt(t, "0x1")}
+		second := &core.InvokeTransaction{TransactionHash: utils.HexToFelt(t, "0x2")}
+		header := &core.Header{
+			Hash:       utils.HexToFelt(t, "0xCAFEBABE"),
+			ParentHash: utils.HexToFelt(t, "0x0"),
+		}
+		block := &core.Block{Header: header, Transactions: []core.Transaction{first, second}}
+
+		mockReader.EXPECT().Receipt(first.TransactionHash).Return(nil, header.Hash, header.Number, nil).Times(2)
+		mockReader.EXPECT().BlockByHash(header.Hash).Return(block, nil).Times(2)
+		mockReader.EXPECT().StateAtBlockHash(header.ParentHash).Return(nil, nopCloser, nil).Times(2)
+		mockReader.EXPECT().HeadState().Return(mocks.NewMockStateHistoryReader(mockCtrl), nopCloser, nil).Times(2)
+
+		execute := vm.FunctionInvocation{
+			ContractAddress:    *new(felt.Felt).SetUint64(0xa),
+			EntryPointSelector: new(felt.Felt).SetUint64(0x2),
+			ExecutionResources: &vm.ExecutionResources{ComputationResources: vm.ComputationResources{Steps: 12}},
+		}
+		mockVM.EXPECT().Execute([]core.Transaction{first}, nil, []*felt.Felt{}, &vm.BlockInfo{Header: header},
+			gomock.Any(), &utils.Mainnet, false, false, false, true).Return(vm.ExecutionResults{
+			Traces:   []vm.TransactionTrace{{ExecuteInvocation: &vm.ExecuteInvocation{FunctionInvocation: &execute}}},
+			NumSteps: 12,
+		}, nil).Times(2)
+
+		profile, httpHeader, rpcErr := handler.ProfileTransaction(t.Context(), *first.TransactionHash, "", "")
+		require.Nil(t, rpcErr)
+		assert.Equal(t, "12", httpHeader.Get(rpc.ExecutionStepsHeader))
+		require.NotNil(t, profile.Profile)
+		assert.Equal(t, uint64(12), profile.Profile.Total.Steps)
+		assert.Equal(t, uint64(1), profile.Profile.Total.Calls)
+
+		profile, _, rpcErr = handler.ProfileTransaction(t.Context(), *first.TransactionHash, rpc.ProfileFolded,
+			profiler.Steps)
+		require.Nil(t, rpcErr)
+		assert.Nil(t, profile.Profile)
+		assert.Equal(t, "execute;0xa:0x2 12\n", profile.Folded)
+	})
+}
+
+func TestProfileFormatUnmarshalText(t *testing.T) {
+	var format rpc.ProfileFormat
+	require.NoError(t, format.UnmarshalText([]byte("pprof")))
+	assert.Equal(t, rpc.ProfilePProf, format)
+	require.Error(t, format.UnmarshalText([]byte("svg")))
+}
@@ -0,0 +1,224 @@
+package rpcv8
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/NethermindEth/juno/adapters/vm2core"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/rpc/rpccore"
+	rpcv6 "github.com/NethermindEth/juno/rpc/v6"
+	"github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/vm"
+)
+
+// SimulatedBlock is a block of transactions simulated on top of the blocks simulated before it.
+type SimulatedBlock struct {
+	Transactions   []BroadcastedTransaction `json:"transactions" validate:"dive"`
+	BlockOverrides *BlockOverrides          `json:"block_overrides,omitempty"`
+}
+
+// BlockOverrides replaces the block information the transactions of a simulated block are executed with.
+// Anything not overridden is inherited from the previous block.
+type BlockOverrides struct {
+	Timestamp        *uint64              `json:"timestamp,omitempty"`
+	SequencerAddress *felt.Felt           `json:"sequencer_address,omitempty"`
+	L1GasPrice       *rpcv6.ResourcePrice `json:"l1_gas_price,omitempty"`
+	L1DataGasPrice   *rpcv6.ResourcePrice `json:"l1_data_gas_price,omitempty"`
+	L2GasPrice       *rpcv6.ResourcePrice `json:"l2_gas_price,omitempty"`
+}
+
+type SimulatedBlockResult struct {
+	BlockNumber  uint64                 `json:"block_number"`
+	Timestamp    uint64                 `json:"timestamp"`
+	Transactions []SimulatedTransaction `json:"transactions"`
+}
+
+type SimulatedBlockExecutionErrorData struct {
+	BlockIndex uint64 `json:"block_index"`
+	TransactionExecutionErrorData
+}
+
+// SimulateBlocks simulates consecutive blocks on top of the given one, each block seeing the state changes of
+// the blocks before it. The first block is simulated with the information of the given block, and every
+// following block with the number after the previous one. Where starknet_simulateTransactions simulates the
+// transactions of a single block, this chains several blocks, each with its own block information.
+func (h *Handler) SimulateBlocks(ctx context.Context, id BlockID, blocks []SimulatedBlock, //nolint:gocyclo
+	simulationFlags []SimulationFlag, stateOverride *StateOverride,
+) ([]SimulatedBlockResult, http.Header, *jsonrpc.Error) {
+	skipFeeCharge := slices.Contains(simulationFlags, SkipFeeChargeFlag)
+	skipValidate := slices.Contains(simulationFlags, SkipValidateFlag)
+	returnReadSet := slices.Contains(simulationFlags, ReturnReadSetFlag)
+
+	httpHeader := http.Header{}
+	httpHeader.Set(ExecutionStepsHeader, "0")
+
+	if len(blocks) > rpccore.MaxSimulatedBlocks {
+		return nil, httpHeader, jsonrpc.Err(jsonrpc.InvalidParams,
+			fmt.Sprintf("cannot simulate more than %d blocks at once", rpccore.MaxSimulatedBlocks))
+	}
+
+	state, closer, rpcErr := h.stateByBlockID(&id)
+	if rpcErr != nil {
+		return nil, httpHeader, rpcErr
+	}
+	defer h.callAndLogErr(closer, "Failed to close state in juno_simulateBlocks")
+
+	state, rpcErr = applyStateOverride(state, stateOverride)
+	if rpcErr != nil {
+		return nil, httpHeader, rpcErr
+	}
+
+	baseHeader, rpcErr := h.blockHeaderByID(&id)
+	if rpcErr != nil {
+		return nil, httpHeader, rpcErr
+	}
+
+	var (
+		network    = h.bcReader.Network()
+		diff       = core.EmptyStateDiff()
+		newClasses = make(map[felt.Felt]core.Class)
+		header     = baseHeader
+		numSteps   uint64
+		results    = make([]SimulatedBlockResult, len(blocks))
+	)
+	for blockIdx := range blocks {
+		block := &blocks[blockIdx]
+		if blockIdx > 0 {
+			next := *header
+			next.Number++
+			header = &next
+		}
+
+		previousTimestamp := header.Timestamp
+		header = block.BlockOverrides.apply(header)
+		if header.Timestamp < previousTimestamp {
+			return nil, httpHeader, jsonrpc.Err(jsonrpc.InvalidParams,
+				fmt.Sprintf("timestamp of simulated block %d is before the one of the previous block", blockIdx))
+		}
+
+		txns, classes, paidFeesOnL1, rpcErr := prepareTransactions(block.Transactions, network)
+		if rpcErr != nil {
+			return nil, httpHeader, rpcErr
+		}
+
+		// the hashes of simulated blocks are not known
+		var blockHashToBeRevealed *felt.Felt
+		if header.Number <= baseHeader.Number+core.BlockHashLag {
+			var err error
+			if blockHashToBeRevealed, err = h.getRevealedBlockHash(header.Number); err != nil {
+				return nil, httpHeader, rpccore.ErrInternal.CloneWithData(err)
+			}
+		}
+		blockInfo := vm.BlockInfo{
+			Header:                header,
+			BlockHashToBeRevealed: blockHashToBeRevealed,
+		}
+
+		var (
+			blockState       = sync.NewPendingState(&diff, newClasses, state)
+			executionResults vm.ExecutionResults
+			readSets         []*vm.ReadSet
+			err              error
+		)
+		if returnReadSet {
+			executionResults, readSets, err = executeRecordingReads(vm.WithContext(ctx, h.vm), txns, classes, paidFeesOnL1,
+				&blockInfo, blockState, network, skipFeeCharge, skipValidate, false)
+		} else {
+			executionResults, err = vm.WithContext(ctx, h.vm).Execute(txns, classes, paidFeesOnL1, &blockInfo,
+				blockState, network, skipFeeCharge, skipValidate, false, true)
+		}
+		if err != nil {
+			var txnExecutionError vm.TransactionExecutionError
+			if errors.As(err, &txnExecutionError) {
+				return nil, httpHeader, rpccore.ErrTransactionExecutionError.CloneWithData(SimulatedBlockExecutionErrorData{
+					BlockIndex: uint64(blockIdx),
+					TransactionExecutionErrorData: TransactionExecutionErrorData{
+						TransactionIndex: txnExecutionError.Index,
+						ExecutionError:   txnExecutionError.Cause,
+					},
+				})
+			}
+			return nil, httpHeader, handleExecutionError(err)
+		}
+		numSteps += executionResults.NumSteps
+
+		simulatedTransactions, err := createSimulatedTransactions(&executionResults, txns, header)
+		if err != nil {
+			return nil, httpHeader, rpccore.ErrInternal.CloneWithData(err)
+		}
+		for idx := range readSets {
+			simulatedTransactions[idx].ReadSet = readSets[idx]
+		}
+		results[blockIdx] = SimulatedBlockResult{
+			BlockNumber:  header.Number,
+			Timestamp:    header.Timestamp,
+			Transactions: simulatedTransactions,
+		}
+
+		for _, class := range classes {
+			classHash, err := class.Hash()
+			if err != nil {
+				return nil, httpHeader, rpccore.ErrInternal.CloneWithData(err)
+			}
+			newClasses[*classHash] = class
+		}
+		for idx := range executionResults.Traces {
+			txnDiff := vm2core.AdaptStateDiff(executionResults.Traces[idx].StateDiff)
+			diff.Merge(&txnDiff)
+		}
+	}
+
+	httpHeader.Set(ExecutionStepsHeader, strconv.FormatUint(numSteps, 10))
+	return results, httpHeader, nil
+}
+
+// apply returns a copy of header with the overrides applied, header itself is not modified.
+func (o *BlockOverrides) apply(header *core.Header) *core.Header {
+	if o == nil {
+		return header
+	}
+
+	overridden := *header
+	if o.Timestamp != nil {
+		overridden.Timestamp = *o.Timestamp
+	}
+	if o.SequencerAddress != nil {
+		overridden.SequencerAddress = o.SequencerAddress
+	}
+	if o.L1GasPrice != nil {
+		if o.L1GasPrice.InWei != nil {
+			overridden.L1GasPriceETH = o.L1GasPrice.InWei
+		}
+		if o.L1GasPrice.InFri != nil {
+			overridden.L1GasPriceSTRK = o.L1GasPrice.InFri
+		}
+	}
+	overridden.L1DataGasPrice = overrideGasPrice(header.L1DataGasPrice, o.L1DataGasPrice)
+	overridden.L2GasPrice = overrideGasPrice(header.L2GasPrice, o.L2GasPrice)
+	return &overridden
+}
+
+func overrideGasPrice(gasPrice *core.GasPrice, override *rpcv6.ResourcePrice) *core.GasPrice {
+	if override == nil {
+		return gasPrice
+	}
+
+	overridden := core.GasPrice{PriceInWei: &felt.Zero, PriceInFri: &felt.Zero}
+	if gasPrice != nil {
+		overridden = *gasPrice
+	}
+	if override.InWei != nil {
+		overridden.PriceInWei = override.InWei
+	}
+	if override.InFri != nil {
+		overridden.PriceInFri = override.InFri
+	}
+	return &overridden
+}
@@ -0,0 +1,173 @@
+package rpcv8_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/mocks"
+	"github.com/NethermindEth/juno/rpc/rpccore"
+	rpcv6 "github.com/NethermindEth/juno/rpc/v6"
+	rpc "github.com/NethermindEth/juno/rpc/v8"
+	"github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSimulateBlocks(t *testing.T) {
+	n := &utils.Mainnet
+	baseHeader := &core.Header{
+		Number:         1,
+		Timestamp:      100,
+		L1GasPriceETH:  &felt.One,
+		L1GasPriceSTRK: &felt.One,
+	}
+	emptyResults := vm.ExecutionResults{
+		OverallFees:      []*felt.Felt{},
+		DataAvailability: []core.DataAvailability{},
+		GasConsumed:      []core.GasConsumed{},
+		Traces:           []vm.TransactionTrace{},
+		NumSteps:         10,
+	}
+
+	setup := func(t *testing.T) (*rpc.Handler, *mocks.MockVM) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockReader := mocks.NewMockReader(mockCtrl)
+		mockVM := mocks.NewMockVM(mockCtrl)
+		mockState := mocks.NewMockStateHistoryReader(mockCtrl)
+		mockReader.EXPECT().HeadState().Return(mockState, nopCloser, nil).AnyTimes()
+		mockReader.EXPECT().HeadsHeader().Return(baseHeader, nil).AnyTimes()
+		mockReader.EXPECT().Network().Return(n).AnyTimes()
+		return rpc.New(mockReader, nil, mockVM, "", utils.NewNopZapLogger()), mockVM
+	}
+
+	t.Run("too many blocks", func(t *testing.T) {
+		handler, _ := setup(t)
+		blocks := make([]rpc.SimulatedBlock, rpccore.MaxSimulatedBlocks+1)
+		res, _, rpcErr := handler.SimulateBlocks(t.Context(), rpc.BlockID{Latest: true}, blocks, nil, nil)
+		require.Nil(t, res)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+	})
+
+	t.Run("block information is carried over and overridden", func(t *testing.T) {
+		handler, mockVM := setup(t)
+
+		timestamp := uint64(200)
+		l2GasPrice := new(felt.Felt).SetUint64(7)
+		firstHeader := *baseHeader
+		firstHeader.Timestamp = timestamp
+		secondHeader := firstHeader
+		secondHeader.Number++
+		secondHeader.L2GasPrice = &core.GasPrice{PriceInWei: &felt.Zero, PriceInFri: l2GasPrice}
+
+		gomock.InOrder(
+			mockVM.EXPECT().Execute([]core.Transaction{}, nil, []*felt.Felt{}, &vm.BlockInfo{Header: &firstHeader},
+				gomock.Any(), n, false, false, false, true).Return(emptyResults, nil),
+			mockVM.EXPECT().Execute([]core.Transaction{}, nil, []*felt.Felt{}, &vm.BlockInfo{Header: &secondHeader},
+				gomock.Any(), n, false, false, false, true).Return(emptyResults, nil),
+		)
+
+		res, httpHeader, rpcErr := handler.SimulateBlocks(t.Context(), rpc.BlockID{Latest: true}, []rpc.SimulatedBlock{
+			{BlockOverrides: &rpc.BlockOverrides{Timestamp: &timestamp}},
+			{BlockOverrides: &rpc.BlockOverrides{L2GasPrice: &rpcv6.ResourcePrice{InFri: l2GasPrice}}},
+		}, nil, nil)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, "20", httpHeader.Get(rpc.ExecutionStepsHeader))
+		assert.Equal(t, []rpc.SimulatedBlockResult{
+			{BlockNumber: 1, Timestamp: timestamp, Transactions: []rpc.SimulatedTransaction{}},
+			{BlockNumber: 2, Timestamp: timestamp, Transactions: []rpc.SimulatedTransaction{}},
+		}, res)
+	})
+
+	t.Run("later blocks see the Cairo 0 classes declared before them", func(t *testing.T) {
+		handler, mockVM := setup(t)
+
+		classHash := utils.HexToFelt(t, "0xc1a55")
+		invokeTxn := rpc.BroadcastedTransaction{
+			Transaction: rpc.Transaction{
+				Type:          rpc.TxnInvoke,
+				Version:       &felt.One,
+				Nonce:         &felt.Zero,
+				MaxFee:        &felt.One,
+				SenderAddress: utils.HexToFelt(t, "0x2"),
+				Signature:     &[]*felt.Felt{},
+				CallData:      &[]*felt.Felt{},
+			},
+		}
+		declareResults := vm.ExecutionResults{
+			OverallFees:      []*felt.Felt{&felt.One},
+			DataAvailability: []core.DataAvailability{{}},
+			GasConsumed:      []core.GasConsumed{{}},
+			Traces: []vm.TransactionTrace{{
+				Type:      vm.TxnInvoke,
+				StateDiff: &vm.StateDiff{DeprecatedDeclaredClasses: []*felt.Felt{classHash}},
+			}},
+		}
+
+		gomock.InOrder(
+			mockVM.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(declareResults, nil),
+			mockVM.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ []core.Transaction, _ []core.Class, _ []*felt.Felt, _ *vm.BlockInfo, state core.StateReader,
+					_ *utils.Network, _, _, _, _ bool,
+				) (vm.ExecutionResults, error) {
+					pendingState, ok := state.(*sync.PendingState)
+					require.True(t, ok)
+					assert.Equal(t, []*felt.Felt{classHash}, pendingState.StateDiff().DeclaredV0Classes)
+					return emptyResults, nil
+				}),
+		)
+
+		_, _, rpcErr := handler.SimulateBlocks(t.Context(), rpc.BlockID{Latest: true}, []rpc.SimulatedBlock{
+			{Transactions: []rpc.BroadcastedTransaction{invokeTxn}},
+			{},
+		}, nil, nil)
+		require.Nil(t, rpcErr)
+	})
+
+	t.Run("timestamp going backwards", func(t *testing.T) {
+		handler, _ := setup(t)
+
+		timestamp := baseHeader.Timestamp - 1
+		res, _, rpcErr := handler.SimulateBlocks(t.Context(), rpc.BlockID{Latest: true}, []rpc.SimulatedBlock{
+			{BlockOverrides: &rpc.BlockOverrides{Timestamp: &timestamp}},
+		}, nil, nil)
+		require.Nil(t, res)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+	})
+
+	t.Run("execution error", func(t *testing.T) {
+		handler, mockVM := setup(t)
+
+		gomock.InOrder(
+			mockVM.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(emptyResults, nil),
+			mockVM.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(vm.ExecutionResults{}, vm.TransactionExecutionError{
+				Index: 3,
+				Cause: json.RawMessage(`"oops"`),
+			}),
+		)
+
+		res, _, rpcErr := handler.SimulateBlocks(t.Context(), rpc.BlockID{Latest: true},
+			make([]rpc.SimulatedBlock, 2), nil, nil)
+		require.Nil(t, res)
+		assert.Equal(t, rpccore.ErrTransactionExecutionError.CloneWithData(rpc.SimulatedBlockExecutionErrorData{
+			BlockIndex: 1,
+			TransactionExecutionErrorData: rpc.TransactionExecutionErrorData{
+				TransactionIndex: 3,
+				ExecutionError:   json.RawMessage(`"oops"`),
+			},
+		}), rpcErr)
+	})
+}
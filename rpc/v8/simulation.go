@@ -1,6 +1,7 @@
 package rpcv8
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,20 +9,43 @@ import (
 	"slices"
 	"strconv"
 
+	"github.com/NethermindEth/juno/adapters/vm2core"
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/jsonrpc"
 	"github.com/NethermindEth/juno/rpc/rpccore"
 	rpcv6 "github.com/NethermindEth/juno/rpc/v6"
+	"github.com/NethermindEth/juno/sync"
 	"github.com/NethermindEth/juno/utils"
 	"github.com/NethermindEth/juno/vm"
 )
 
 const ExecutionStepsHeader string = "X-Cairo-Steps"
 
+// SimulationFlag is a flag of starknet_simulateTransactions and juno_simulateBlocks. Besides the flags of the
+// spec, it can be JUNO_RETURN_READ_SET, a Juno extension reporting the state read by each simulated transaction.
+// Other methods taking simulation flags reject it.
+type SimulationFlag rpcv6.SimulationFlag
+
+const (
+	SkipValidateFlag  = SimulationFlag(rpcv6.SkipValidateFlag)
+	SkipFeeChargeFlag = SimulationFlag(rpcv6.SkipFeeChargeFlag)
+	ReturnReadSetFlag = SimulationFlag(rpcv6.SkipFeeChargeFlag + 1)
+)
+
+func (s *SimulationFlag) UnmarshalJSON(bytes []byte) error {
+	if string(bytes) == `"JUNO_RETURN_READ_SET"` {
+		*s = ReturnReadSetFlag
+		return nil
+	}
+	return (*rpcv6.SimulationFlag)(s).UnmarshalJSON(bytes)
+}
+
 type SimulatedTransaction struct {
 	TransactionTrace *TransactionTrace `json:"transaction_trace,omitempty"`
 	FeeEstimation    FeeEstimate       `json:"fee_estimation,omitempty"`
+	// ReadSet is only set if requested with the JUNO_RETURN_READ_SET flag.
+	ReadSet *vm.ReadSet `json:"juno_read_set,omitempty"`
 }
 
 type TracedBlockTransaction struct {
@@ -33,17 +57,18 @@ type TracedBlockTransaction struct {
 		Simulate Handlers
 *****************************************************/
 
-func (h *Handler) SimulateTransactions(id BlockID, transactions []BroadcastedTransaction,
-	simulationFlags []rpcv6.SimulationFlag,
+func (h *Handler) SimulateTransactions(ctx context.Context, id BlockID, transactions []BroadcastedTransaction,
+	simulationFlags []SimulationFlag, stateOverride *StateOverride,
 ) ([]SimulatedTransaction, http.Header, *jsonrpc.Error) {
-	return h.simulateTransactions(id, transactions, simulationFlags, false)
+	return h.simulateTransactions(ctx, id, transactions, simulationFlags, stateOverride, false)
 }
 
-func (h *Handler) simulateTransactions(id BlockID, transactions []BroadcastedTransaction,
-	simulationFlags []rpcv6.SimulationFlag, errOnRevert bool,
+func (h *Handler) simulateTransactions(ctx context.Context, id BlockID, transactions []BroadcastedTransaction,
+	simulationFlags []SimulationFlag, stateOverride *StateOverride, errOnRevert bool,
 ) ([]SimulatedTransaction, http.Header, *jsonrpc.Error) {
-	skipFeeCharge := slices.Contains(simulationFlags, rpcv6.SkipFeeChargeFlag)
-	skipValidate := slices.Contains(simulationFlags, rpcv6.SkipValidateFlag)
+	skipFeeCharge := slices.Contains(simulationFlags, SkipFeeChargeFlag)
+	skipValidate := slices.Contains(simulationFlags, SkipValidateFlag)
+	returnReadSet := slices.Contains(simulationFlags, ReturnReadSetFlag)
 
 	httpHeader := http.Header{}
 	httpHeader.Set(ExecutionStepsHeader, "0")
@@ -54,6 +79,11 @@ func (h *Handler) simulateTransactions(id BlockID, transactions []BroadcastedTra
 	}
 	defer h.callAndLogErr(closer, "Failed to close state in starknet_estimateFee")
 
+	state, rpcErr = applyStateOverride(state, stateOverride)
+	if rpcErr != nil {
+		return nil, httpHeader, rpcErr
+	}
+
 	header, rpcErr := h.blockHeaderByID(&id)
 	if rpcErr != nil {
 		return nil, httpHeader, rpcErr
@@ -74,8 +104,17 @@ func (h *Handler) simulateTransactions(id BlockID, transactions []BroadcastedTra
 		BlockHashToBeRevealed: blockHashToBeRevealed,
 	}
 
-	executionResults, err := h.vm.Execute(txns, classes, paidFeesOnL1, &blockInfo,
-		state, network, skipFeeCharge, skipValidate, errOnRevert, true)
+	var (
+		executionResults vm.ExecutionResults
+		readSets         []*vm.ReadSet
+	)
+	if returnReadSet {
+		executionResults, readSets, err = executeRecordingReads(vm.WithContext(ctx, h.vm), txns, classes, paidFeesOnL1,
+			&blockInfo, state, network, skipFeeCharge, skipValidate, errOnRevert)
+	} else {
+		executionResults, err = vm.WithContext(ctx, h.vm).Execute(txns, classes, paidFeesOnL1, &blockInfo,
+			state, network, skipFeeCharge, skipValidate, errOnRevert, true)
+	}
 	if err != nil {
 		return nil, httpHeader, handleExecutionError(err)
 	}
@@ -86,10 +125,74 @@ func (h *Handler) simulateTransactions(id BlockID, transactions []BroadcastedTra
 	if err != nil {
 		return nil, httpHeader, rpccore.ErrInternal.CloneWithData(err)
 	}
+	for idx := range readSets {
+		simulatedTransactions[idx].ReadSet = readSets[idx]
+	}
 
 	return simulatedTransactions, httpHeader, nil
 }
 
+// executeRecordingReads executes the transactions one at a time, each on top of the state changes of
+// the previous ones, and records the state read by each of them. Executing them in a single batch
+// would not record the reads of a transaction that are served from the VM's cache of earlier reads.
+func executeRecordingReads(virtualMachine vm.VM, txns []core.Transaction, classes []core.Class,
+	paidFeesOnL1 []*felt.Felt, blockInfo *vm.BlockInfo, state core.StateReader, network *utils.Network,
+	skipFeeCharge, skipValidate, errOnRevert bool,
+) (vm.ExecutionResults, []*vm.ReadSet, error) {
+	var (
+		results    vm.ExecutionResults
+		readSets   = make([]*vm.ReadSet, 0, len(txns))
+		diff       = core.EmptyStateDiff()
+		newClasses = make(map[felt.Felt]core.Class)
+	)
+
+	for idx, txn := range txns {
+		// Declared classes and paid fees are given in the order of the transactions they belong to.
+		var txnClasses []core.Class
+		if _, ok := txn.(*core.DeclareTransaction); ok {
+			txnClasses, classes = classes[:1], classes[1:]
+		}
+		var txnPaidFees []*felt.Felt
+		if _, ok := txn.(*core.L1HandlerTransaction); ok {
+			txnPaidFees, paidFeesOnL1 = paidFeesOnL1[:1], paidFeesOnL1[1:]
+		}
+
+		recorder := vm.NewReadRecorder(sync.NewPendingState(&diff, newClasses, state))
+		txnResults, err := virtualMachine.Execute([]core.Transaction{txn}, txnClasses, txnPaidFees, blockInfo,
+			recorder, network, skipFeeCharge, skipValidate, errOnRevert, true)
+		if err != nil {
+			var txnExecutionError vm.TransactionExecutionError
+			if errors.As(err, &txnExecutionError) {
+				txnExecutionError.Index = uint64(idx)
+				return results, nil, txnExecutionError
+			}
+			return results, nil, err
+		}
+		if len(txnResults.Traces) != 1 {
+			return results, nil, fmt.Errorf("expected a single trace, got %d", len(txnResults.Traces))
+		}
+
+		results.OverallFees = append(results.OverallFees, txnResults.OverallFees...)
+		results.DataAvailability = append(results.DataAvailability, txnResults.DataAvailability...)
+		results.GasConsumed = append(results.GasConsumed, txnResults.GasConsumed...)
+		results.Traces = append(results.Traces, txnResults.Traces...)
+		results.Receipts = append(results.Receipts, txnResults.Receipts...)
+		results.NumSteps += txnResults.NumSteps
+		readSets = append(readSets, recorder.ReadSet())
+
+		for _, class := range txnClasses {
+			classHash, err := class.Hash()
+			if err != nil {
+				return results, nil, err
+			}
+			newClasses[*classHash] = class
+		}
+		txnDiff := vm2core.AdaptStateDiff(txnResults.Traces[0].StateDiff)
+		diff.Merge(&txnDiff)
+	}
+	return results, readSets, nil
+}
+
 func prepareTransactions(transactions []BroadcastedTransaction, network *utils.Network) (
 	[]core.Transaction, []core.Class, []*felt.Felt, *jsonrpc.Error,
 ) {
@@ -8,10 +8,12 @@ import (
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/mocks"
 	"github.com/NethermindEth/juno/rpc/rpccore"
 	"github.com/NethermindEth/juno/utils"
 	"github.com/NethermindEth/juno/vm"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 //nolint:dupl
@@ -151,3 +153,69 @@ func TestHandleExecutionError(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteRecordingReads(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockVM := mocks.NewMockVM(mockCtrl)
+	head := mocks.NewMockStateHistoryReader(mockCtrl)
+
+	address := utils.HexToFelt(t, "0x1")
+	key := utils.HexToFelt(t, "0x2")
+	written := utils.HexToFelt(t, "0x3")
+	txns := []core.Transaction{&core.InvokeTransaction{}, &core.InvokeTransaction{}}
+	network := &utils.Mainnet
+	blockInfo := &vm.BlockInfo{Header: &core.Header{}}
+
+	head.EXPECT().ContractStorage(address, key).Return(&felt.Zero, nil)
+	gomock.InOrder(
+		mockVM.EXPECT().Execute(txns[:1], nil, nil, blockInfo, gomock.Any(), network, false, false, false, true).
+			DoAndReturn(func(_ []core.Transaction, _ []core.Class, _ []*felt.Felt, _ *vm.BlockInfo,
+				state core.StateReader, _ *utils.Network, _, _, _, _ bool,
+			) (vm.ExecutionResults, error) {
+				value, err := state.ContractStorage(address, key)
+				require.NoError(t, err)
+				require.Equal(t, &felt.Zero, value)
+				return vm.ExecutionResults{
+					OverallFees:      []*felt.Felt{&felt.One},
+					DataAvailability: []core.DataAvailability{{}},
+					GasConsumed:      []core.GasConsumed{{}},
+					Traces: []vm.TransactionTrace{{StateDiff: &vm.StateDiff{
+						StorageDiffs: []vm.StorageDiff{{
+							Address:        *address,
+							StorageEntries: []vm.Entry{{Key: *key, Value: *written}},
+						}},
+					}}},
+					NumSteps: 1,
+				}, nil
+			}),
+		mockVM.EXPECT().Execute(txns[1:], nil, nil, blockInfo, gomock.Any(), network, false, false, false, true).
+			DoAndReturn(func(_ []core.Transaction, _ []core.Class, _ []*felt.Felt, _ *vm.BlockInfo,
+				state core.StateReader, _ *utils.Network, _, _, _, _ bool,
+			) (vm.ExecutionResults, error) {
+				// the write of the first transaction is visible
+				value, err := state.ContractStorage(address, key)
+				require.NoError(t, err)
+				require.Equal(t, written, value)
+				return vm.ExecutionResults{
+					OverallFees:      []*felt.Felt{&felt.One},
+					DataAvailability: []core.DataAvailability{{}},
+					GasConsumed:      []core.GasConsumed{{}},
+					Traces:           []vm.TransactionTrace{{}},
+					NumSteps:         2,
+				}, nil
+			}),
+	)
+
+	results, readSets, err := executeRecordingReads(mockVM, txns, nil, nil, blockInfo, head, network, false, false, false)
+	require.NoError(t, err)
+	require.Len(t, results.Traces, 2)
+	require.Equal(t, uint64(3), results.NumSteps)
+
+	// both transactions read the slot, even though the second one read a value written by the first one
+	require.Len(t, readSets, 2)
+	for _, readSet := range readSets {
+		require.Equal(t, []vm.StorageKeys{{ContractAddress: *address, Keys: []felt.Felt{*key}}}, readSet.StorageKeys)
+	}
+}
@@ -19,6 +19,16 @@ import (
 	"go.uber.org/mock/gomock"
 )
 
+func TestSimulationFlagUnmarshal(t *testing.T) {
+	var flags []rpc.SimulationFlag
+	require.NoError(t, json.Unmarshal([]byte(`["SKIP_VALIDATE","SKIP_FEE_CHARGE","JUNO_RETURN_READ_SET"]`), &flags))
+	require.Equal(t, []rpc.SimulationFlag{rpc.SkipValidateFlag, rpc.SkipFeeChargeFlag, rpc.ReturnReadSetFlag}, flags)
+
+	// methods that don't support the Juno flags reject them
+	var specFlags []rpcv6.SimulationFlag
+	require.Error(t, json.Unmarshal([]byte(`["JUNO_RETURN_READ_SET"]`), &specFlags))
+}
+
 func TestSimulateTransactions(t *testing.T) {
 	t.Parallel()
 	n := &utils.Mainnet
@@ -46,7 +56,7 @@ func TestSimulateTransactions(t *testing.T) {
 		stepsUsed       uint64
 		err             *jsonrpc.Error
 		mockBehavior    func(*mocks.MockReader, *mocks.MockVM, *mocks.MockStateHistoryReader)
-		simulationFlags []rpcv6.SimulationFlag
+		simulationFlags []rpc.SimulationFlag
 		simulatedTxs    []rpc.SimulatedTransaction
 	}{
 		{ //nolint:dupl
@@ -65,7 +75,7 @@ func TestSimulateTransactions(t *testing.T) {
 						NumSteps:         uint64(123),
 					}, nil)
 			},
-			simulationFlags: []rpcv6.SimulationFlag{rpcv6.SkipFeeChargeFlag},
+			simulationFlags: []rpc.SimulationFlag{rpc.SkipFeeChargeFlag},
 			simulatedTxs:    []rpc.SimulatedTransaction{},
 		},
 		{ //nolint:dupl
@@ -84,7 +94,7 @@ func TestSimulateTransactions(t *testing.T) {
 						NumSteps:         uint64(123),
 					}, nil)
 			},
-			simulationFlags: []rpcv6.SimulationFlag{rpcv6.SkipValidateFlag},
+			simulationFlags: []rpc.SimulationFlag{rpc.SkipValidateFlag},
 			simulatedTxs:    []rpc.SimulatedTransaction{},
 		},
 		{
@@ -99,7 +109,7 @@ func TestSimulateTransactions(t *testing.T) {
 						Cause: json.RawMessage("oops"),
 					})
 			},
-			simulationFlags: []rpcv6.SimulationFlag{rpcv6.SkipValidateFlag},
+			simulationFlags: []rpc.SimulationFlag{rpc.SkipValidateFlag},
 			err: rpccore.ErrTransactionExecutionError.CloneWithData(rpc.TransactionExecutionErrorData{
 				TransactionIndex: 44,
 				ExecutionError:   json.RawMessage("oops"),
@@ -120,7 +130,7 @@ func TestSimulateTransactions(t *testing.T) {
 						NumSteps:         uint64(0),
 					}, nil)
 			},
-			simulationFlags: []rpcv6.SimulationFlag{rpcv6.SkipValidateFlag},
+			simulationFlags: []rpc.SimulationFlag{rpc.SkipValidateFlag},
 			err: rpccore.ErrInternal.CloneWithData(errors.New(
 				"inconsistent lengths: 1 overall fees, 1 traces, 1 gas consumed, 2 data availability, 0 txns",
 			)),
@@ -140,10 +150,11 @@ func TestSimulateTransactions(t *testing.T) {
 			test.mockBehavior(mockReader, mockVM, mockState)
 			handler := rpc.New(mockReader, nil, mockVM, "", utils.NewNopZapLogger())
 
-			simulatedTxs, httpHeader, err := handler.SimulateTransactions(
+			simulatedTxs, httpHeader, err := handler.SimulateTransactions(t.Context(),
 				rpc.BlockID{Latest: true},
 				[]rpc.BroadcastedTransaction{},
 				test.simulationFlags,
+				nil,
 			)
 			if test.err != nil {
 				require.Equal(t, test.err, err)
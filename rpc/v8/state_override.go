@@ -0,0 +1,85 @@
+package rpcv8
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/rpc/rpccore"
+	rpcv6 "github.com/NethermindEth/juno/rpc/v6"
+	"github.com/NethermindEth/juno/sync"
+)
+
+// StateOverride describes hypothetical changes to the state a call or a simulation is executed against.
+// starknet_call, starknet_estimateFee and starknet_simulateTransactions take it as an optional last
+// parameter that the spec does not define, so requests that follow the spec are executed as before.
+type StateOverride struct {
+	Contracts []ContractOverride `json:"contracts,omitempty"`
+	// DeclaredClasses are treated as declared, so that contracts can be overridden to use them.
+	DeclaredClasses []json.RawMessage `json:"declared_classes,omitempty"`
+}
+
+// ContractOverride replaces the class hash, the nonce and storage slots of a contract.
+// Overriding the class hash of an address that has no contract deploys one.
+type ContractOverride struct {
+	Address   felt.Felt     `json:"address"`
+	ClassHash *felt.Felt    `json:"class_hash,omitempty"`
+	Nonce     *felt.Felt    `json:"nonce,omitempty"`
+	Storage   []rpcv6.Entry `json:"storage,omitempty"`
+}
+
+// applyStateOverride layers the override on top of state, in the same way the pending state is
+// layered on top of the head state. A nil override leaves state unchanged.
+func applyStateOverride(state core.StateReader, override *StateOverride) (core.StateReader, *jsonrpc.Error) {
+	if override == nil {
+		return state, nil
+	}
+
+	newClasses := make(map[felt.Felt]core.Class, len(override.DeclaredClasses))
+	for _, declaredClass := range override.DeclaredClasses {
+		class, err := adaptDeclaredClass(declaredClass)
+		if err != nil {
+			return nil, jsonrpc.Err(jsonrpc.InvalidParams, fmt.Sprintf("invalid declared class: %v", err))
+		}
+		classHash, err := class.Hash()
+		if err != nil {
+			return nil, rpccore.ErrInternal.CloneWithData(err)
+		}
+		newClasses[*classHash] = class
+	}
+
+	diff := core.EmptyStateDiff()
+	for idx := range override.Contracts {
+		contract := &override.Contracts[idx]
+
+		if contract.ClassHash != nil {
+			_, err := state.ContractClassHash(&contract.Address)
+			switch {
+			case errors.Is(err, db.ErrKeyNotFound):
+				diff.DeployedContracts[contract.Address] = contract.ClassHash
+			case err != nil:
+				return nil, rpccore.ErrInternal.CloneWithData(err)
+			default:
+				diff.ReplacedClasses[contract.Address] = contract.ClassHash
+			}
+		}
+
+		if contract.Nonce != nil {
+			diff.Nonces[contract.Address] = contract.Nonce
+		}
+
+		if len(contract.Storage) > 0 {
+			storage := make(map[felt.Felt]*felt.Felt, len(contract.Storage))
+			for _, entry := range contract.Storage {
+				storage[entry.Key] = &entry.Value
+			}
+			diff.StorageDiffs[contract.Address] = storage
+		}
+	}
+
+	return sync.NewPendingState(&diff, newClasses, state), nil
+}
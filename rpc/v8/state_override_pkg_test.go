@@ -0,0 +1,92 @@
+package rpcv8
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/mocks"
+	rpcv6 "github.com/NethermindEth/juno/rpc/v6"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestApplyStateOverride(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	deployed := utils.HexToFelt(t, "0x1")
+	undeployed := utils.HexToFelt(t, "0x2")
+	untouched := utils.HexToFelt(t, "0x3")
+	classHash := utils.HexToFelt(t, "0xc1a55")
+	key := utils.HexToFelt(t, "0x10")
+	value := utils.HexToFelt(t, "0x20")
+
+	head := mocks.NewMockStateHistoryReader(mockCtrl)
+
+	t.Run("nil override", func(t *testing.T) {
+		state, rpcErr := applyStateOverride(head, nil)
+		require.Nil(t, rpcErr)
+		assert.Same(t, head, state)
+	})
+
+	t.Run("contracts", func(t *testing.T) {
+		head.EXPECT().ContractClassHash(deployed).Return(&felt.One, nil)
+		head.EXPECT().ContractClassHash(undeployed).Return(nil, db.ErrKeyNotFound)
+
+		state, rpcErr := applyStateOverride(head, &StateOverride{
+			Contracts: []ContractOverride{
+				{
+					Address:   *deployed,
+					ClassHash: classHash,
+					Nonce:     value,
+					Storage:   []rpcv6.Entry{{Key: *key, Value: *value}},
+				},
+				{
+					Address:   *undeployed,
+					ClassHash: classHash,
+				},
+			},
+		})
+		require.Nil(t, rpcErr)
+
+		got, err := state.ContractClassHash(deployed)
+		require.NoError(t, err)
+		assert.Equal(t, classHash, got)
+
+		got, err = state.ContractNonce(deployed)
+		require.NoError(t, err)
+		assert.Equal(t, value, got)
+
+		got, err = state.ContractStorage(deployed, key)
+		require.NoError(t, err)
+		assert.Equal(t, value, got)
+
+		// overridden class hashes deploy missing contracts, with empty storage
+		got, err = state.ContractClassHash(undeployed)
+		require.NoError(t, err)
+		assert.Equal(t, classHash, got)
+
+		got, err = state.ContractStorage(undeployed, key)
+		require.NoError(t, err)
+		assert.Equal(t, &felt.Zero, got)
+
+		// anything else is read from the underlying state
+		head.EXPECT().ContractNonce(untouched).Return(&felt.One, nil)
+		got, err = state.ContractNonce(untouched)
+		require.NoError(t, err)
+		assert.Equal(t, &felt.One, got)
+	})
+
+	t.Run("invalid declared class", func(t *testing.T) {
+		_, rpcErr := applyStateOverride(head, &StateOverride{
+			DeclaredClasses: []json.RawMessage{json.RawMessage(`{}`)},
+		})
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+	})
+}
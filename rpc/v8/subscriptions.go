@@ -369,7 +369,7 @@ func (h *Handler) processEvents(ctx context.Context, w jsonrpc.Conn, id, from, t
 		return err
 	}
 
-	filteredEvents, cToken, err := filter.Events(nil, subscribeEventsChunkSize)
+	filteredEvents, cToken, err := filter.Events(ctx, nil, subscribeEventsChunkSize)
 	if err != nil {
 		h.log.Warnw("Error filtering events", "err", err)
 		return err
@@ -382,7 +382,7 @@ func (h *Handler) processEvents(ctx context.Context, w jsonrpc.Conn, id, from, t
 	}
 
 	for cToken != nil {
-		filteredEvents, cToken, err = filter.Events(cToken, subscribeEventsChunkSize)
+		filteredEvents, cToken, err = filter.Events(ctx, cToken, subscribeEventsChunkSize)
 		if err != nil {
 			h.log.Warnw("Error filtering events", "err", err)
 			return err
@@ -534,6 +534,67 @@ func (h *Handler) onPendingBlock(
 	return nil
 }
 
+// PendingDelta is a change of the pending block sent to the subscribers of pending deltas.
+type PendingDelta struct {
+	BlockNumber uint64                   `json:"block_number"`
+	ParentHash  *felt.Felt               `json:"parent_hash"`
+	Added       []TransactionWithReceipt `json:"added"`
+	Retracted   []*felt.Felt             `json:"retracted"`
+}
+
+// SubscribePendingDeltas creates a WebSocket stream which will fire events when the pending block changes, with
+// the transactions added to it, their receipts and events, and the hashes of the transactions retracted from it
+// without being included in a block. The first event holds all the transactions of the current pending block.
+func (h *Handler) SubscribePendingDeltas(ctx context.Context) (SubscriptionID, *jsonrpc.Error) {
+	w, ok := jsonrpc.ConnFromContext(ctx)
+	if !ok {
+		return 0, jsonrpc.Err(jsonrpc.MethodNotFound, nil)
+	}
+
+	tracker := sync.NewPendingTracker(func(txHash *felt.Felt) bool {
+		_, err := h.bcReader.TransactionByHash(txHash)
+		return err == nil
+	})
+	onPending := func(_ context.Context, id uint64, _ *subscription, pending *core.Block) error {
+		if delta := tracker.Update(pending); delta != nil {
+			return sendPendingDelta(w, delta, id)
+		}
+		return nil
+	}
+
+	subscriber := subscriber{
+		onStart: func(ctx context.Context, id uint64, sub *subscription, _ any) error {
+			if pending := h.syncReader.PendingBlock(); pending != nil {
+				return onPending(ctx, id, sub, pending)
+			}
+			return nil
+		},
+		onPending: onPending,
+	}
+	return h.subscribe(ctx, w, subscriber)
+}
+
+func sendPendingDelta(w jsonrpc.Conn, delta *core.PendingDelta, id uint64) error {
+	added := make([]TransactionWithReceipt, len(delta.Added))
+	for i, pendingTxn := range delta.Added {
+		added[i] = TransactionWithReceipt{
+			Transaction: AdaptTransaction(pendingTxn.Transaction),
+			Receipt:     AdaptReceipt(pendingTxn.Receipt, pendingTxn.Transaction, TxnAcceptedOnL2, nil, 0),
+		}
+	}
+	retracted := delta.Retracted
+	if retracted == nil {
+		retracted = []*felt.Felt{}
+	}
+
+	return sendResponse("juno_subscriptionPendingDelta", w, id, &PendingDelta{
+		BlockNumber: delta.Number,
+		ParentHash:  delta.ParentHash,
+		Added:       added,
+		Retracted:   retracted,
+	})
+}
+
 func toFullTx(txn core.Transaction) any {
 	return AdaptTransaction(txn)
 }
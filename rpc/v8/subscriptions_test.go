@@ -149,14 +149,14 @@ func TestSubscribeEvents(t *testing.T) {
 		mockChain.EXPECT().EventFilter(gomock.Any(), gomock.Any()).Return(mockEventFilterer, nil).AnyTimes()
 		mockChain.EXPECT().BlockByNumber(gomock.Any()).Return(b1, nil).AnyTimes()
 		mockEventFilterer.EXPECT().SetRangeEndBlockByNumber(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return(b1Filtered, nil, nil)
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(b1Filtered, nil, nil)
 		mockEventFilterer.EXPECT().Close().AnyTimes()
 
 		id, clientConn := createTestEventsWebsocket(t, handler, fromAddr, keys, nil)
 
 		assertNextEvents(t, clientConn, id, b1Emitted)
 
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return(b2Filtered, nil, nil)
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(b2Filtered, nil, nil)
 		handler.newHeads.Send(b2)
 		assertNextEvents(t, clientConn, id, b2Emitted)
 	})
@@ -175,7 +175,7 @@ func TestSubscribeEvents(t *testing.T) {
 		mockChain.EXPECT().EventFilter(fromAddr, keys).Return(mockEventFilterer, nil)
 
 		mockEventFilterer.EXPECT().SetRangeEndBlockByNumber(gomock.Any(), gomock.Any()).Return(nil).MaxTimes(2)
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return(b1Filtered, nil, nil)
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(b1Filtered, nil, nil)
 		mockEventFilterer.EXPECT().Close().AnyTimes()
 
 		id, clientConn := createTestEventsWebsocket(t, handler, fromAddr, keys, &b1.Number)
@@ -198,8 +198,8 @@ func TestSubscribeEvents(t *testing.T) {
 
 		cToken := new(blockchain.ContinuationToken)
 		mockEventFilterer.EXPECT().SetRangeEndBlockByNumber(gomock.Any(), gomock.Any()).Return(nil).MaxTimes(2)
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return(b1Filtered, cToken, nil)
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return(b2Filtered, nil, nil)
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(b1Filtered, cToken, nil)
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(b2Filtered, nil, nil)
 		mockEventFilterer.EXPECT().Close().AnyTimes()
 
 		id, clientConn := createTestEventsWebsocket(t, handler, fromAddr, keys, &b1.Number)
@@ -222,21 +222,21 @@ func TestSubscribeEvents(t *testing.T) {
 		mockEventFilterer.EXPECT().Close().AnyTimes()
 
 		mockChain.EXPECT().HeadsHeader().Return(b1.Header, nil)
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return(b1Filtered, nil, nil)
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(b1Filtered, nil, nil)
 
 		id, clientConn := createTestEventsWebsocket(t, handler, fromAddr, keys, nil)
 
 		assertNextEvents(t, clientConn, id, b1Emitted)
 
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return(pending1Filtered, nil, nil)
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(pending1Filtered, nil, nil)
 		handler.pendingBlock.Send(pending1)
 		assertNextEvents(t, clientConn, id, pending1Emitted)
 
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return(pending2Filtered, nil, nil)
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(pending2Filtered, nil, nil)
 		handler.pendingBlock.Send(pending2)
 		assertNextEvents(t, clientConn, id, pending2Emitted[len(pending1Emitted):])
 
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return(b2Filtered, nil, nil)
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(b2Filtered, nil, nil)
 		handler.newHeads.Send(b2)
 		assertNextEvents(t, clientConn, id, b2Emitted[len(pending2Emitted):])
 	})
@@ -617,7 +617,7 @@ func TestSubscriptionReorg(t *testing.T) {
 
 	mockEventFilterer := mocks.NewMockEventFilterer(mockCtrl)
 	mockEventFilterer.EXPECT().SetRangeEndBlockByNumber(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
-	mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return(nil, nil, nil).AnyTimes()
+	mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil, nil).AnyTimes()
 	mockEventFilterer.EXPECT().Close().Return(nil).AnyTimes()
 
 	mockChain.EXPECT().HeadsHeader().Return(&core.Header{}, nil).Times(len(testCases))
@@ -820,6 +820,93 @@ func TestSubscribePendingTxs(t *testing.T) {
 	})
 }
 
+func TestSubscribePendingDeltas(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockChain := mocks.NewMockReader(mockCtrl)
+	l1Feed := feed.New[*core.L1Head]()
+	mockChain.EXPECT().SubscribeL1Head().Return(blockchain.L1HeadSubscription{Subscription: l1Feed.Subscribe()})
+
+	syncer := newFakeSyncer()
+	handler, server := setupRPC(t, ctx, mockChain, syncer)
+
+	conn := createWsConn(t, ctx, server)
+	id := uint64(1)
+	handler.WithIDGen(func() uint64 { return id })
+	got := sendWsMessage(t, ctx, conn, subMsg("juno_subscribePendingDeltas"))
+	require.Equal(t, subResp(id), got)
+
+	hash1 := new(felt.Felt).SetUint64(1)
+	hash2 := new(felt.Felt).SetUint64(2)
+	hash3 := new(felt.Felt).SetUint64(3)
+	pendingBlock := func(number uint64, parentHash *felt.Felt, hashes ...*felt.Felt) *core.Block {
+		block := &core.Block{Header: &core.Header{Number: number, ParentHash: parentHash}}
+		for _, hash := range hashes {
+			block.Transactions = append(block.Transactions, &core.InvokeTransaction{
+				TransactionHash: hash,
+				Version:         new(core.TransactionVersion).SetUint64(1),
+			})
+			block.Receipts = append(block.Receipts, &core.TransactionReceipt{TransactionHash: hash})
+		}
+		return block
+	}
+
+	type delta struct {
+		BlockNumber uint64                   `json:"block_number"`
+		Added       []TransactionWithReceipt `json:"added"`
+		Retracted   []*felt.Felt             `json:"retracted"`
+	}
+	readDelta := func(t *testing.T) (delta, []*felt.Felt) {
+		t.Helper()
+		_, msg, err := conn.Read(ctx)
+		require.NoError(t, err)
+
+		var resp struct {
+			Method string `json:"method"`
+			Params struct {
+				Result         delta  `json:"result"`
+				SubscriptionID uint64 `json:"subscription_id"`
+			} `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(msg, &resp))
+		assert.Equal(t, "juno_subscriptionPendingDelta", resp.Method)
+		assert.Equal(t, id, resp.Params.SubscriptionID)
+
+		added := make([]*felt.Felt, len(resp.Params.Result.Added))
+		for i, txn := range resp.Params.Result.Added {
+			added[i] = txn.Transaction.Hash
+			assert.Equal(t, txn.Transaction.Hash, txn.Receipt.Hash)
+		}
+		return resp.Params.Result, added
+	}
+
+	parentHash := new(felt.Felt).SetUint64(10)
+	syncer.pending.Send(pendingBlock(1, parentHash, hash1, hash2))
+	got1, added := readDelta(t)
+	assert.Equal(t, uint64(1), got1.BlockNumber)
+	assert.Equal(t, []*felt.Felt{hash1, hash2}, added)
+	assert.Empty(t, got1.Retracted)
+
+	// the second transaction is replaced
+	syncer.pending.Send(pendingBlock(1, parentHash, hash1, hash3))
+	got2, added := readDelta(t)
+	assert.Equal(t, []*felt.Felt{hash3}, added)
+	assert.Equal(t, []*felt.Felt{hash2}, got2.Retracted)
+
+	// the first transaction is included in the new head, the third one is dropped
+	mockChain.EXPECT().TransactionByHash(hash1).Return(&core.InvokeTransaction{TransactionHash: hash1}, nil)
+	mockChain.EXPECT().TransactionByHash(hash3).Return(nil, db.ErrKeyNotFound)
+	syncer.pending.Send(pendingBlock(2, new(felt.Felt).SetUint64(11)))
+	got3, added := readDelta(t)
+	assert.Equal(t, uint64(2), got3.BlockNumber)
+	assert.Empty(t, added)
+	assert.Equal(t, []*felt.Felt{hash3}, got3.Retracted)
+}
+
 func TestUnsubscribe(t *testing.T) {
 	log := utils.NewNopZapLogger()
 
@@ -74,45 +74,53 @@ type FunctionInvocation struct {
 // It follows the specification defined here:
 // https://github.com/starkware-libs/starknet-specs/blob/1ae810e0137cc5d175ace4554892a4f43052be56/api/starknet_trace_api_openrpc.json#L11
 func (h *Handler) TraceTransaction(ctx context.Context, hash felt.Felt) (*TransactionTrace, http.Header, *jsonrpc.Error) {
-	_, blockHash, _, err := h.bcReader.Receipt(&hash)
 	httpHeader := http.Header{}
 	httpHeader.Set(ExecutionStepsHeader, "0")
 
+	block, txIndex, rpcErr := h.transactionBlock(&hash)
+	if rpcErr != nil {
+		return nil, httpHeader, rpcErr
+	}
+
+	traceResults, header, traceBlockErr := h.traceBlockTransactions(ctx, block)
+	if traceBlockErr != nil {
+		return nil, header, traceBlockErr
+	}
+
+	return traceResults[txIndex].TraceRoot, header, nil
+}
+
+// transactionBlock returns the block of a transaction, pending or not, and the index of the transaction in it.
+func (h *Handler) transactionBlock(hash *felt.Felt) (*core.Block, int, *jsonrpc.Error) {
+	_, blockHash, _, err := h.bcReader.Receipt(hash)
 	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
-		return nil, httpHeader, rpccore.ErrTxnHashNotFound
+		return nil, 0, rpccore.ErrTxnHashNotFound
 	}
 
 	var block *core.Block
-	isPendingBlock := blockHash == nil
-	if isPendingBlock {
+	if blockHash == nil {
 		var pending *sync.Pending
 		pending, err = h.syncReader.Pending()
 		if err != nil {
 			// for traceTransaction handlers there is no block not found error
-			return nil, httpHeader, rpccore.ErrTxnHashNotFound
+			return nil, 0, rpccore.ErrTxnHashNotFound
 		}
 		block = pending.Block
 	} else {
 		block, err = h.bcReader.BlockByHash(blockHash)
 		if err != nil {
 			// for traceTransaction handlers there is no block not found error
-			return nil, httpHeader, rpccore.ErrTxnHashNotFound
+			return nil, 0, rpccore.ErrTxnHashNotFound
 		}
 	}
 
 	txIndex := slices.IndexFunc(block.Transactions, func(tx core.Transaction) bool {
-		return tx.Hash().Equal(&hash)
+		return tx.Hash().Equal(hash)
 	})
 	if txIndex == -1 {
-		return nil, httpHeader, rpccore.ErrTxnHashNotFound
-	}
-
-	traceResults, header, traceBlockErr := h.traceBlockTransactions(ctx, block)
-	if traceBlockErr != nil {
-		return nil, header, traceBlockErr
+		return nil, 0, rpccore.ErrTxnHashNotFound
 	}
-
-	return traceResults[txIndex].TraceRoot, header, nil
+	return block, txIndex, nil
 }
 
 func (h *Handler) TraceBlockTransactions(ctx context.Context, id BlockID) ([]TracedBlockTransaction, http.Header, *jsonrpc.Error) {
@@ -182,35 +190,71 @@ func (h *Handler) traceBlockTransactions(ctx context.Context, block *core.Block)
 		}
 	}
 
+	executionResult, rpcErr := h.executeBlock(ctx, block, block.Transactions)
+	httpHeader.Set(ExecutionStepsHeader, strconv.FormatUint(executionResult.NumSteps, 10))
+	if rpcErr != nil {
+		return nil, httpHeader, rpcErr
+	}
+
+	result := make([]TracedBlockTransaction, len(executionResult.Traces))
+	// Adapt every vm transaction trace to rpc v8 trace and add root level execution resources
+	for index := range executionResult.Traces {
+		trace := utils.HeapPtr(AdaptVMTransactionTrace(&executionResult.Traces[index]))
+
+		trace.ExecutionResources = &ExecutionResources{
+			InnerExecutionResources: InnerExecutionResources{
+				L1Gas: executionResult.GasConsumed[index].L1Gas,
+				L2Gas: executionResult.GasConsumed[index].L2Gas,
+			},
+			L1DataGas: executionResult.GasConsumed[index].L1DataGas,
+		}
+
+		result[index] = TracedBlockTransaction{
+			TraceRoot:       trace,
+			TransactionHash: block.Transactions[index].Hash(),
+		}
+	}
+
+	if !isPending {
+		h.blockTraceCache.Add(rpccore.TraceCacheKey{
+			BlockHash: *block.Hash,
+		}, result)
+	}
+
+	return result, httpHeader, nil
+}
+
+// executeBlock re-executes txns, the first transactions of block, on top of the state the block was built on.
+func (h *Handler) executeBlock(ctx context.Context, block *core.Block, txns []core.Transaction) (vm.ExecutionResults, *jsonrpc.Error) {
 	state, closer, err := h.bcReader.StateAtBlockHash(block.ParentHash)
 	if err != nil {
-		return nil, httpHeader, rpccore.ErrBlockNotFound
+		return vm.ExecutionResults{}, rpccore.ErrBlockNotFound
 	}
-	defer h.callAndLogErr(closer, "Failed to close state in traceBlockTransactions")
+	defer h.callAndLogErr(closer, "Failed to close state in executeBlock")
 
 	var (
 		headState       core.StateReader
 		headStateCloser blockchain.StateCloser
 	)
-	if isPending {
+	if block.Hash == nil {
 		headState, headStateCloser, err = h.syncReader.PendingState()
 	} else {
 		headState, headStateCloser, err = h.bcReader.HeadState()
 	}
 	if err != nil {
-		return nil, httpHeader, jsonrpc.Err(jsonrpc.InternalError, err.Error())
+		return vm.ExecutionResults{}, jsonrpc.Err(jsonrpc.InternalError, err.Error())
 	}
-	defer h.callAndLogErr(headStateCloser, "Failed to close head state in traceBlockTransactions")
+	defer h.callAndLogErr(headStateCloser, "Failed to close head state in executeBlock")
 
 	var classes []core.Class
 	paidFeesOnL1 := []*felt.Felt{}
 
-	for _, transaction := range block.Transactions {
+	for _, transaction := range txns {
 		switch tx := transaction.(type) {
 		case *core.DeclareTransaction:
 			class, stateErr := headState.Class(tx.ClassHash)
 			if stateErr != nil {
-				return nil, httpHeader, jsonrpc.Err(jsonrpc.InternalError, stateErr.Error())
+				return vm.ExecutionResults{}, jsonrpc.Err(jsonrpc.InternalError, stateErr.Error())
 			}
 			classes = append(classes, class.Class)
 		case *core.L1HandlerTransaction:
@@ -221,7 +265,7 @@ func (h *Handler) traceBlockTransactions(ctx context.Context, block *core.Block)
 
 	blockHashToBeRevealed, err := h.getRevealedBlockHash(block.Number)
 	if err != nil {
-		return nil, httpHeader, rpccore.ErrInternal.CloneWithData(err)
+		return vm.ExecutionResults{}, rpccore.ErrInternal.CloneWithData(err)
 	}
 	network := h.bcReader.Network()
 	header := block.Header
@@ -230,46 +274,17 @@ func (h *Handler) traceBlockTransactions(ctx context.Context, block *core.Block)
 		BlockHashToBeRevealed: blockHashToBeRevealed,
 	}
 
-	executionResult, err := h.vm.Execute(block.Transactions, classes, paidFeesOnL1,
+	executionResult, err := vm.WithContext(ctx, h.vm).Execute(txns, classes, paidFeesOnL1,
 		&blockInfo, state, network, false, false, false, true)
-
-	httpHeader.Set(ExecutionStepsHeader, strconv.FormatUint(executionResult.NumSteps, 10))
-
 	if err != nil {
 		if errors.Is(err, utils.ErrResourceBusy) {
-			return nil, httpHeader, rpccore.ErrInternal.CloneWithData(rpccore.ThrottledVMErr)
+			return executionResult, rpccore.ErrInternal.CloneWithData(rpccore.ThrottledVMErr)
 		}
 		// Since we are tracing an existing block, we know that there should be no errors during execution. If we encounter any,
 		// report them as unexpected errors
-		return nil, httpHeader, rpccore.ErrUnexpectedError.CloneWithData(err.Error())
-	}
-
-	result := make([]TracedBlockTransaction, len(executionResult.Traces))
-	// Adapt every vm transaction trace to rpc v8 trace and add root level execution resources
-	for index := range executionResult.Traces {
-		trace := utils.HeapPtr(AdaptVMTransactionTrace(&executionResult.Traces[index]))
-
-		trace.ExecutionResources = &ExecutionResources{
-			InnerExecutionResources: InnerExecutionResources{
-				L1Gas: executionResult.GasConsumed[index].L1Gas,
-				L2Gas: executionResult.GasConsumed[index].L2Gas,
-			},
-			L1DataGas: executionResult.GasConsumed[index].L1DataGas,
-		}
-
-		result[index] = TracedBlockTransaction{
-			TraceRoot:       trace,
-			TransactionHash: block.Transactions[index].Hash(),
-		}
+		return executionResult, rpccore.ErrUnexpectedError.CloneWithData(err.Error())
 	}
-
-	if !isPending {
-		h.blockTraceCache.Add(rpccore.TraceCacheKey{
-			BlockHash: *block.Hash,
-		}, result)
-	}
-
-	return result, httpHeader, nil
+	return executionResult, nil
 }
 
 func (h *Handler) fetchTraces(ctx context.Context, blockHash *felt.Felt) ([]TracedBlockTransaction, *jsonrpc.Error) {
@@ -298,18 +313,40 @@ func (h *Handler) fetchTraces(ctx context.Context, blockHash *felt.Felt) ([]Trac
 }
 
 // https://github.com/starkware-libs/starknet-specs/blob/e0b76ed0d8d8eba405e182371f9edac8b2bcbc5a/api/starknet_api_openrpc.json#L401-L445
-func (h *Handler) Call(funcCall FunctionCall, id BlockID) ([]*felt.Felt, *jsonrpc.Error) { //nolint:gocritic
+func (h *Handler) Call(ctx context.Context, funcCall FunctionCall, id BlockID, //nolint:gocritic
+	stateOverride *StateOverride,
+) ([]*felt.Felt, *jsonrpc.Error) {
 	state, closer, rpcErr := h.stateByBlockID(&id)
 	if rpcErr != nil {
 		return nil, rpcErr
 	}
 	defer h.callAndLogErr(closer, "Failed to close state in starknet_call")
 
+	state, rpcErr = applyStateOverride(state, stateOverride)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
 	header, rpcErr := h.blockHeaderByID(&id)
 	if rpcErr != nil {
 		return nil, rpcErr
 	}
 
+	blockHashToBeRevealed, err := h.getRevealedBlockHash(header.Number)
+	if err != nil {
+		return nil, rpccore.ErrInternal.CloneWithData(err)
+	}
+
+	return h.call(vm.WithContext(ctx, h.vm), &funcCall, &vm.BlockInfo{
+		Header:                header,
+		BlockHashToBeRevealed: blockHashToBeRevealed,
+	}, state)
+}
+
+// call evaluates funcCall against state on virtualMachine.
+func (h *Handler) call(virtualMachine vm.VM, funcCall *FunctionCall, blockInfo *vm.BlockInfo,
+	state core.StateReader,
+) ([]*felt.Felt, *jsonrpc.Error) {
 	classHash, err := state.ContractClassHash(&funcCall.ContractAddress)
 	if err != nil {
 		return nil, rpccore.ErrContractNotFound
@@ -322,20 +359,12 @@ func (h *Handler) Call(funcCall FunctionCall, id BlockID) ([]*felt.Felt, *jsonrp
 
 	sierraVersion := declaredClass.Class.SierraVersion()
 
-	blockHashToBeRevealed, err := h.getRevealedBlockHash(header.Number)
-	if err != nil {
-		return nil, rpccore.ErrInternal.CloneWithData(err)
-	}
-
-	res, err := h.vm.Call(&vm.CallInfo{
+	res, err := virtualMachine.Call(&vm.CallInfo{
 		ContractAddress: &funcCall.ContractAddress,
 		Selector:        &funcCall.EntryPointSelector,
 		Calldata:        funcCall.Calldata,
 		ClassHash:       classHash,
-	}, &vm.BlockInfo{
-		Header:                header,
-		BlockHashToBeRevealed: blockHashToBeRevealed,
-	}, state, h.bcReader.Network(), h.callMaxSteps, sierraVersion, true, false)
+	}, blockInfo, state, h.bcReader.Network(), h.callMaxSteps, sierraVersion, true, false)
 	if err != nil {
 		if errors.Is(err, utils.ErrResourceBusy) {
 			return nil, rpccore.ErrInternal.CloneWithData(rpccore.ThrottledVMErr)
@@ -72,6 +72,17 @@ func (m *L1DAMode) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (m L1DAMode) MarshalJSON() ([]byte, error) {
+	switch m {
+	case Calldata:
+		return []byte(`"CALLDATA"`), nil
+	case Blob:
+		return []byte(`"BLOB"`), nil
+	default:
+		return nil, errors.New("unknown L1DAMode")
+	}
+}
+
 type GasPrice struct {
 	PriceInWei *felt.Felt `json:"price_in_wei"`
 	PriceInFri *felt.Felt `json:"price_in_fri"`
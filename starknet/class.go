@@ -61,6 +61,13 @@ func (c *ClassDefinition) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, c.V0)
 }
 
+func (c ClassDefinition) MarshalJSON() ([]byte, error) {
+	if c.V1 != nil {
+		return json.Marshal(c.V1)
+	}
+	return json.Marshal(c.V0)
+}
+
 type SegmentLengths struct {
 	Children []SegmentLengths
 	Length   uint64
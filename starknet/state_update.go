@@ -9,30 +9,33 @@ type StateUpdate struct {
 	OldRoot   *felt.Felt `json:"old_root"`
 
 	StateDiff struct {
-		StorageDiffs map[string][]struct {
-			Key   *felt.Felt `json:"key"`
-			Value *felt.Felt `json:"value"`
-		} `json:"storage_diffs"`
-
-		Nonces            map[string]*felt.Felt `json:"nonces"`
-		DeployedContracts []struct {
-			Address   *felt.Felt `json:"address"`
-			ClassHash *felt.Felt `json:"class_hash"`
-		} `json:"deployed_contracts"`
+		StorageDiffs      map[string][]StorageDiff `json:"storage_diffs"`
+		Nonces            map[string]*felt.Felt    `json:"nonces"`
+		DeployedContracts []DeployedContract       `json:"deployed_contracts"`
 
 		// v0.11.0
-		OldDeclaredContracts []*felt.Felt `json:"old_declared_contracts"`
-		DeclaredClasses      []struct {
-			ClassHash         *felt.Felt `json:"class_hash"`
-			CompiledClassHash *felt.Felt `json:"compiled_class_hash"`
-		} `json:"declared_classes"`
-		ReplacedClasses []struct {
-			Address   *felt.Felt `json:"address"`
-			ClassHash *felt.Felt `json:"class_hash"`
-		} `json:"replaced_classes"`
+		OldDeclaredContracts []*felt.Felt       `json:"old_declared_contracts"`
+		DeclaredClasses      []DeclaredClass    `json:"declared_classes"`
+		ReplacedClasses      []DeployedContract `json:"replaced_classes"`
 	} `json:"state_diff"`
 }
 
+type StorageDiff struct {
+	Key   *felt.Felt `json:"key"`
+	Value *felt.Felt `json:"value"`
+}
+
+// DeployedContract is a contract deployed, or whose class was replaced, in a state update
+type DeployedContract struct {
+	Address   *felt.Felt `json:"address"`
+	ClassHash *felt.Felt `json:"class_hash"`
+}
+
+type DeclaredClass struct {
+	ClassHash         *felt.Felt `json:"class_hash"`
+	CompiledClassHash *felt.Felt `json:"compiled_class_hash"`
+}
+
 // StateUpdateWithBlock object returned by the feeder in JSON format for "get_state_update" endpoint with includingBlock arg
 type StateUpdateWithBlock struct {
 	Block       *Block       `json:"block"`
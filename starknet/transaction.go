@@ -30,6 +30,19 @@ func (es *ExecutionStatus) UnmarshalText(data []byte) error {
 	return nil
 }
 
+func (es ExecutionStatus) MarshalText() ([]byte, error) {
+	switch es {
+	case Succeeded:
+		return []byte("SUCCEEDED"), nil
+	case Reverted:
+		return []byte("REVERTED"), nil
+	case Rejected:
+		return []byte("REJECTED"), nil
+	default:
+		return nil, fmt.Errorf("unknown ExecutionStatus %d", es)
+	}
+}
+
 type FinalityStatus uint8
 
 const (
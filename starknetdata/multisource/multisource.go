@@ -0,0 +1,345 @@
+package multisource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/starknetdata"
+	"github.com/NethermindEth/juno/utils"
+)
+
+var _ starknetdata.StarknetData = (*MultiSource)(nil)
+
+const (
+	minCooldown = time.Second
+	maxCooldown = time.Minute
+)
+
+// Source is a StarknetData identified by a name, such as its URL, in logs and metrics.
+type Source struct {
+	Name string
+	Data starknetdata.StarknetData
+}
+
+type source struct {
+	Source
+
+	mu             sync.Mutex
+	failures       uint
+	unhealthyUntil time.Time
+}
+
+func (s *source) healthy(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !now.Before(s.unhealthyUntil)
+}
+
+// record updates the health of the source. Every consecutive failure doubles the time the source is
+// considered unhealthy for, up to maxCooldown.
+func (s *source) record(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil || errors.Is(err, db.ErrKeyNotFound) {
+		s.failures = 0
+		s.unhealthyUntil = time.Time{}
+		return
+	}
+
+	s.failures++
+	cooldown := maxCooldown
+	if s.failures < 8 {
+		cooldown = min(minCooldown<<(s.failures-1), maxCooldown)
+	}
+	s.unhealthyUntil = time.Now().Add(cooldown)
+}
+
+// MultiSource is a StarknetData that fetches data from several sources. Healthy sources are tried first, in
+// the order they were given in, and the next source is tried when one fails.
+//
+// With hedging, the next source is also tried when a source has not answered within the hedge delay, and the
+// first answer is used. With a quorum, state updates with their blocks, which the synchronizer stores, are
+// only returned once enough sources agree on the block hash.
+type MultiSource struct {
+	sources    []*source
+	hedgeDelay time.Duration
+	quorum     int
+	log        utils.SimpleLogger
+	listener   feeder.EventListener
+}
+
+func New(sources []Source, log utils.SimpleLogger) *MultiSource {
+	m := &MultiSource{
+		sources:  make([]*source, len(sources)),
+		quorum:   1,
+		log:      log,
+		listener: &feeder.SelectiveListener{},
+	}
+	for i := range sources {
+		m.sources[i] = &source{Source: sources[i]}
+	}
+	return m
+}
+
+// WithHedgeDelay sets how long a source has to answer before the next one is tried as well. Zero disables
+// hedging.
+func (m *MultiSource) WithHedgeDelay(delay time.Duration) *MultiSource {
+	m.hedgeDelay = delay
+	return m
+}
+
+// WithQuorum sets the number of sources that have to agree on the hash of a block before it is returned by
+// StateUpdateWithBlock. It is capped at the number of sources.
+func (m *MultiSource) WithQuorum(quorum int) *MultiSource {
+	m.quorum = min(max(quorum, 1), len(m.sources))
+	return m
+}
+
+// WithListener registers an EventListener
+func (m *MultiSource) WithListener(listener feeder.EventListener) *MultiSource {
+	m.listener = listener
+	return m
+}
+
+// ordered returns the sources with the healthy ones first, keeping their order otherwise.
+func (m *MultiSource) ordered() []*source {
+	now := time.Now()
+	sources := slices.Clone(m.sources)
+	slices.SortStableFunc(sources, func(a, b *source) int {
+		aHealthy, bHealthy := a.healthy(now), b.healthy(now)
+		switch {
+		case aHealthy == bHealthy:
+			return 0
+		case aHealthy:
+			return -1
+		default:
+			return 1
+		}
+	})
+	return sources
+}
+
+// request calls fn on src and records the outcome, unless the request was cancelled because another source
+// answered first.
+func request[T any](ctx context.Context, m *MultiSource, src *source, method string,
+	fn func(context.Context, starknetdata.StarknetData) (T, error),
+) (T, error) {
+	start := time.Now()
+	value, err := fn(ctx, src.Data)
+	if err != nil && ctx.Err() != nil {
+		return value, err
+	}
+
+	src.record(err)
+	m.listener.OnSourceResponse(src.Name, method, err, time.Since(start))
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		m.log.Debugw("Source failed", "source", src.Name, "method", method, "err", err)
+	}
+	return value, err
+}
+
+type result[T any] struct {
+	value  T
+	err    error
+	source *source
+}
+
+// failover returns the first successful answer of the sources.
+func failover[T any](ctx context.Context, m *MultiSource, method string,
+	fn func(context.Context, starknetdata.StarknetData) (T, error),
+) (T, error) {
+	// The requests still in flight are cancelled and waited for once an answer is returned.
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	sources := m.ordered()
+	results := make(chan result[T], len(sources))
+	next, inFlight := 0, 0
+	launch := func() {
+		src := sources[next]
+		next++
+		inFlight++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := request(ctx, m, src, method, fn)
+			results <- result[T]{value: value, err: err, source: src}
+		}()
+	}
+
+	var errs []error
+	launch()
+	for inFlight > 0 {
+		var hedge <-chan time.Time
+		if m.hedgeDelay > 0 && next < len(sources) {
+			hedge = time.After(m.hedgeDelay)
+		}
+
+		select {
+		case res := <-results:
+			inFlight--
+			if res.err == nil {
+				return res.value, nil
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", res.source.Name, res.err))
+			if next < len(sources) {
+				launch()
+			}
+		case <-hedge:
+			launch()
+		}
+	}
+
+	var zero T
+	return zero, errors.Join(errs...)
+}
+
+// BlockByNumber gets the block for a given block number from the first source to answer.
+func (m *MultiSource) BlockByNumber(ctx context.Context, blockNumber uint64) (*core.Block, error) {
+	return failover(ctx, m, "BlockByNumber", func(ctx context.Context, data starknetdata.StarknetData) (*core.Block, error) {
+		return data.BlockByNumber(ctx, blockNumber)
+	})
+}
+
+// BlockLatest gets the latest block from the first source to answer.
+func (m *MultiSource) BlockLatest(ctx context.Context) (*core.Block, error) {
+	return failover(ctx, m, "BlockLatest", func(ctx context.Context, data starknetdata.StarknetData) (*core.Block, error) {
+		return data.BlockLatest(ctx)
+	})
+}
+
+// BlockPending gets the pending block from the first source to answer.
+func (m *MultiSource) BlockPending(ctx context.Context) (*core.Block, error) {
+	return failover(ctx, m, "BlockPending", func(ctx context.Context, data starknetdata.StarknetData) (*core.Block, error) {
+		return data.BlockPending(ctx)
+	})
+}
+
+// Transaction gets the transaction for a given transaction hash from the first source to answer.
+func (m *MultiSource) Transaction(ctx context.Context, transactionHash *felt.Felt) (core.Transaction, error) {
+	return failover(ctx, m, "Transaction", func(ctx context.Context, data starknetdata.StarknetData) (core.Transaction, error) {
+		return data.Transaction(ctx, transactionHash)
+	})
+}
+
+// Class gets the class for a given class hash from the first source to answer.
+func (m *MultiSource) Class(ctx context.Context, classHash *felt.Felt) (core.Class, error) {
+	return failover(ctx, m, "Class", func(ctx context.Context, data starknetdata.StarknetData) (core.Class, error) {
+		return data.Class(ctx, classHash)
+	})
+}
+
+// StateUpdate gets the state update for a given block number from the first source to answer.
+func (m *MultiSource) StateUpdate(ctx context.Context, blockNumber uint64) (*core.StateUpdate, error) {
+	return failover(ctx, m, "StateUpdate", func(ctx context.Context, data starknetdata.StarknetData) (*core.StateUpdate, error) {
+		return data.StateUpdate(ctx, blockNumber)
+	})
+}
+
+// StateUpdatePending gets the state update for the pending block from the first source to answer.
+func (m *MultiSource) StateUpdatePending(ctx context.Context) (*core.StateUpdate, error) {
+	return failover(ctx, m, "StateUpdatePending", func(ctx context.Context, data starknetdata.StarknetData) (*core.StateUpdate, error) {
+		return data.StateUpdatePending(ctx)
+	})
+}
+
+type stateUpdateWithBlock struct {
+	stateUpdate *core.StateUpdate
+	block       *core.Block
+}
+
+// StateUpdatePendingWithBlock gets both pending state update and pending block from the first source to
+// answer. The pending block has no hash, so it is never subject to the quorum.
+func (m *MultiSource) StateUpdatePendingWithBlock(ctx context.Context) (*core.StateUpdate, *core.Block, error) {
+	res, err := failover(ctx, m, "StateUpdatePendingWithBlock",
+		func(ctx context.Context, data starknetdata.StarknetData) (stateUpdateWithBlock, error) {
+			stateUpdate, block, err := data.StateUpdatePendingWithBlock(ctx)
+			return stateUpdateWithBlock{stateUpdate: stateUpdate, block: block}, err
+		})
+	return res.stateUpdate, res.block, err
+}
+
+// StateUpdateWithBlock gets both state update and block for a given block number from the first source to
+// answer or, with a quorum, once enough sources agree on the block hash.
+func (m *MultiSource) StateUpdateWithBlock(ctx context.Context, blockNumber uint64) (*core.StateUpdate, *core.Block, error) {
+	const method = "StateUpdateWithBlock"
+	fn := func(ctx context.Context, data starknetdata.StarknetData) (stateUpdateWithBlock, error) {
+		stateUpdate, block, err := data.StateUpdateWithBlock(ctx, blockNumber)
+		return stateUpdateWithBlock{stateUpdate: stateUpdate, block: block}, err
+	}
+
+	if m.quorum <= 1 {
+		res, err := failover(ctx, m, method, fn)
+		return res.stateUpdate, res.block, err
+	}
+
+	res, err := m.agree(ctx, method, blockNumber, fn)
+	return res.stateUpdate, res.block, err
+}
+
+// agree asks all the sources for a block and returns it once the quorum agrees on its hash.
+func (m *MultiSource) agree(ctx context.Context, method string, blockNumber uint64,
+	fn func(context.Context, starknetdata.StarknetData) (stateUpdateWithBlock, error),
+) (stateUpdateWithBlock, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	results := make(chan result[stateUpdateWithBlock], len(m.sources))
+	for _, src := range m.sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := request(ctx, m, src, method, fn)
+			results <- result[stateUpdateWithBlock]{value: value, err: err, source: src}
+		}()
+	}
+
+	var errs []error
+	byHash := make(map[felt.Felt][]result[stateUpdateWithBlock])
+	for range m.sources {
+		res := <-results
+		if res.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", res.source.Name, res.err))
+			continue
+		}
+
+		hash := *res.value.block.Hash
+		byHash[hash] = append(byHash[hash], res)
+		if len(byHash[hash]) < m.quorum {
+			continue
+		}
+
+		for otherHash, others := range byHash {
+			if otherHash == hash {
+				continue
+			}
+			for _, other := range others {
+				m.log.Warnw("Source disagrees with the quorum", "source", other.source.Name, "number", blockNumber,
+					"hash", other.value.block.Hash, "quorumHash", &hash)
+				other.source.record(errDisagreement)
+				m.listener.OnSourceDisagreement(other.source.Name)
+			}
+		}
+		return res.value, nil
+	}
+
+	if len(byHash) > 1 {
+		errs = append(errs, fmt.Errorf("sources disagree on the hash of block %d", blockNumber))
+	}
+	return stateUpdateWithBlock{}, fmt.Errorf("no quorum of %d sources for block %d: %w", m.quorum, blockNumber,
+		errors.Join(errs...))
+}
+
+var errDisagreement = errors.New("disagrees with the quorum")
@@ -0,0 +1,140 @@
+package multisource_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/mocks"
+	"github.com/NethermindEth/juno/starknetdata/multisource"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type recordingListener struct {
+	feeder.SelectiveListener
+
+	mu        sync.Mutex
+	responses []string
+}
+
+func newRecordingListener() *recordingListener {
+	l := new(recordingListener)
+	l.OnSourceResponseCb = func(source, method string, err error, _ time.Duration) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		status := "ok"
+		if err != nil {
+			status = "error"
+		}
+		l.responses = append(l.responses, source+" "+method+" "+status)
+	}
+	return l
+}
+
+func blockWithHash(hash uint64) *core.Block {
+	return &core.Block{Header: &core.Header{Hash: new(felt.Felt).SetUint64(hash)}}
+}
+
+func TestFailover(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	first, second := mocks.NewMockStarknetData(mockCtrl), mocks.NewMockStarknetData(mockCtrl)
+	listener := newRecordingListener()
+	multiSource := multisource.New([]multisource.Source{
+		{Name: "first", Data: first},
+		{Name: "second", Data: second},
+	}, utils.NewNopZapLogger()).WithListener(listener)
+
+	block := blockWithHash(1)
+	first.EXPECT().BlockLatest(gomock.Any()).Return(nil, errors.New("throttled"))
+	second.EXPECT().BlockLatest(gomock.Any()).Return(block, nil).Times(2)
+
+	got, err := multiSource.BlockLatest(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, block, got)
+
+	// The first source is unhealthy after failing, so the second one is asked first.
+	got, err = multiSource.BlockLatest(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, block, got)
+	assert.Equal(t, []string{"first BlockLatest error", "second BlockLatest ok", "second BlockLatest ok"},
+		listener.responses)
+
+	t.Run("all sources fail", func(t *testing.T) {
+		first.EXPECT().Class(gomock.Any(), gomock.Any()).Return(nil, errors.New("unavailable"))
+		second.EXPECT().Class(gomock.Any(), gomock.Any()).Return(nil, errors.New("throttled"))
+
+		_, err := multiSource.Class(t.Context(), new(felt.Felt))
+		require.ErrorContains(t, err, "second: throttled")
+		require.ErrorContains(t, err, "first: unavailable")
+	})
+}
+
+func TestHedging(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	slow, fast := mocks.NewMockStarknetData(mockCtrl), mocks.NewMockStarknetData(mockCtrl)
+	multiSource := multisource.New([]multisource.Source{
+		{Name: "slow", Data: slow},
+		{Name: "fast", Data: fast},
+	}, utils.NewNopZapLogger()).WithHedgeDelay(10 * time.Millisecond)
+
+	block := blockWithHash(1)
+	slow.EXPECT().BlockByNumber(gomock.Any(), uint64(1)).DoAndReturn(
+		func(ctx context.Context, _ uint64) (*core.Block, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+	fast.EXPECT().BlockByNumber(gomock.Any(), uint64(1)).Return(block, nil)
+
+	got, err := multiSource.BlockByNumber(t.Context(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, block, got)
+}
+
+func TestQuorum(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	sources := []multisource.Source{
+		{Name: "a", Data: mocks.NewMockStarknetData(mockCtrl)},
+		{Name: "b", Data: mocks.NewMockStarknetData(mockCtrl)},
+		{Name: "c", Data: mocks.NewMockStarknetData(mockCtrl)},
+	}
+	expect := func(idx int, block *core.Block, err error) {
+		sources[idx].Data.(*mocks.MockStarknetData).EXPECT().StateUpdateWithBlock(gomock.Any(), uint64(3)).
+			Return(&core.StateUpdate{}, block, err)
+	}
+	multiSource := multisource.New(sources, utils.NewNopZapLogger()).WithQuorum(2)
+
+	t.Run("quorum agrees", func(t *testing.T) {
+		block := blockWithHash(1)
+		expect(0, block, nil)
+		expect(1, nil, errors.New("unavailable"))
+		expect(2, blockWithHash(1), nil)
+
+		_, got, err := multiSource.StateUpdateWithBlock(t.Context(), 3)
+		require.NoError(t, err)
+		assert.Equal(t, block.Hash, got.Hash)
+	})
+
+	t.Run("no quorum", func(t *testing.T) {
+		expect(0, blockWithHash(1), nil)
+		expect(1, blockWithHash(2), nil)
+		expect(2, nil, errors.New("unavailable"))
+
+		_, _, err := multiSource.StateUpdateWithBlock(t.Context(), 3)
+		require.ErrorContains(t, err, "no quorum of 2 sources for block 3")
+		require.ErrorContains(t, err, "sources disagree on the hash of block 3")
+	})
+}
@@ -0,0 +1,512 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/NethermindEth/juno/adapters/rpc2core"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	rpcv6 "github.com/NethermindEth/juno/rpc/v6"
+	rpcv8 "github.com/NethermindEth/juno/rpc/v8"
+	"github.com/NethermindEth/juno/starknetdata"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var _ starknetdata.StarknetData = (*RPC)(nil)
+
+const (
+	latestID  = "latest"
+	pendingID = "pending"
+)
+
+// Error codes of the upstream that mean that what was asked for does not exist.
+const (
+	blockNotFoundCode     = 24
+	txnHashNotFoundCode   = 29
+	classHashNotFoundCode = 28
+)
+
+// Error is an error returned by the upstream node.
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if len(e.Data) > 0 {
+		return fmt.Sprintf("upstream error %d: %s: %s", e.Code, e.Message, e.Data)
+	}
+	return fmt.Sprintf("upstream error %d: %s", e.Code, e.Message)
+}
+
+// RPC fetches blocks, state updates and classes from a Starknet JSON-RPC 0.8 endpoint, such as another Juno
+// node, instead of the feeder gateway.
+//
+// Blocks fetched over RPC have no signatures, and their receipts only carry the total gas consumed as
+// execution resources since the steps and builtins are not part of the RPC receipts.
+type RPC struct {
+	url        string
+	client     *http.Client
+	userAgent  string
+	backoff    feeder.Backoff
+	maxRetries int
+	maxWait    time.Duration
+	minWait    time.Duration
+	log        utils.SimpleLogger
+	nextID     atomic.Uint64
+}
+
+func New(url string) *RPC {
+	return &RPC{
+		url:        url,
+		client:     http.DefaultClient,
+		backoff:    feeder.ExponentialBackoff,
+		maxRetries: 10,
+		maxWait:    4 * time.Second,
+		minWait:    time.Second,
+		log:        utils.NewNopZapLogger(),
+	}
+}
+
+func (r *RPC) WithTimeout(t time.Duration) *RPC {
+	r.client = &http.Client{Timeout: t}
+	return r
+}
+
+func (r *RPC) WithUserAgent(ua string) *RPC {
+	r.userAgent = ua
+	return r
+}
+
+func (r *RPC) WithLogger(log utils.SimpleLogger) *RPC {
+	r.log = log
+	return r
+}
+
+func (r *RPC) WithBackoff(b feeder.Backoff) *RPC {
+	r.backoff = b
+	return r
+}
+
+func (r *RPC) WithMaxRetries(num int) *RPC {
+	r.maxRetries = num
+	return r
+}
+
+type rpcRequest struct {
+	Version string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+	ID      uint64 `json:"id"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+// call sends a request to the upstream, retrying failed requests with a backoff, and decodes its result into
+// result. Upstream errors meaning that the block, transaction or class does not exist are returned as
+// db.ErrKeyNotFound.
+func (r *RPC) call(ctx context.Context, method string, params, result any) error {
+	var err error
+	wait := time.Duration(0)
+	for range r.maxRetries + 1 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+			if err = r.send(ctx, method, params, result); err == nil {
+				return nil
+			}
+
+			if wait < r.minWait {
+				wait = r.minWait
+			}
+			wait = r.backoff(wait)
+			if wait > r.maxWait {
+				wait = r.maxWait
+			}
+			r.log.Debugw("Failed request to upstream, retrying...", "method", method, "retryAfter", wait.String(),
+				"err", err)
+		}
+	}
+	return err
+}
+
+func (r *RPC) send(ctx context.Context, method string, params, result any) error {
+	body, err := json.Marshal(rpcRequest{
+		Version: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      r.nextID.Add(1),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.userAgent != "" {
+		req.Header.Set("User-Agent", r.userAgent)
+	}
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		resBody, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("%s: unexpected status %s: %s", method, res.Status, resBody)
+	}
+
+	var response rpcResponse
+	if err = json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return fmt.Errorf("%s: decode response: %w", method, err)
+	}
+	if response.Error != nil {
+		switch response.Error.Code {
+		case blockNotFoundCode, txnHashNotFoundCode, classHashNotFoundCode:
+			return db.ErrKeyNotFound
+		default:
+			return fmt.Errorf("%s: %w", method, response.Error)
+		}
+	}
+	if err = json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("%s: decode result: %w", method, err)
+	}
+	return nil
+}
+
+func blockParams(blockID any) map[string]any {
+	return map[string]any{"block_id": blockID}
+}
+
+func numberID(blockNumber uint64) any {
+	return map[string]uint64{"block_number": blockNumber}
+}
+
+// BlockByNumber gets the block for a given block number from the upstream,
+// then adapts it to the core.Block type.
+func (r *RPC) BlockByNumber(ctx context.Context, blockNumber uint64) (*core.Block, error) {
+	return r.block(ctx, numberID(blockNumber))
+}
+
+// BlockLatest gets the latest block from the upstream,
+// then adapts it to the core.Block type.
+func (r *RPC) BlockLatest(ctx context.Context) (*core.Block, error) {
+	return r.block(ctx, latestID)
+}
+
+// BlockPending gets the pending block from the upstream,
+// then adapts it to the core.Block type.
+func (r *RPC) BlockPending(ctx context.Context) (*core.Block, error) {
+	return r.block(ctx, pendingID)
+}
+
+type blockWithReceipts struct {
+	Hash             *felt.Felt          `json:"block_hash"`
+	ParentHash       *felt.Felt          `json:"parent_hash"`
+	Number           uint64              `json:"block_number"`
+	NewRoot          *felt.Felt          `json:"new_root"`
+	Timestamp        uint64              `json:"timestamp"`
+	SequencerAddress *felt.Felt          `json:"sequencer_address"`
+	L1GasPrice       rpcv6.ResourcePrice `json:"l1_gas_price"`
+	L1DataGasPrice   rpcv6.ResourcePrice `json:"l1_data_gas_price"`
+	L2GasPrice       rpcv6.ResourcePrice `json:"l2_gas_price"`
+	L1DAMode         string              `json:"l1_da_mode"`
+	StarknetVersion  string              `json:"starknet_version"`
+	Transactions     []struct {
+		Transaction rpcv8.Transaction `json:"transaction"`
+		Receipt     receipt           `json:"receipt"`
+	} `json:"transactions"`
+}
+
+type receipt struct {
+	Hash      *felt.Felt `json:"transaction_hash"`
+	ActualFee struct {
+		Amount *felt.Felt `json:"amount"`
+		Unit   string     `json:"unit"`
+	} `json:"actual_fee"`
+	ExecutionStatus string `json:"execution_status"`
+	MessagesSent    []struct {
+		From    *felt.Felt   `json:"from_address"`
+		To      *felt.Felt   `json:"to_address"`
+		Payload []*felt.Felt `json:"payload"`
+	} `json:"messages_sent"`
+	Events             []*rpcv8.Event            `json:"events"`
+	RevertReason       string                    `json:"revert_reason"`
+	ExecutionResources *rpcv8.ExecutionResources `json:"execution_resources"`
+}
+
+func (r *RPC) block(ctx context.Context, blockID any) (*core.Block, error) {
+	var response blockWithReceipts
+	if err := r.call(ctx, "starknet_getBlockWithReceipts", blockParams(blockID), &response); err != nil {
+		return nil, err
+	}
+
+	// The pending block has no hash, so a block with one is the latest block returned in its place.
+	if blockID == pendingID && response.Hash != nil {
+		return nil, errors.New("no pending block")
+	}
+
+	return adaptBlock(&response)
+}
+
+func adaptBlock(response *blockWithReceipts) (*core.Block, error) {
+	txns := make([]core.Transaction, len(response.Transactions))
+	receipts := make([]*core.TransactionReceipt, len(response.Transactions))
+	eventCount := uint64(0)
+	for i := range response.Transactions {
+		txn, txnReceipt := &response.Transactions[i].Transaction, &response.Transactions[i].Receipt
+		// Transactions in blocks have no hash in the RPC format, it is part of their receipt.
+		txn.Hash = txnReceipt.Hash
+
+		var err error
+		if txns[i], err = rpc2core.AdaptTransaction(txn); err != nil {
+			return nil, err
+		}
+		if receipts[i], err = adaptReceipt(txnReceipt, txns[i]); err != nil {
+			return nil, err
+		}
+		eventCount += uint64(len(txnReceipt.Events))
+	}
+
+	l1DAMode := core.Calldata
+	if response.L1DAMode == "BLOB" {
+		l1DAMode = core.Blob
+	}
+	return &core.Block{
+		Header: &core.Header{
+			Hash:             response.Hash,
+			ParentHash:       response.ParentHash,
+			Number:           response.Number,
+			GlobalStateRoot:  response.NewRoot,
+			Timestamp:        response.Timestamp,
+			ProtocolVersion:  response.StarknetVersion,
+			SequencerAddress: response.SequencerAddress,
+			TransactionCount: uint64(len(txns)),
+			EventCount:       eventCount,
+			EventsBloom:      core.EventsBloom(receipts),
+			L1GasPriceETH:    response.L1GasPrice.InWei,
+			L1GasPriceSTRK:   response.L1GasPrice.InFri,
+			L1DAMode:         l1DAMode,
+			L1DataGasPrice:   adaptGasPrice(response.L1DataGasPrice),
+			L2GasPrice:       adaptGasPrice(response.L2GasPrice),
+			Signatures:       [][]*felt.Felt{},
+		},
+		Transactions: txns,
+		Receipts:     receipts,
+	}, nil
+}
+
+// adaptGasPrice returns nil for zero prices, which is what Juno returns for blocks from before a price was
+// introduced.
+func adaptGasPrice(price rpcv6.ResourcePrice) *core.GasPrice {
+	if (price.InWei == nil || price.InWei.IsZero()) && (price.InFri == nil || price.InFri.IsZero()) {
+		return nil
+	}
+	return &core.GasPrice{
+		PriceInWei: price.InWei,
+		PriceInFri: price.InFri,
+	}
+}
+
+func adaptReceipt(response *receipt, txn core.Transaction) (*core.TransactionReceipt, error) {
+	var feeUnit core.FeeUnit
+	switch response.ActualFee.Unit {
+	case "WEI":
+		feeUnit = core.WEI
+	case "FRI":
+		feeUnit = core.STRK
+	default:
+		return nil, fmt.Errorf("unknown fee unit %q", response.ActualFee.Unit)
+	}
+
+	events := make([]*core.Event, len(response.Events))
+	for i, event := range response.Events {
+		events[i] = &core.Event{From: event.From, Keys: event.Keys, Data: event.Data}
+	}
+
+	messages := make([]*core.L2ToL1Message, len(response.MessagesSent))
+	for i, msg := range response.MessagesSent {
+		messages[i] = &core.L2ToL1Message{
+			From:    msg.From,
+			To:      common.HexToAddress(msg.To.String()),
+			Payload: msg.Payload,
+		}
+	}
+
+	var resources *core.ExecutionResources
+	if response.ExecutionResources != nil {
+		resources = &core.ExecutionResources{
+			TotalGasConsumed: &core.GasConsumed{
+				L1Gas:     response.ExecutionResources.L1Gas,
+				L1DataGas: response.ExecutionResources.L1DataGas,
+				L2Gas:     response.ExecutionResources.L2Gas,
+			},
+		}
+	}
+
+	return &core.TransactionReceipt{
+		Fee:                response.ActualFee.Amount,
+		FeeUnit:            feeUnit,
+		Events:             events,
+		ExecutionResources: resources,
+		L1ToL2Message:      adaptL1ToL2Message(txn),
+		L2ToL1Message:      messages,
+		TransactionHash:    response.Hash,
+		Reverted:           response.ExecutionStatus == "REVERTED",
+		RevertReason:       response.RevertReason,
+	}, nil
+}
+
+// adaptL1ToL2Message rebuilds the message consumed by an L1 handler transaction, whose first calldata
+// element is the L1 sender and the rest the payload.
+func adaptL1ToL2Message(txn core.Transaction) *core.L1ToL2Message {
+	l1Handler, ok := txn.(*core.L1HandlerTransaction)
+	if !ok || len(l1Handler.CallData) == 0 {
+		return nil
+	}
+	return &core.L1ToL2Message{
+		From:     common.HexToAddress(l1Handler.CallData[0].String()),
+		Nonce:    l1Handler.Nonce,
+		Payload:  l1Handler.CallData[1:],
+		Selector: l1Handler.EntryPointSelector,
+		To:       l1Handler.ContractAddress,
+	}
+}
+
+// Transaction gets the transaction for a given transaction hash from the upstream,
+// then adapts it to the appropriate core.Transaction types.
+func (r *RPC) Transaction(ctx context.Context, transactionHash *felt.Felt) (core.Transaction, error) {
+	var response rpcv8.Transaction
+	if err := r.call(ctx, "starknet_getTransactionByHash", map[string]any{
+		"transaction_hash": transactionHash,
+	}, &response); err != nil {
+		return nil, err
+	}
+	return rpc2core.AdaptTransaction(&response)
+}
+
+// Class gets the class for a given class hash from the upstream,
+// then adapts it to the core.Class type, compiling Sierra classes.
+func (r *RPC) Class(ctx context.Context, classHash *felt.Felt) (core.Class, error) {
+	var definition json.RawMessage
+	if err := r.call(ctx, "starknet_getClass", map[string]any{
+		"class_hash": classHash,
+		"block_id":   latestID,
+	}, &definition); err != nil {
+		return nil, err
+	}
+
+	class, err := rpc2core.AdaptDeclaredClass(definition)
+	if err != nil {
+		return nil, fmt.Errorf("adapt class %s: %w", classHash, err)
+	}
+	return class, nil
+}
+
+// StateUpdate gets the state update for a given block number from the upstream,
+// then adapts it to the core.StateUpdate type.
+func (r *RPC) StateUpdate(ctx context.Context, blockNumber uint64) (*core.StateUpdate, error) {
+	return r.stateUpdate(ctx, numberID(blockNumber))
+}
+
+// StateUpdatePending gets the state update for the pending block from the upstream,
+// then adapts it to the core.StateUpdate type.
+func (r *RPC) StateUpdatePending(ctx context.Context) (*core.StateUpdate, error) {
+	return r.stateUpdate(ctx, pendingID)
+}
+
+func (r *RPC) stateUpdate(ctx context.Context, blockID any) (*core.StateUpdate, error) {
+	var response rpcv6.StateUpdate
+	if err := r.call(ctx, "starknet_getStateUpdate", blockParams(blockID), &response); err != nil {
+		return nil, err
+	}
+	if response.StateDiff == nil {
+		return nil, errors.New("state update without a state diff")
+	}
+
+	return &core.StateUpdate{
+		BlockHash: response.BlockHash,
+		NewRoot:   response.NewRoot,
+		OldRoot:   response.OldRoot,
+		StateDiff: adaptStateDiff(response.StateDiff),
+	}, nil
+}
+
+func adaptStateDiff(response *rpcv6.StateDiff) *core.StateDiff {
+	stateDiff := &core.StateDiff{
+		StorageDiffs:      make(map[felt.Felt]map[felt.Felt]*felt.Felt, len(response.StorageDiffs)),
+		Nonces:            make(map[felt.Felt]*felt.Felt, len(response.Nonces)),
+		DeployedContracts: make(map[felt.Felt]*felt.Felt, len(response.DeployedContracts)),
+		DeclaredV0Classes: response.DeprecatedDeclaredClasses,
+		DeclaredV1Classes: make(map[felt.Felt]*felt.Felt, len(response.DeclaredClasses)),
+		ReplacedClasses:   make(map[felt.Felt]*felt.Felt, len(response.ReplacedClasses)),
+	}
+
+	for _, diff := range response.StorageDiffs {
+		entries := make(map[felt.Felt]*felt.Felt, len(diff.StorageEntries))
+		for _, entry := range diff.StorageEntries {
+			entries[entry.Key] = &entry.Value
+		}
+		stateDiff.StorageDiffs[diff.Address] = entries
+	}
+	for _, nonce := range response.Nonces {
+		stateDiff.Nonces[nonce.ContractAddress] = &nonce.Nonce
+	}
+	for _, deployed := range response.DeployedContracts {
+		stateDiff.DeployedContracts[deployed.Address] = &deployed.ClassHash
+	}
+	for _, declared := range response.DeclaredClasses {
+		stateDiff.DeclaredV1Classes[declared.ClassHash] = &declared.CompiledClassHash
+	}
+	for _, replaced := range response.ReplacedClasses {
+		stateDiff.ReplacedClasses[replaced.ContractAddress] = &replaced.ClassHash
+	}
+	return stateDiff
+}
+
+// StateUpdatePendingWithBlock gets both pending state update and pending block from the upstream,
+// then adapts them to the core.StateUpdate and core.Block types respectively.
+//
+// They are fetched with two requests, so the pending block may have changed in between.
+func (r *RPC) StateUpdatePendingWithBlock(ctx context.Context) (*core.StateUpdate, *core.Block, error) {
+	return r.stateUpdateWithBlock(ctx, pendingID)
+}
+
+// StateUpdateWithBlock gets both state update and block for a given block number from the upstream,
+// then adapts them to the core.StateUpdate and core.Block types respectively
+func (r *RPC) StateUpdateWithBlock(ctx context.Context, blockNumber uint64) (*core.StateUpdate, *core.Block, error) {
+	return r.stateUpdateWithBlock(ctx, numberID(blockNumber))
+}
+
+func (r *RPC) stateUpdateWithBlock(ctx context.Context, blockID any) (*core.StateUpdate, *core.Block, error) {
+	block, err := r.block(ctx, blockID)
+	if err != nil {
+		return nil, nil, err
+	}
+	stateUpdate, err := r.stateUpdate(ctx, blockID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stateUpdate, block, nil
+}
@@ -0,0 +1,139 @@
+package rpc_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/mocks"
+	rpcv6 "github.com/NethermindEth/juno/rpc/v6"
+	rpcv8 "github.com/NethermindEth/juno/rpc/v8"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	adaptrpc "github.com/NethermindEth/juno/starknetdata/rpc"
+	"github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newFakeUpstream serves the block and state update methods with the RPC handlers of Juno, so that what
+// the handlers return for a block is adapted back.
+func newFakeUpstream(t *testing.T, reader *mocks.MockReader, syncReader *mocks.MockSyncReader) *httptest.Server {
+	t.Helper()
+
+	log := utils.NewNopZapLogger()
+	v8Handler := rpcv8.New(reader, syncReader, nil, "", log)
+	v6Handler := rpcv6.New(reader, syncReader, nil, "", &utils.Sepolia, log)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Params struct {
+				BlockID json.RawMessage `json:"block_id"`
+			} `json:"params"`
+			ID uint64 `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var (
+			result any
+			rpcErr *jsonrpc.Error
+		)
+		switch req.Method {
+		case "starknet_getBlockWithReceipts":
+			var id rpcv8.BlockID
+			require.NoError(t, json.Unmarshal(req.Params.BlockID, &id))
+			result, rpcErr = v8Handler.BlockWithReceipts(id)
+		case "starknet_getStateUpdate":
+			var id rpcv6.BlockID
+			require.NoError(t, json.Unmarshal(req.Params.BlockID, &id))
+			result, rpcErr = v6Handler.StateUpdate(id)
+		default:
+			rpcErr = jsonrpc.Err(jsonrpc.MethodNotFound, nil)
+		}
+
+		res := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		if rpcErr != nil {
+			res["error"] = rpcErr
+		} else {
+			res["result"] = result
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStateUpdateWithBlock(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	mockSyncReader := mocks.NewMockSyncReader(mockCtrl)
+	mockReader.EXPECT().L1Head().Return(nil, db.ErrKeyNotFound).AnyTimes()
+	server := newFakeUpstream(t, mockReader, mockSyncReader)
+
+	feederData := adaptfeeder.New(feeder.NewTestClient(t, &utils.Sepolia))
+	rpcData := adaptrpc.New(server.URL).WithMaxRetries(0)
+	ctx := t.Context()
+
+	for _, number := range []uint64{0, 1} {
+		t.Run("sepolia block number "+strconv.FormatUint(number, 10), func(t *testing.T) {
+			expectedUpdate, expectedBlock, err := feederData.StateUpdateWithBlock(ctx, number)
+			require.NoError(t, err)
+			mockReader.EXPECT().BlockByNumber(number).Return(expectedBlock, nil)
+			mockReader.EXPECT().StateUpdateByNumber(number).Return(expectedUpdate, nil)
+
+			stateUpdate, block, err := rpcData.StateUpdateWithBlock(ctx, number)
+			require.NoError(t, err)
+			assert.Equal(t, expectedUpdate, stateUpdate)
+
+			expectedHeader, header := *expectedBlock.Header, *block.Header
+			expectedHeader.Signatures = [][]*felt.Felt{}
+			assert.Equal(t, expectedHeader, header)
+			assert.Equal(t, expectedBlock.Transactions, block.Transactions)
+			require.Len(t, block.Receipts, len(expectedBlock.Receipts))
+			for i, receipt := range block.Receipts {
+				expected := expectedBlock.Receipts[i]
+				assert.Equal(t, expected.TransactionHash, receipt.TransactionHash)
+				assert.Equal(t, expected.Fee, receipt.Fee)
+				assert.Equal(t, expected.Events, receipt.Events)
+				assert.Equal(t, expected.L2ToL1Message, receipt.L2ToL1Message)
+				assert.Equal(t, expected.Reverted, receipt.Reverted)
+			}
+
+			_, err = core.VerifyBlockHash(block, &utils.Sepolia, stateUpdate.StateDiff)
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("block not found", func(t *testing.T) {
+		mockReader.EXPECT().BlockByNumber(uint64(7)).Return(nil, db.ErrKeyNotFound)
+
+		_, err := rpcData.BlockByNumber(ctx, 7)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+
+	t.Run("no pending block", func(t *testing.T) {
+		block, err := feederData.BlockByNumber(ctx, 0)
+		require.NoError(t, err)
+		mockSyncReader.EXPECT().Pending().Return(&sync.Pending{Block: block}, nil)
+
+		_, err = rpcData.BlockPending(ctx)
+		require.EqualError(t, err, "no pending block")
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		_, err := rpcData.Class(ctx, new(felt.Felt))
+		var upstreamErr *adaptrpc.Error
+		require.ErrorAs(t, err, &upstreamErr)
+		assert.Equal(t, jsonrpc.MethodNotFound, upstreamErr.Code)
+	})
+}
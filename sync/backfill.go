@@ -0,0 +1,118 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/service"
+	"github.com/NethermindEth/juno/starknetdata"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/sourcegraph/conc/pool"
+)
+
+const (
+	backfillBatchSize  = 16
+	backfillRetryDelay = 5 * time.Second
+)
+
+var _ service.Service = (*Backfiller)(nil)
+
+// Backfiller downloads the blocks before the start of the history of a chain started from a checkpoint, down
+// to genesis. Blocks are fetched in batches, verified against the parent hash of the block after them and
+// stored without touching the state, so the node serves recent blocks while its history grows.
+type Backfiller struct {
+	blockchain   *blockchain.Blockchain
+	starknetData starknetdata.StarknetData
+	log          utils.SimpleLogger
+	batchSize    uint64
+	retryDelay   time.Duration
+}
+
+func NewBackfiller(bc *blockchain.Blockchain, starknetData starknetdata.StarknetData, log utils.SimpleLogger) *Backfiller {
+	return &Backfiller{
+		blockchain:   bc,
+		starknetData: starknetData,
+		log:          log,
+		batchSize:    backfillBatchSize,
+		retryDelay:   backfillRetryDelay,
+	}
+}
+
+// WithRetryDelay sets how long the backfiller waits after failing to fetch or store a batch.
+func (b *Backfiller) WithRetryDelay(delay time.Duration) *Backfiller {
+	b.retryDelay = delay
+	return b
+}
+
+// Run backfills the history until it reaches genesis, then idles until the context is cancelled, since
+// services returning early stop the node.
+func (b *Backfiller) Run(ctx context.Context) error {
+	for {
+		start, err := b.blockchain.HistoryStart()
+		if err != nil {
+			return err
+		}
+		if start == 0 {
+			b.log.Infow("History is complete")
+			<-ctx.Done()
+			return nil
+		}
+
+		if err = b.backfillBatch(ctx, start); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			b.log.Warnw("Failed to backfill blocks", "before", start, "err", err)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(b.retryDelay):
+			}
+		}
+	}
+}
+
+// backfillBatch fetches and stores the blocks of the batch right before the start of the history.
+func (b *Backfiller) backfillBatch(ctx context.Context, start uint64) error {
+	count := min(b.batchSize, start)
+	blocks := make([]*blockchain.VerifiedBlock, count)
+
+	fetchers := pool.New().WithContext(ctx).WithCancelOnError()
+	for i := range count {
+		number := start - 1 - i
+		fetchers.Go(func(ctx context.Context) error {
+			stateUpdate, block, err := b.starknetData.StateUpdateWithBlock(ctx, number)
+			if err != nil {
+				return fmt.Errorf("fetch block %d: %w", number, err)
+			}
+			if !block.Hash.Equal(stateUpdate.BlockHash) {
+				return fmt.Errorf("block %d: block hashes do not match", number)
+			}
+			commitments, err := core.VerifyBlockHash(block, b.blockchain.Network(), stateUpdate.StateDiff)
+			if err != nil {
+				return fmt.Errorf("block %d: %v", number, err)
+			}
+
+			blocks[i] = &blockchain.VerifiedBlock{
+				Block:       block,
+				Commitments: commitments,
+				StateUpdate: stateUpdate,
+			}
+			return nil
+		})
+	}
+	if err := fetchers.Wait(); err != nil {
+		return err
+	}
+
+	if err := b.blockchain.StoreHistory(blocks); err != nil {
+		return err
+	}
+	last := blocks[len(blocks)-1].Block
+	b.log.Infow("Backfilled blocks", "from", last.Number, "to", start-1, "hash", last.Hash.ShortString())
+	return nil
+}
@@ -0,0 +1,57 @@
+package sync_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/db/pebble"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfiller(t *testing.T) {
+	client := feeder.NewTestClient(t, &utils.Mainnet)
+	gw := adaptfeeder.New(client)
+
+	bc := blockchain.New(pebble.NewMemTest(t), &utils.Mainnet)
+	for number := range uint64(3) {
+		stateUpdate, block, err := gw.StateUpdateWithBlock(t.Context(), number)
+		require.NoError(t, err)
+		require.NoError(t, bc.Store(block, &core.BlockCommitments{}, stateUpdate, nil))
+	}
+	head, err := bc.Head()
+	require.NoError(t, err)
+	require.NoError(t, bc.SetCheckpoint(t.Context(), &blockchain.Checkpoint{Number: head.Number, Hash: head.Hash}))
+
+	_, err = bc.BlockByNumber(0)
+	require.ErrorIs(t, err, blockchain.ErrHistoryUnavailable)
+
+	ctx, cancel := context.WithTimeout(t.Context(), timeout)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- sync.NewBackfiller(bc, gw, utils.NewNopZapLogger()).WithRetryDelay(0).Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		start, err := bc.HistoryStart()
+		return err == nil && start == 0
+	}, timeout, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	for number := range head.Number {
+		expected, err := gw.BlockByNumber(t.Context(), number)
+		require.NoError(t, err)
+		block, err := bc.BlockByNumber(number)
+		require.NoError(t, err)
+		assert.Equal(t, expected, block)
+	}
+}
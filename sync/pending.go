@@ -77,6 +77,13 @@ func (p *PendingState) Class(classHash *felt.Felt) (*core.DeclaredClass, error)
 	return p.head.Class(classHash)
 }
 
+func (p *PendingState) HasClass(classHash *felt.Felt) (bool, error) {
+	if _, found := p.newClasses[*classHash]; found {
+		return true, nil
+	}
+	return core.HasClass(p.head, classHash)
+}
+
 func (p *PendingState) ClassTrie() (*trie.Trie, error) {
 	return nil, core.ErrHistoricalTrieNotSupported
 }
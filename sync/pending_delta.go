@@ -0,0 +1,67 @@
+package sync
+
+import (
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// PendingTracker turns successive pending blocks into deltas. Pending blocks may be skipped: a transaction that
+// left the pending block is retracted, unless the pending block moved to a new parent and the transaction was
+// included in a block in the meantime.
+type PendingTracker struct {
+	included func(txHash *felt.Felt) bool
+	last     *core.Block
+}
+
+// NewPendingTracker creates a tracker that checks whether transactions were included in a block with included.
+func NewPendingTracker(included func(txHash *felt.Felt) bool) *PendingTracker {
+	return &PendingTracker{included: included}
+}
+
+// Update returns the delta from the last pending block to the given one, nil if nothing changed.
+func (t *PendingTracker) Update(pending *core.Block) *core.PendingDelta {
+	last := t.last
+	t.last = pending
+
+	parentChanged := true
+	lastHashes := make(map[felt.Felt]struct{})
+	if last != nil {
+		parentChanged = !last.ParentHash.Equal(pending.ParentHash)
+		for _, txn := range last.Transactions {
+			lastHashes[*txn.Hash()] = struct{}{}
+		}
+	}
+
+	delta := &core.PendingDelta{
+		Number:     pending.Number,
+		ParentHash: pending.ParentHash,
+	}
+	pendingHashes := make(map[felt.Felt]struct{}, len(pending.Transactions))
+	for i, txn := range pending.Transactions {
+		pendingHashes[*txn.Hash()] = struct{}{}
+		if _, ok := lastHashes[*txn.Hash()]; !ok {
+			delta.Added = append(delta.Added, core.PendingTransaction{
+				Transaction: txn,
+				Receipt:     pending.Receipts[i],
+			})
+		}
+	}
+
+	if last != nil {
+		for _, txn := range last.Transactions {
+			if _, ok := pendingHashes[*txn.Hash()]; ok {
+				continue
+			}
+			// a transaction can only have been included in a block if the pending block has a new parent
+			if parentChanged && t.included(txn.Hash()) {
+				continue
+			}
+			delta.Retracted = append(delta.Retracted, txn.Hash())
+		}
+	}
+
+	if !parentChanged && len(delta.Added) == 0 && len(delta.Retracted) == 0 {
+		return nil
+	}
+	return delta
+}
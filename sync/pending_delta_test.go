@@ -0,0 +1,113 @@
+package sync_test
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/sync"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingTracker(t *testing.T) {
+	parentA := new(felt.Felt).SetUint64(0xa)
+	parentB := new(felt.Felt).SetUint64(0xb)
+	txnHashes := make([]*felt.Felt, 4)
+	for i := range txnHashes {
+		txnHashes[i] = new(felt.Felt).SetUint64(uint64(i))
+	}
+
+	pendingBlock := func(number uint64, parentHash *felt.Felt, txns ...int) *core.Block {
+		block := &core.Block{Header: &core.Header{Number: number, ParentHash: parentHash}}
+		for _, i := range txns {
+			block.Transactions = append(block.Transactions, &core.InvokeTransaction{TransactionHash: txnHashes[i]})
+			block.Receipts = append(block.Receipts, &core.TransactionReceipt{TransactionHash: txnHashes[i]})
+		}
+		return block
+	}
+	added := func(block *core.Block, indices ...int) []core.PendingTransaction {
+		var txns []core.PendingTransaction
+		for _, i := range indices {
+			txns = append(txns, core.PendingTransaction{Transaction: block.Transactions[i], Receipt: block.Receipts[i]})
+		}
+		return txns
+	}
+
+	tests := map[string]struct {
+		last     *core.Block
+		pending  *core.Block
+		included []int
+		// expected builds the expected delta from pending, nil means no delta
+		expected func(pending *core.Block) *core.PendingDelta
+	}{
+		"new pending block": {
+			pending: pendingBlock(1, parentA, 0, 1),
+			expected: func(pending *core.Block) *core.PendingDelta {
+				return &core.PendingDelta{Number: 1, ParentHash: parentA, Added: added(pending, 0, 1)}
+			},
+		},
+		"pending block grows": {
+			last:    pendingBlock(1, parentA, 0),
+			pending: pendingBlock(1, parentA, 0, 1),
+			expected: func(pending *core.Block) *core.PendingDelta {
+				return &core.PendingDelta{Number: 1, ParentHash: parentA, Added: added(pending, 1)}
+			},
+		},
+		"pending block unchanged": {
+			last:    pendingBlock(1, parentA, 0),
+			pending: pendingBlock(1, parentA, 0),
+		},
+		"pending block replaced": {
+			last:     pendingBlock(1, parentA, 0, 1),
+			pending:  pendingBlock(1, parentA, 0, 2),
+			included: []int{1},
+			expected: func(pending *core.Block) *core.PendingDelta {
+				return &core.PendingDelta{
+					Number:     1,
+					ParentHash: parentA,
+					Added:      added(pending, 1),
+					Retracted:  []*felt.Felt{txnHashes[1]},
+				}
+			},
+		},
+		"pending block promoted to head": {
+			last:     pendingBlock(1, parentA, 0, 1),
+			pending:  pendingBlock(2, parentB, 2),
+			included: []int{0, 1},
+			expected: func(pending *core.Block) *core.PendingDelta {
+				return &core.PendingDelta{Number: 2, ParentHash: parentB, Added: added(pending, 0)}
+			},
+		},
+		"transaction dropped when the pending block is promoted": {
+			last:     pendingBlock(1, parentA, 0, 1),
+			pending:  pendingBlock(2, parentB),
+			included: []int{0},
+			expected: func(*core.Block) *core.PendingDelta {
+				return &core.PendingDelta{Number: 2, ParentHash: parentB, Retracted: []*felt.Felt{txnHashes[1]}}
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			included := make(map[felt.Felt]struct{}, len(test.included))
+			for _, i := range test.included {
+				included[*txnHashes[i]] = struct{}{}
+			}
+			tracker := sync.NewPendingTracker(func(txHash *felt.Felt) bool {
+				_, ok := included[*txHash]
+				return ok
+			})
+
+			if test.last != nil {
+				tracker.Update(test.last)
+			}
+			delta := tracker.Update(test.pending)
+			if test.expected == nil {
+				assert.Nil(t, delta)
+				return
+			}
+			assert.Equal(t, test.expected(test.pending), delta)
+		})
+	}
+}
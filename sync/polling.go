@@ -0,0 +1,82 @@
+package sync
+
+import (
+	stdsync "sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core"
+)
+
+const (
+	minLatestPollInterval = time.Second
+	maxLatestPollInterval = time.Minute
+	// pendingPollsPerBlock bounds how far polling the pending block backs off while it does not change.
+	pendingPollsPerBlock = 4
+	// blockTimeSmoothing is the weight of the average against a new block time, as in TCP round trip estimation.
+	blockTimeSmoothing = 8
+)
+
+// blockTimeEstimator keeps a moving average of the time between blocks, from the timestamps of consecutive
+// stored blocks.
+type blockTimeEstimator struct {
+	mu      stdsync.Mutex
+	last    *core.Header
+	average time.Duration
+	known   bool
+}
+
+func (e *blockTimeEstimator) observe(header *core.Header) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.last != nil && header.Number == e.last.Number+1 && header.Timestamp >= e.last.Timestamp {
+		blockTime := time.Duration(header.Timestamp-e.last.Timestamp) * time.Second
+		if e.known {
+			e.average += (blockTime - e.average) / blockTimeSmoothing
+		} else {
+			e.average, e.known = blockTime, true
+		}
+	}
+	e.last = header
+}
+
+// estimate returns the average block time, false if not enough blocks were observed yet.
+func (e *blockTimeEstimator) estimate() (time.Duration, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.average, e.known
+}
+
+// latestPollInterval returns the interval until the next latest block poll, the block time when it is known so
+// that the latest block is polled about once per block.
+func (s *Synchronizer) latestPollInterval() time.Duration {
+	blockTime, known := s.blockTimes.estimate()
+	if !known {
+		return maxLatestPollInterval
+	}
+	return min(max(blockTime, minLatestPollInterval), maxLatestPollInterval)
+}
+
+// nextPendingPollInterval returns the interval until the next pending block poll. Polling backs off while the
+// pending block does not change, down to pendingPollsPerBlock polls per block, and is reset once it changes.
+func (s *Synchronizer) nextPendingPollInterval(interval time.Duration, changed bool) time.Duration {
+	if changed {
+		return s.pendingPollInterval
+	}
+
+	maxInterval := s.pendingPollInterval
+	if blockTime, known := s.blockTimes.estimate(); known {
+		maxInterval = max(maxInterval, blockTime/pendingPollsPerBlock)
+	}
+	return min(2*interval, maxInterval)
+}
+
+// triggerPolls polls the latest and the pending block right away, without waiting for their next poll.
+func (s *Synchronizer) triggerPolls() {
+	for _, trigger := range []chan struct{}{s.latestTrigger, s.pendingTrigger} {
+		select {
+		case trigger <- struct{}{}:
+		default:
+		}
+	}
+}
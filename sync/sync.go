@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	stdsync "sync"
 	"sync/atomic"
 	"time"
 
@@ -33,6 +34,16 @@ const (
 	OpFetch  = "fetch"
 )
 
+// Finality is the status a block needs to reach before the synchronizer stores it.
+type Finality string
+
+const (
+	// FinalityL2 stores blocks as soon as they are accepted on L2.
+	FinalityL2 Finality = "l2"
+	// FinalityL1 only stores blocks once the L1 head reaches them, so that stored blocks are never reorged.
+	FinalityL1 Finality = "l1"
+)
+
 // This is a work-around. mockgen chokes when the instantiated generic type is in the interface.
 type NewHeadSubscription struct {
 	*feed.Subscription[*core.Block]
@@ -130,7 +141,16 @@ type Synchronizer struct {
 	pending             atomic.Pointer[Pending]
 	pendingPollInterval time.Duration
 	catchUpMode         bool
+	batchSize           int
+	batch               []*blockchain.VerifiedBlock
+	finality            Finality
 	plugin              junoplugin.JunoPlugin
+	pendingTracker      *PendingTracker
+	pendingTrackerMu    stdsync.Mutex
+	blockTimes          blockTimeEstimator
+	upstreamHeadsURL    string
+	latestTrigger       chan struct{}
+	pendingTrigger      chan struct{}
 
 	currReorg *ReorgBlockRange // If nil, no reorg is happening
 }
@@ -149,7 +169,11 @@ func New(bc *blockchain.Blockchain, starkNetData starknetdata.StarknetData, log
 		pendingPollInterval: pendingPollInterval,
 		listener:            &SelectiveListener{},
 		readOnlyBlockchain:  readOnlyBlockchain,
+		finality:            FinalityL2,
+		latestTrigger:       make(chan struct{}, 1),
+		pendingTrigger:      make(chan struct{}, 1),
 	}
+	s.pendingTracker = NewPendingTracker(s.includedInChain)
 	return s
 }
 
@@ -159,6 +183,30 @@ func (s *Synchronizer) WithPlugin(plugin junoplugin.JunoPlugin) *Synchronizer {
 	return s
 }
 
+// WithCatchUpBatchSize makes up to size blocks share a single database transaction while the synchronizer is
+// catching up with the chain. Each block is still verified and its state update applied on its own, only the
+// transaction is shared, and a batch is either fully stored or not at all.
+func (s *Synchronizer) WithCatchUpBatchSize(size uint) *Synchronizer {
+	s.batchSize = int(size)
+	return s
+}
+
+// WithFinality sets the status a block needs to reach before it is stored. With FinalityL1, blocks ahead of the
+// L1 head are held in memory until the L1 head reaches them, the pending block is not polled and reorgs are
+// never reverted.
+func (s *Synchronizer) WithFinality(finality Finality) *Synchronizer {
+	s.finality = finality
+	return s
+}
+
+// WithUpstreamHeads subscribes to starknet_subscribeNewHeads of an upstream Starknet RPC node at the given
+// WebSocket URL, so that the latest and the pending block are fetched as soon as it sees a new head instead of at
+// the next poll.
+func (s *Synchronizer) WithUpstreamHeads(url string) *Synchronizer {
+	s.upstreamHeadsURL = url
+	return s
+}
+
 // WithListener registers an EventListener
 func (s *Synchronizer) WithListener(listener EventListener) *Synchronizer {
 	s.listener = listener
@@ -293,7 +341,6 @@ func (s *Synchronizer) handlePluginRevertBlock() {
 	}
 }
 
-//nolint:gocyclo
 func (s *Synchronizer) verifierTask(ctx context.Context, block *core.Block, stateUpdate *core.StateUpdate,
 	newClasses map[felt.Felt]core.Class, resetStreams context.CancelFunc,
 ) stream.Callback {
@@ -312,72 +359,184 @@ func (s *Synchronizer) verifierTask(ctx context.Context, block *core.Block, stat
 				resetStreams()
 				return
 			}
-			storeTimer := time.Now()
-			err = s.blockchain.Store(block, commitments, stateUpdate, newClasses)
-			if err != nil {
-				if errors.Is(err, blockchain.ErrParentDoesNotMatchHead) {
-					// revert the head and restart the sync process, hoping that the reorg is not deep
-					// if the reorg is deeper, we will end up here again and again until we fully revert reorged
-					// blocks
-					if s.plugin != nil {
-						s.handlePluginRevertBlock()
-					}
-					s.revertHead(block)
 
-					// The previous head has been reverted, hence, get the current head and store empty pending block
-					head, err := s.blockchain.HeadsHeader()
-					if err != nil {
-						s.log.Errorw("Failed to retrieve the head header", "err", err)
+			if s.finality == FinalityL1 {
+				accepted, err := s.acceptedOnL1(block)
+				if err == nil && !accepted {
+					// the blocks accepted on L1 so far are stored before waiting for the next L1 head
+					if !s.storeBatch() {
+						resetStreams()
+						return
 					}
-
-					if head != nil {
-						if err := s.storeEmptyPending(head); err != nil {
-							s.log.Errorw("Failed to store empty pending block", "number", block.Number)
-						}
+					err = s.waitForL1(ctx, block)
+				}
+				if err != nil {
+					if ctx.Err() == nil {
+						s.log.Warnw("Failed waiting for the L1 head", "number", block.Number, "err", err)
+						resetStreams()
 					}
-				} else {
-					s.log.Warnw("Failed storing Block", "number", block.Number,
-						"hash", block.Hash.ShortString(), "err", err)
+					return
 				}
-				resetStreams()
+			}
+
+			verified := &blockchain.VerifiedBlock{
+				Block:       block,
+				Commitments: commitments,
+				StateUpdate: stateUpdate,
+				NewClasses:  newClasses,
+			}
+			s.batch = append(s.batch, verified)
+			// in catch-up mode, an incomplete batch is also stored when the streams are reset, see syncBlocks
+			if s.catchUpMode && len(s.batch) < s.batchSize {
+				s.updateCatchUpMode(block, resetStreams)
 				return
 			}
 
-			if err := s.storeEmptyPending(block.Header); err != nil {
-				s.log.Errorw("Failed to store empty pending block", "number", block.Number)
+			if !s.storeBatch() {
+				resetStreams()
+				return
 			}
+			s.updateCatchUpMode(block, resetStreams)
+		}
+	}
+}
 
-			s.listener.OnSyncStepDone(OpStore, block.Number, time.Since(storeTimer))
+// storeBatch stores the verified blocks accumulated so far, in a single transaction if there are several of
+// them, and reports whether it succeeded. The batch is dropped either way, a failed one is fetched again once
+// the streams are reset.
+func (s *Synchronizer) storeBatch() bool {
+	if len(s.batch) == 0 {
+		return true
+	}
+	batch := s.batch
+	s.batch = nil
 
-			highestBlockHeader := s.highestBlockHeader.Load()
-			if highestBlockHeader != nil {
-				isBehind := highestBlockHeader.Number > block.Number+uint64(maxWorkers())
-				if s.catchUpMode != isBehind {
-					resetStreams()
-				}
-				s.catchUpMode = isBehind
+	first, last := batch[0].Block, batch[len(batch)-1].Block
+	storeTimer := time.Now()
+	var err error
+	if len(batch) == 1 {
+		err = s.blockchain.Store(first, batch[0].Commitments, batch[0].StateUpdate, batch[0].NewClasses)
+	} else {
+		err = s.blockchain.StoreBatch(batch)
+	}
+	if err != nil {
+		if errors.Is(err, blockchain.ErrParentDoesNotMatchHead) && s.finality == FinalityL1 {
+			// the head is accepted on L1 and is never reverted
+			s.log.Errorw("Block does not extend the head accepted on L1", "number", first.Number,
+				"hash", first.Hash.ShortString(), "parentHash", first.ParentHash.ShortString())
+		} else if errors.Is(err, blockchain.ErrParentDoesNotMatchHead) {
+			// revert the head and restart the sync process, hoping that the reorg is not deep
+			// if the reorg is deeper, we will end up here again and again until we fully revert reorged
+			// blocks
+			if s.plugin != nil {
+				s.handlePluginRevertBlock()
 			}
+			s.revertHead(first)
 
-			if highestBlockHeader == nil || highestBlockHeader.Number < block.Number {
-				s.highestBlockHeader.CompareAndSwap(highestBlockHeader, block.Header)
+			// The previous head has been reverted, hence, get the current head and store empty pending block
+			head, err := s.blockchain.HeadsHeader()
+			if err != nil {
+				s.log.Errorw("Failed to retrieve the head header", "err", err)
 			}
 
-			if s.currReorg != nil {
-				s.reorgFeed.Send(s.currReorg)
-				s.currReorg = nil // reset the reorg data
+			if head != nil {
+				if err := s.storeEmptyPending(head); err != nil {
+					s.log.Errorw("Failed to store empty pending block", "number", first.Number)
+				}
 			}
+		} else {
+			s.log.Warnw("Failed storing Block", "number", last.Number,
+				"hash", last.Hash.ShortString(), "batchSize", len(batch), "err", err)
+		}
+		return false
+	}
 
-			s.newHeads.Send(block)
-			s.log.Infow("Stored Block", "number", block.Number, "hash",
-				block.Hash.ShortString(), "root", block.GlobalStateRoot.ShortString())
-			if s.plugin != nil {
-				err := s.plugin.NewBlock(block, stateUpdate, newClasses)
-				if err != nil {
-					s.log.Errorw("Plugin NewBlock failure:", err)
-				}
+	if err := s.storeEmptyPending(last.Header); err != nil {
+		s.log.Errorw("Failed to store empty pending block", "number", last.Number)
+	}
+
+	s.listener.OnSyncStepDone(OpStore, last.Number, time.Since(storeTimer))
+
+	for _, verified := range batch {
+		block := verified.Block
+		if highestBlockHeader := s.highestBlockHeader.Load(); highestBlockHeader == nil ||
+			highestBlockHeader.Number < block.Number {
+			s.highestBlockHeader.CompareAndSwap(highestBlockHeader, block.Header)
+		}
+
+		if s.currReorg != nil {
+			s.reorgFeed.Send(s.currReorg)
+			s.currReorg = nil // reset the reorg data
+		}
+
+		s.newHeads.Send(block)
+		s.blockTimes.observe(block.Header)
+		s.log.Infow("Stored Block", "number", block.Number, "hash",
+			block.Hash.ShortString(), "root", block.GlobalStateRoot.ShortString())
+		if s.plugin != nil {
+			err := s.plugin.NewBlock(block, verified.StateUpdate, verified.NewClasses)
+			if err != nil {
+				s.log.Errorw("Plugin NewBlock failure:", err)
 			}
 		}
 	}
+	return true
+}
+
+// acceptedOnL1 reports whether the L1 head has reached the block. The hash of the block is checked if it is the
+// L1 head.
+func (s *Synchronizer) acceptedOnL1(block *core.Block) (bool, error) {
+	l1Head, err := s.blockchain.L1Head()
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if l1Head.BlockNumber < block.Number {
+		return false, nil
+	}
+	if l1Head.BlockNumber == block.Number && !l1Head.BlockHash.Equal(block.Hash) {
+		return false, fmt.Errorf("block hash %s does not match the L1 head hash %s", block.Hash, l1Head.BlockHash)
+	}
+	return true, nil
+}
+
+// waitForL1 blocks until the L1 head reaches the block.
+func (s *Synchronizer) waitForL1(ctx context.Context, block *core.Block) error {
+	sub := s.blockchain.SubscribeL1Head()
+	defer sub.Unsubscribe()
+
+	s.log.Debugw("Waiting for the L1 head", "number", block.Number)
+	for {
+		// checked after subscribing so that no L1 head is missed
+		accepted, err := s.acceptedOnL1(block)
+		if err != nil || accepted {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sub.Recv():
+		}
+	}
+}
+
+// updateCatchUpMode switches in and out of catch-up mode depending on how far behind the latest block the
+// given block is, resetting the streams to resize the worker pools.
+func (s *Synchronizer) updateCatchUpMode(block *core.Block, resetStreams context.CancelFunc) {
+	highestBlockHeader := s.highestBlockHeader.Load()
+	if highestBlockHeader == nil {
+		return
+	}
+
+	isBehind := highestBlockHeader.Number > block.Number+uint64(maxWorkers())
+	if s.catchUpMode != isBehind {
+		resetStreams()
+	}
+	s.catchUpMode = isBehind
 }
 
 func (s *Synchronizer) nextHeight() uint64 {
@@ -398,6 +557,10 @@ func (s *Synchronizer) syncBlocks(syncCtx context.Context) {
 	startingHeight := nextHeight
 	s.startingBlockNumber = &startingHeight
 
+	if s.upstreamHeadsURL != "" {
+		go s.listenUpstreamHeads(syncCtx)
+	}
+
 	latestSem := make(chan struct{}, 1)
 	if s.readOnlyBlockchain {
 		s.pollLatest(syncCtx, latestSem)
@@ -417,6 +580,7 @@ func (s *Synchronizer) syncBlocks(syncCtx context.Context) {
 			streamCancel()
 			fetchers.Wait()
 			verifiers.Wait()
+			s.storeBatch()
 
 			select {
 			case <-syncCtx.Done():
@@ -482,35 +646,41 @@ func (s *Synchronizer) revertHead(forkBlock *core.Block) {
 	s.listener.OnReorg(head.Number)
 }
 
+// pollPending polls the pending block, backing off while it does not change.
 func (s *Synchronizer) pollPending(ctx context.Context, sem chan struct{}) {
-	if s.pendingPollInterval == time.Duration(0) {
+	// the pending block is not accepted on L1
+	if s.pendingPollInterval == time.Duration(0) || s.finality == FinalityL1 {
 		return
 	}
 
-	pendingPollTicker := time.NewTicker(s.pendingPollInterval)
+	interval := s.pendingPollInterval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
 	for {
 		select {
 		case <-ctx.Done():
-			pendingPollTicker.Stop()
 			return
-		case <-pendingPollTicker.C:
-			select {
-			case sem <- struct{}{}:
-				go func() {
-					defer func() {
-						<-sem
-					}()
-					err := s.fetchAndStorePending(ctx)
-					if err != nil {
-						s.log.Debugw("Error while trying to poll pending block", "err", err)
-					}
-				}()
-			default:
-			}
+		case <-timer.C:
+		case <-s.pendingTrigger:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case sem <- struct{}{}:
 		}
+		changed, err := s.fetchAndStorePending(ctx)
+		<-sem
+		if err != nil {
+			s.log.Debugw("Error while trying to poll pending block", "err", err)
+		}
+
+		interval = s.nextPendingPollInterval(interval, changed)
+		timer.Reset(interval)
 	}
 }
 
+// pollLatest polls the latest block about once per block.
 func (s *Synchronizer) pollLatest(ctx context.Context, sem chan struct{}) {
 	poll := func() {
 		select {
@@ -530,52 +700,64 @@ func (s *Synchronizer) pollLatest(ctx context.Context, sem chan struct{}) {
 		}
 	}
 
-	ticker := time.NewTicker(time.Minute)
+	timer := time.NewTimer(s.latestPollInterval())
+	defer timer.Stop()
 	poll()
 
 	for {
 		select {
 		case <-ctx.Done():
-			ticker.Stop()
 			return
-		case <-ticker.C:
-			poll()
+		case <-timer.C:
+		case <-s.latestTrigger:
 		}
+		poll()
+		timer.Reset(s.latestPollInterval())
 	}
 }
 
-func (s *Synchronizer) fetchAndStorePending(ctx context.Context) error {
+// fetchAndStorePending fetches the pending block and stores it, returning whether it changed.
+func (s *Synchronizer) fetchAndStorePending(ctx context.Context) (bool, error) {
 	highestBlockHeader := s.highestBlockHeader.Load()
 	if highestBlockHeader == nil {
-		return nil
+		return false, nil
 	}
 
 	head, err := s.blockchain.HeadsHeader()
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// not at the tip of the chain yet, no need to poll pending
 	if highestBlockHeader.Number > head.Number {
-		return nil
+		return false, nil
 	}
 
 	pendingStateUpdate, pendingBlock, err := s.starknetData.StateUpdatePendingWithBlock(ctx)
 	if err != nil {
-		return err
+		return false, err
+	}
+
+	// skip fetching the classes of a pending block that StorePending would ignore
+	if existing, err := s.Pending(); err == nil && existing.Block.ParentHash.Equal(pendingBlock.ParentHash) &&
+		sameTransactions(existing.Block, pendingBlock) {
+		return false, nil
 	}
 
 	newClasses, err := s.fetchUnknownClasses(ctx, pendingStateUpdate)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	s.log.Debugw("Found pending block", "txns", pendingBlock.TransactionCount)
-	return s.StorePending(&Pending{
+	if err = s.StorePending(&Pending{
 		Block:       pendingBlock,
 		StateUpdate: pendingStateUpdate,
 		NewClasses:  newClasses,
-	})
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 func (s *Synchronizer) StartingBlockNumber() (uint64, error) {
@@ -621,8 +803,8 @@ func (s *Synchronizer) StorePending(p *Pending) error {
 	}
 
 	if existingPending, err := s.Pending(); err == nil {
-		if existingPending.Block.TransactionCount >= p.Block.TransactionCount {
-			// ignore the incoming pending if it has fewer transactions than the one we already have
+		if sameTransactions(existingPending.Block, p.Block) {
+			// ignore the incoming pending if it is the one we already have
 			return nil
 		}
 	} else if !errors.Is(err, ErrPendingBlockNotFound) {
@@ -631,10 +813,26 @@ func (s *Synchronizer) StorePending(p *Pending) error {
 	s.pending.Store(p)
 
 	s.pendingFeed.Send(p.Block)
+	s.sendPendingDelta(p.Block)
 
 	return nil
 }
 
+// sameTransactions reports whether both blocks have the same transactions in the same order. Pending blocks
+// with the same parent may lose transactions as well as gain some, so the number of transactions alone does
+// not tell whether the pending block changed.
+func sameTransactions(a, b *core.Block) bool {
+	if len(a.Transactions) != len(b.Transactions) {
+		return false
+	}
+	for i, txn := range a.Transactions {
+		if !txn.Hash().Equal(b.Transactions[i].Hash()) {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *Synchronizer) Pending() (*Pending, error) {
 	p := s.pending.Load()
 	if p == nil {
@@ -708,9 +906,31 @@ func (s *Synchronizer) storeEmptyPending(latestHeader *core.Header) error {
 	}
 
 	s.pending.Store(emptyPending)
+	s.sendPendingDelta(pendingBlock)
 	return nil
 }
 
+// sendPendingDelta passes the change of the pending block to the plugin, if it follows the pending block.
+func (s *Synchronizer) sendPendingDelta(pending *core.Block) {
+	deltaPlugin, ok := s.plugin.(junoplugin.PendingDeltaPlugin)
+	if !ok {
+		return
+	}
+
+	s.pendingTrackerMu.Lock()
+	defer s.pendingTrackerMu.Unlock()
+	if delta := s.pendingTracker.Update(pending); delta != nil {
+		if err := deltaPlugin.PendingDelta(delta); err != nil {
+			s.log.Errorw("Plugin PendingDelta failure:", "err", err)
+		}
+	}
+}
+
+func (s *Synchronizer) includedInChain(txHash *felt.Felt) bool {
+	_, err := s.blockchain.TransactionByHash(txHash)
+	return err == nil
+}
+
 func makeStateDiffForEmptyBlock(bc blockchain.Reader, blockNumber uint64) (*core.StateDiff, error) {
 	stateDiff := &core.StateDiff{
 		StorageDiffs:      make(map[felt.Felt]map[felt.Felt]*felt.Felt),
@@ -721,18 +941,16 @@ func makeStateDiffForEmptyBlock(bc blockchain.Reader, blockNumber uint64) (*core
 		ReplacedClasses:   make(map[felt.Felt]*felt.Felt),
 	}
 
-	const blockHashLag = 10
-	if blockNumber < blockHashLag {
+	if blockNumber < core.BlockHashLag {
 		return stateDiff, nil
 	}
 
-	header, err := bc.BlockHeaderByNumber(blockNumber - blockHashLag)
+	header, err := bc.BlockHeaderByNumber(blockNumber - core.BlockHashLag)
 	if err != nil {
 		return nil, err
 	}
 
-	blockHashStorageContract := new(felt.Felt).SetUint64(1)
-	stateDiff.StorageDiffs[*blockHashStorageContract] = map[felt.Felt]*felt.Felt{
+	stateDiff.StorageDiffs[*core.BlockHashContract] = map[felt.Felt]*felt.Felt{
 		*new(felt.Felt).SetUint64(header.Number): header.Hash,
 	}
 	return stateDiff, nil
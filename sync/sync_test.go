@@ -3,6 +3,10 @@ package sync_test
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	stdsync "sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -13,9 +17,11 @@ import (
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/db/pebble"
 	"github.com/NethermindEth/juno/mocks"
+	junoplugin "github.com/NethermindEth/juno/plugin"
 	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
 	"github.com/NethermindEth/juno/sync"
 	"github.com/NethermindEth/juno/utils"
+	"github.com/coder/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -65,6 +71,86 @@ func TestSyncBlocks(t *testing.T) {
 		testBlockchain(t, bc)
 	})
 
+	t.Run("sync multiple blocks in batches", func(t *testing.T) {
+		testDB := pebble.NewMemTest(t)
+		bc := blockchain.New(testDB, &utils.Mainnet)
+		synchronizer := sync.New(bc, gw, log, time.Duration(0), false, testDB).WithCatchUpBatchSize(8)
+		ctx, cancel := context.WithTimeout(t.Context(), timeout)
+
+		require.NoError(t, synchronizer.Run(ctx))
+		cancel()
+
+		testBlockchain(t, bc)
+	})
+
+	t.Run("sync blocks in batches while catching up", func(t *testing.T) {
+		testDB := pebble.NewMemTest(t)
+		bc := blockchain.New(testDB, &utils.Mainnet)
+
+		var synchronizer *sync.Synchronizer
+		mockSNData := mocks.NewMockStarknetData(mockCtrl)
+		mockSNData.EXPECT().BlockLatest(gomock.Any()).Return(&core.Block{Header: &core.Header{Number: 1000}}, nil).AnyTimes()
+		mockSNData.EXPECT().StateUpdateWithBlock(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, height uint64) (*core.StateUpdate, *core.Block, error) {
+			if height > 2 {
+				return nil, nil, errors.New("not found")
+			}
+			// the synchronizer is behind the latest block by the time the first block is stored
+			for synchronizer.HighestBlockHeader() == nil {
+				if ctx.Err() != nil {
+					return nil, nil, ctx.Err()
+				}
+				time.Sleep(time.Millisecond)
+			}
+			return gw.StateUpdateWithBlock(ctx, height)
+		}).AnyTimes()
+		mockSNData.EXPECT().Class(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, hash *felt.Felt) (core.Class, error) {
+			return gw.Class(ctx, hash)
+		}).AnyTimes()
+
+		var storedMu stdsync.Mutex
+		var stored []uint64
+		recorder := &newHeadsRecorder{}
+		synchronizer = sync.New(bc, mockSNData, log, time.Duration(0), false, testDB).WithCatchUpBatchSize(8).
+			WithPlugin(recorder).WithListener(&sync.SelectiveListener{
+			OnSyncStepDoneCb: func(op string, blockNum uint64, _ time.Duration) {
+				if op == sync.OpStore {
+					storedMu.Lock()
+					stored = append(stored, blockNum)
+					storedMu.Unlock()
+				}
+			},
+		})
+		recorder.sub = synchronizer.SubscribeNewHeads()
+		t.Cleanup(recorder.sub.Unsubscribe)
+		ctx, cancel := context.WithTimeout(t.Context(), timeout)
+
+		require.NoError(t, synchronizer.Run(ctx))
+		cancel()
+
+		testBlockchain(t, bc)
+		// the first block is stored before catching up, the others in a single batch
+		assert.Equal(t, []uint64{0, 2}, stored)
+		assert.Equal(t, []uint64{0, 1, 2}, recorder.heads)
+	})
+
+	t.Run("sync only blocks accepted on l1", func(t *testing.T) {
+		testDB := pebble.NewMemTest(t)
+		bc := blockchain.New(testDB, &utils.Mainnet)
+		b1, err := gw.BlockByNumber(t.Context(), 1)
+		require.NoError(t, err)
+		require.NoError(t, bc.SetL1Head(&core.L1Head{BlockNumber: 1, BlockHash: b1.Hash, StateRoot: b1.GlobalStateRoot}))
+
+		synchronizer := sync.New(bc, gw, log, time.Duration(0), false, testDB).WithFinality(sync.FinalityL1)
+		ctx, cancel := context.WithTimeout(t.Context(), timeout)
+
+		require.NoError(t, synchronizer.Run(ctx))
+		cancel()
+
+		head, err := bc.Head()
+		require.NoError(t, err)
+		assert.Equal(t, b1, head)
+	})
+
 	t.Run("sync multiple blocks in a non-empty db", func(t *testing.T) {
 		testDB := pebble.NewMemTest(t)
 		bc := blockchain.New(testDB, &utils.Mainnet)
@@ -263,6 +349,106 @@ func TestPending(t *testing.T) {
 	})
 }
 
+// newHeadsRecorder is a plugin recording the new heads announced before each block is passed to it.
+type newHeadsRecorder struct {
+	junoplugin.JunoPlugin
+	sub   sync.NewHeadSubscription
+	heads []uint64
+}
+
+func (r *newHeadsRecorder) NewBlock(*core.Block, *core.StateUpdate, map[felt.Felt]core.Class) error {
+	head := <-r.sub.Recv()
+	r.heads = append(r.heads, head.Number)
+	return nil
+}
+
+// pendingDeltaRecorder is a plugin following the pending block, recording its deltas.
+type pendingDeltaRecorder struct {
+	junoplugin.JunoPlugin
+	deltas []*core.PendingDelta
+}
+
+func (r *pendingDeltaRecorder) PendingDelta(delta *core.PendingDelta) error {
+	r.deltas = append(r.deltas, delta)
+	return nil
+}
+
+func TestStorePendingChanges(t *testing.T) {
+	client := feeder.NewTestClient(t, &utils.Mainnet)
+	gw := adaptfeeder.New(client)
+
+	testDB := pebble.NewMemTest(t)
+	chain := blockchain.New(testDB, &utils.Mainnet)
+	recorder := &pendingDeltaRecorder{}
+	synchronizer := sync.New(chain, gw, utils.NewNopZapLogger(), 0, false, testDB).WithPlugin(recorder)
+
+	genesis, err := gw.BlockByNumber(t.Context(), 0)
+	require.NoError(t, err)
+	genesisUpdate, err := gw.StateUpdate(t.Context(), 0)
+	require.NoError(t, err)
+	require.NoError(t, chain.Store(genesis, &core.BlockCommitments{}, genesisUpdate, nil))
+
+	pending := func(hashes ...uint64) *sync.Pending {
+		block := &core.Block{Header: &core.Header{
+			Number:           1,
+			ParentHash:       genesis.Hash,
+			ProtocolVersion:  genesis.ProtocolVersion,
+			TransactionCount: uint64(len(hashes)),
+		}}
+		for _, hash := range hashes {
+			txHash := new(felt.Felt).SetUint64(hash)
+			block.Transactions = append(block.Transactions, &core.InvokeTransaction{TransactionHash: txHash})
+			block.Receipts = append(block.Receipts, &core.TransactionReceipt{TransactionHash: txHash})
+		}
+		return &sync.Pending{Block: block, StateUpdate: &core.StateUpdate{StateDiff: &core.StateDiff{}}}
+	}
+
+	sub := synchronizer.SubscribePending()
+	t.Cleanup(sub.Unsubscribe)
+	for _, test := range []struct {
+		name      string
+		pending   *sync.Pending
+		stored    bool
+		retracted []*felt.Felt
+	}{
+		{name: "first pending block", pending: pending(1, 2), stored: true},
+		{name: "same transactions", pending: pending(1, 2), stored: false},
+		{
+			name:      "retracted transaction",
+			pending:   pending(1),
+			stored:    true,
+			retracted: []*felt.Felt{new(felt.Felt).SetUint64(2)},
+		},
+		{
+			name:      "replaced transaction",
+			pending:   pending(3),
+			stored:    true,
+			retracted: []*felt.Felt{new(felt.Felt).SetUint64(1)},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			before, _ := synchronizer.Pending()
+			require.NoError(t, synchronizer.StorePending(test.pending))
+
+			got, err := synchronizer.Pending()
+			require.NoError(t, err)
+			if !test.stored {
+				assert.Same(t, before, got)
+				return
+			}
+			assert.Same(t, test.pending, got)
+			select {
+			case block := <-sub.Recv():
+				assert.Same(t, test.pending.Block, block)
+			case <-time.After(timeout):
+				require.Fail(t, "pending block not sent")
+			}
+			require.NotEmpty(t, recorder.deltas)
+			assert.Equal(t, test.retracted, recorder.deltas[len(recorder.deltas)-1].Retracted)
+		})
+	}
+}
+
 func TestSubscribeNewHeads(t *testing.T) {
 	t.Parallel()
 	testDB := pebble.NewMemTest(t)
@@ -312,3 +498,55 @@ func TestSubscribePending(t *testing.T) {
 	require.Equal(t, pending.Block, pendingBlock)
 	sub.Unsubscribe()
 }
+
+func TestUpstreamHeads(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.CloseNow() //nolint:errcheck
+
+		_, msg, err := conn.Read(t.Context())
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Contains(t, string(msg), `"method":"starknet_subscribeNewHeads"`)
+		assert.NoError(t, conn.Write(t.Context(), websocket.MessageText, []byte(`{"jsonrpc":"2.0","result":1,"id":1}`)))
+		assert.NoError(t, conn.Write(t.Context(), websocket.MessageText,
+			[]byte(`{"jsonrpc":"2.0","method":"starknet_subscriptionNewHeads","params":{"subscription_id":1,"result":{}}}`)))
+
+		// wait for the synchronizer to close the connection
+		_, _, err = conn.Read(t.Context())
+		assert.Error(t, err)
+	}))
+	t.Cleanup(srv.Close)
+
+	mockCtrl := gomock.NewController(t)
+	mockSNData := mocks.NewMockStarknetData(mockCtrl)
+	var polls atomic.Int32
+	mockSNData.EXPECT().BlockLatest(gomock.Any()).DoAndReturn(func(context.Context) (*core.Block, error) {
+		polls.Add(1)
+		return nil, errors.New("no block")
+	}).AnyTimes()
+
+	testDB := pebble.NewMemTest(t)
+	bc := blockchain.New(testDB, &utils.Mainnet)
+	synchronizer := sync.New(bc, mockSNData, utils.NewNopZapLogger(), 0, true, testDB).
+		WithUpstreamHeads("ws" + strings.TrimPrefix(srv.URL, "http"))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error)
+	go func() {
+		done <- synchronizer.Run(ctx)
+	}()
+
+	// the latest block is polled on start and once more on the new head, well before the next poll
+	require.Eventually(t, func() bool {
+		return polls.Load() == 2
+	}, timeout, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,69 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+const upstreamRetryDelay = 5 * time.Second
+
+// listenUpstreamHeads subscribes to the new heads of the upstream node and triggers polling the latest and the
+// pending block on each of them. It resubscribes until the context is cancelled.
+func (s *Synchronizer) listenUpstreamHeads(ctx context.Context) {
+	for {
+		err := s.subscribeUpstreamHeads(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		s.log.Warnw("Upstream new heads subscription failed, resubscribing", "url", s.upstreamHeadsURL,
+			"retryAfter", upstreamRetryDelay.String(), "err", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(upstreamRetryDelay):
+		}
+	}
+}
+
+func (s *Synchronizer) subscribeUpstreamHeads(ctx context.Context) error {
+	conn, _, err := websocket.Dial(ctx, s.upstreamHeadsURL, nil) //nolint:bodyclose // websocket package closes resp.Body for us.
+	if err != nil {
+		return err
+	}
+	defer conn.CloseNow() //nolint:errcheck
+
+	subscribe := []byte(`{"jsonrpc":"2.0","id":1,"method":"starknet_subscribeNewHeads"}`)
+	if err = conn.Write(ctx, websocket.MessageText, subscribe); err != nil {
+		return err
+	}
+	s.log.Infow("Subscribed to upstream new heads", "url", s.upstreamHeadsURL)
+
+	for {
+		_, msg, err := conn.Read(ctx)
+		if err != nil {
+			return err
+		}
+
+		var message struct {
+			Method string `json:"method"`
+			Error  *struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err = json.Unmarshal(msg, &message); err != nil {
+			return err
+		}
+		if message.Error != nil {
+			return fmt.Errorf("subscribe: %s (%d)", message.Error.Message, message.Error.Code)
+		}
+		if message.Method == "starknet_subscriptionNewHeads" {
+			s.triggerPolls()
+		}
+	}
+}
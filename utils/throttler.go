@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"errors"
 	"math"
 	"sync/atomic"
@@ -32,12 +33,23 @@ func (t *Throttler[T]) WithMaxQueueLen(maxQueueLen int32) *Throttler[T] {
 
 // Do lets caller acquire the resource within the context of a callback
 func (t *Throttler[T]) Do(doer func(resource *T) error) error {
+	return t.DoContext(context.Background(), doer)
+}
+
+// DoContext is like Do but gives up waiting for the resource once ctx is done,
+// in which case the callback is not run and the context error is returned
+func (t *Throttler[T]) DoContext(ctx context.Context, doer func(resource *T) error) error {
 	queueLen := t.queue.Add(1)
 	if queueLen > t.maxQueueLen {
 		t.queue.Add(-1)
 		return ErrResourceBusy
 	}
-	t.sem <- struct{}{}
+	select {
+	case t.sem <- struct{}{}:
+	case <-ctx.Done():
+		t.queue.Add(-1)
+		return ctx.Err()
+	}
 	defer func() {
 		<-t.sem
 	}()
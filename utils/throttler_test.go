@@ -1,6 +1,7 @@
 package utils_test
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"sync/atomic"
@@ -61,3 +62,30 @@ func TestThrottler(t *testing.T) {
 	wg.Wait()
 	assert.Equal(t, int64(4), runCount)
 }
+
+func TestThrottlerDoContext(t *testing.T) {
+	throttledRes := utils.NewThrottler(1, new(int))
+	waitOn := make(chan struct{})
+
+	go func() {
+		_ = throttledRes.Do(func(*int) error {
+			<-waitOn
+			return nil
+		})
+	}()
+	time.Sleep(time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	var ran bool
+	err := throttledRes.DoContext(ctx, func(*int) error {
+		ran = true
+		return nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, ran)
+	assert.Equal(t, 0, throttledRes.QueueLen())
+
+	close(waitOn)
+}
@@ -0,0 +1,74 @@
+package vm
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/NethermindEth/juno/utils"
+)
+
+// RustBackend is the name of the default backend, the Rust VM called through cgo.
+const RustBackend = "rust"
+
+// BackendConfig configures the VMs created by a backend.
+type BackendConfig struct {
+	ConcurrencyMode bool
+	// VersionedConstantsFile overrides the versioned constants for the VMs of the backend only, leaving the
+	// ones set with SetVersionedConstants to the other VMs. The constants of every VM created with it stay in
+	// memory until the process exits, so it is meant for VMs set up once at startup.
+	VersionedConstantsFile string
+}
+
+// Backend creates the VMs of a VM implementation.
+type Backend func(cfg *BackendConfig, log utils.SimpleLogger) (VM, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]Backend{
+		RustBackend: newRustVM,
+	}
+)
+
+// RegisterBackend makes a VM implementation selectable by name. It panics if the name is already taken.
+func RegisterBackend(name string, backend Backend) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	if _, ok := backends[name]; ok {
+		panic("vm: backend " + name + " is already registered")
+	}
+	backends[name] = backend
+}
+
+// Backends returns the names of the registered backends, sorted.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	return slices.Sorted(maps.Keys(backends))
+}
+
+// NewBackend creates a VM of the backend registered with name.
+func NewBackend(name string, cfg *BackendConfig, log utils.SimpleLogger) (VM, error) {
+	backendsMu.RLock()
+	backend, ok := backends[name]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown VM backend %q, available backends: %v", name, Backends())
+	}
+	return backend(cfg, log)
+}
+
+func newRustVM(cfg *BackendConfig, log utils.SimpleLogger) (VM, error) {
+	v := &vm{
+		log:             log,
+		concurrencyMode: cfg.ConcurrencyMode,
+	}
+	if cfg.VersionedConstantsFile != "" {
+		var err error
+		if v.versionedConstantsID, err = registerVersionedConstants(cfg.VersionedConstantsFile); err != nil {
+			return nil, fmt.Errorf("failed to set versioned constants: %w", err)
+		}
+	}
+	return v, nil
+}
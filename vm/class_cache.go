@@ -0,0 +1,153 @@
+package vm
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/ethereum/go-ethereum/common/lru"
+)
+
+// classCache is the cache of marshalled classes shared by all VM instances, nil if disabled.
+var classCache atomic.Pointer[ClassCache]
+
+// SetClassCache makes all VM instances use cache for the classes they read, a nil cache disables caching.
+func SetClassCache(cache *ClassCache) {
+	classCache.Store(cache)
+}
+
+type ClassCacheListener interface {
+	OnClassCacheHit()
+	OnClassCacheMiss()
+}
+
+type SelectiveClassCacheListener struct {
+	OnClassCacheHitCb  func()
+	OnClassCacheMissCb func()
+}
+
+func (l *SelectiveClassCacheListener) OnClassCacheHit() {
+	if l.OnClassCacheHitCb != nil {
+		l.OnClassCacheHitCb()
+	}
+}
+
+func (l *SelectiveClassCacheListener) OnClassCacheMiss() {
+	if l.OnClassCacheMissCb != nil {
+		l.OnClassCacheMissCb()
+	}
+}
+
+// ClassCache is a size-bounded cache of classes marshalled for the VM, keyed by class hash. Classes can't
+// change once declared, so entries are never invalidated, and the least recently used ones are evicted first.
+//
+// On a hit, the state being executed on is still checked to declare the class, so that classes declared
+// elsewhere are not visible to the VM. States implementing core.ClassChecker do so without decoding the class.
+type ClassCache struct {
+	listener ClassCacheListener
+	maxSize  uint64
+
+	mu      sync.Mutex
+	entries *lru.SizeConstrainedCache[felt.Felt, json.RawMessage]
+}
+
+// NewClassCache creates a cache holding at most maxSize bytes of marshalled classes.
+func NewClassCache(maxSize uint64) *ClassCache {
+	return &ClassCache{
+		listener: &SelectiveClassCacheListener{},
+		maxSize:  maxSize,
+		entries:  lru.NewSizeConstrainedCache[felt.Felt, json.RawMessage](maxSize),
+	}
+}
+
+// WithListener registers a ClassCacheListener
+func (c *ClassCache) WithListener(listener ClassCacheListener) *ClassCache {
+	c.listener = listener
+	return c
+}
+
+// get returns the cached class, if any, without counting a hit or a miss.
+func (c *ClassCache) get(classHash *felt.Felt) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Get(*classHash)
+}
+
+// marshal returns the marshalled class, from the cache if possible.
+func (c *ClassCache) marshal(classHash *felt.Felt, class core.Class) (json.RawMessage, error) {
+	c.mu.Lock()
+	classInfo, found := c.entries.Get(*classHash)
+	c.mu.Unlock()
+	if found {
+		c.listener.OnClassCacheHit()
+		return classInfo, nil
+	}
+	c.listener.OnClassCacheMiss()
+
+	classInfo, err := marshalClassInfo(class)
+	if err != nil {
+		return nil, err
+	}
+	c.add(classHash, classInfo)
+	return classInfo, nil
+}
+
+func (c *ClassCache) add(classHash *felt.Felt, classInfo json.RawMessage) {
+	if uint64(len(classInfo)) > c.maxSize {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries.Add(*classHash, classInfo)
+}
+
+// Warm marshals the given classes into the cache, the classes used the most should come last so that
+// they are the last to be evicted.
+func (c *ClassCache) Warm(state core.StateReader, classHashes []*felt.Felt) error {
+	for _, classHash := range classHashes {
+		declaredClass, err := state.Class(classHash)
+		if err != nil {
+			return err
+		}
+
+		classInfo, err := marshalClassInfo(declaredClass.Class)
+		if err != nil {
+			return err
+		}
+		c.add(classHash, classInfo)
+	}
+	return nil
+}
+
+// stateClassInfo returns the class declared in state marshalled for the VM, through the shared cache if there
+// is one. db.ErrKeyNotFound is returned if the class is not declared in state.
+func stateClassInfo(state core.StateReader, classHash *felt.Felt) (json.RawMessage, error) {
+	cache := classCache.Load()
+	if cache != nil {
+		if _, ok := state.(core.ClassChecker); ok {
+			if classInfo, found := cache.get(classHash); found {
+				declared, err := core.HasClass(state, classHash)
+				if err != nil {
+					return nil, err
+				} else if !declared {
+					return nil, db.ErrKeyNotFound
+				}
+				cache.listener.OnClassCacheHit()
+				return classInfo, nil
+			}
+		}
+	}
+
+	declaredClass, err := state.Class(classHash)
+	if err != nil {
+		return nil, err
+	}
+	if cache != nil {
+		return cache.marshal(classHash, declaredClass.Class)
+	}
+	return marshalClassInfo(declaredClass.Class)
+}
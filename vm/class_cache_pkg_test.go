@@ -0,0 +1,111 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// classesState is a state holding only classes.
+type classesState struct {
+	core.StateReader
+
+	classes map[felt.Felt]core.Class
+}
+
+func (s classesState) Class(classHash *felt.Felt) (*core.DeclaredClass, error) {
+	class, found := s.classes[*classHash]
+	if !found {
+		return nil, db.ErrKeyNotFound
+	}
+	return &core.DeclaredClass{Class: class}, nil
+}
+
+// checkedClassesState is a classesState that can check that a class is declared, and counts the classes read.
+type checkedClassesState struct {
+	classesState
+
+	reads *int
+}
+
+func (s checkedClassesState) Class(classHash *felt.Felt) (*core.DeclaredClass, error) {
+	*s.reads++
+	return s.classesState.Class(classHash)
+}
+
+func (s checkedClassesState) HasClass(classHash *felt.Felt) (bool, error) {
+	_, found := s.classes[*classHash]
+	return found, nil
+}
+
+func TestClassCache(t *testing.T) {
+	var hits, misses int
+	cache := NewClassCache(1 << 20).WithListener(&SelectiveClassCacheListener{
+		OnClassCacheHitCb:  func() { hits++ },
+		OnClassCacheMissCb: func() { misses++ },
+	})
+
+	classHash := new(felt.Felt).SetUint64(1)
+	class := &core.Cairo1Class{
+		Program:  []*felt.Felt{new(felt.Felt).SetUint64(1), new(felt.Felt), new(felt.Felt)},
+		Compiled: &core.CompiledClass{Prime: big.NewInt(1)},
+	}
+	expected, err := marshalClassInfo(class)
+	require.NoError(t, err)
+
+	t.Run("miss then hit", func(t *testing.T) {
+		for range 2 {
+			classInfo, err := cache.marshal(classHash, class)
+			require.NoError(t, err)
+			assert.Equal(t, expected, classInfo)
+		}
+		assert.Equal(t, 1, hits)
+		assert.Equal(t, 1, misses)
+	})
+
+	t.Run("marshalling errors are not cached", func(t *testing.T) {
+		_, err := cache.marshal(new(felt.Felt).SetUint64(2), &core.Cairo1Class{})
+		require.Error(t, err)
+		_, found := cache.entries.Get(*new(felt.Felt).SetUint64(2))
+		assert.False(t, found)
+	})
+
+	t.Run("warm", func(t *testing.T) {
+		warmedHash := new(felt.Felt).SetUint64(3)
+		state := classesState{classes: map[felt.Felt]core.Class{*warmedHash: class}}
+		require.NoError(t, cache.Warm(state, []*felt.Felt{warmedHash}))
+
+		classInfo, found := cache.entries.Get(*warmedHash)
+		require.True(t, found)
+		assert.Equal(t, expected, classInfo)
+
+		missingHash := new(felt.Felt).SetUint64(4)
+		require.ErrorIs(t, cache.Warm(state, []*felt.Felt{missingHash}), db.ErrKeyNotFound)
+	})
+	t.Run("hits only check that the class is declared", func(t *testing.T) {
+		SetClassCache(cache)
+		t.Cleanup(func() { SetClassCache(nil) })
+
+		declaredHash := new(felt.Felt).SetUint64(5)
+		var reads int
+		state := checkedClassesState{
+			classesState: classesState{classes: map[felt.Felt]core.Class{*declaredHash: class}},
+			reads:        &reads,
+		}
+		for range 2 {
+			classInfo, err := stateClassInfo(state, declaredHash)
+			require.NoError(t, err)
+			assert.Equal(t, expected, classInfo)
+		}
+		assert.Equal(t, 1, reads)
+
+		// cached, but not declared in this state
+		_, err := stateClassInfo(checkedClassesState{reads: &reads}, declaredHash)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+}
@@ -0,0 +1,186 @@
+package vm
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+)
+
+type DifferentialListener interface {
+	OnComparison(method string)
+	OnMismatch(method, field string)
+}
+
+type SelectiveDifferentialListener struct {
+	OnComparisonCb func(method string)
+	OnMismatchCb   func(method, field string)
+}
+
+func (l *SelectiveDifferentialListener) OnComparison(method string) {
+	if l.OnComparisonCb != nil {
+		l.OnComparisonCb(method)
+	}
+}
+
+func (l *SelectiveDifferentialListener) OnMismatch(method, field string) {
+	if l.OnMismatchCb != nil {
+		l.OnMismatchCb(method, field)
+	}
+}
+
+var _ VM = (*DifferentialVM)(nil)
+
+// DifferentialVM runs every call and execution on a primary and a shadow VM and reports where their results
+// differ. Only the results of the primary are returned, so the shadow can be a new VM version or use other
+// versioned constants without affecting the callers.
+//
+// Both VMs run concurrently on the same state reader, which is only read from.
+type DifferentialVM struct {
+	primary  VM
+	shadow   VM
+	log      utils.SimpleLogger
+	listener DifferentialListener
+}
+
+func NewDifferential(primary, shadow VM, log utils.SimpleLogger) *DifferentialVM {
+	return &DifferentialVM{
+		primary:  primary,
+		shadow:   shadow,
+		log:      log,
+		listener: &SelectiveDifferentialListener{},
+	}
+}
+
+// WithListener registers a DifferentialListener
+func (d *DifferentialVM) WithListener(listener DifferentialListener) *DifferentialVM {
+	d.listener = listener
+	return d
+}
+
+func (d *DifferentialVM) Call(callInfo *CallInfo, blockInfo *BlockInfo, state core.StateReader,
+	network *utils.Network, maxSteps uint64, sierraVersion string, structuredErrStack, returnStateDiff bool,
+) (CallResult, error) {
+	var (
+		shadowRes CallResult
+		shadowErr error
+	)
+	shadowDone := runShadow(func() {
+		shadowRes, shadowErr = d.shadow.Call(callInfo, blockInfo, state, network, maxSteps, sierraVersion,
+			structuredErrStack, returnStateDiff)
+	}, &shadowErr)
+	res, err := d.primary.Call(callInfo, blockInfo, state, network, maxSteps, sierraVersion,
+		structuredErrStack, returnStateDiff)
+	<-shadowDone
+
+	mismatches := compareErrors(err, shadowErr)
+	if err == nil && shadowErr == nil {
+		mismatches = appendIfDifferent(mismatches, "result", res.Result, shadowRes.Result)
+		mismatches = appendIfDifferent(mismatches, "execution_failed", res.ExecutionFailed, shadowRes.ExecutionFailed)
+		mismatches = appendIfDifferent(mismatches, "state_diff", res.StateDiff, shadowRes.StateDiff)
+	}
+	d.report("call", mismatches, err, shadowErr, "contract", callInfo.ContractAddress, "block",
+		blockInfo.Header.Number)
+	return res, err
+}
+
+func (d *DifferentialVM) Execute(txns []core.Transaction, declaredClasses []core.Class, paidFeesOnL1 []*felt.Felt,
+	blockInfo *BlockInfo, state core.StateReader, network *utils.Network, skipChargeFee, skipValidate,
+	errOnRevert, errStack bool,
+) (ExecutionResults, error) {
+	var (
+		shadowRes ExecutionResults
+		shadowErr error
+	)
+	shadowDone := runShadow(func() {
+		shadowRes, shadowErr = d.shadow.Execute(txns, declaredClasses, paidFeesOnL1, blockInfo, state, network,
+			skipChargeFee, skipValidate, errOnRevert, errStack)
+	}, &shadowErr)
+	res, err := d.primary.Execute(txns, declaredClasses, paidFeesOnL1, blockInfo, state, network,
+		skipChargeFee, skipValidate, errOnRevert, errStack)
+	<-shadowDone
+
+	mismatches := compareErrors(err, shadowErr)
+	if err == nil && shadowErr == nil {
+		mismatches = appendIfDifferent(mismatches, "fees", res.OverallFees, shadowRes.OverallFees)
+		mismatches = appendIfDifferent(mismatches, "gas_consumed", res.GasConsumed, shadowRes.GasConsumed)
+		mismatches = appendIfDifferent(mismatches, "data_availability", res.DataAvailability,
+			shadowRes.DataAvailability)
+		mismatches = appendIfDifferent(mismatches, "steps", res.NumSteps, shadowRes.NumSteps)
+		mismatches = appendIfDifferent(mismatches, "receipts", res.Receipts, shadowRes.Receipts)
+		traces, stateDiffs := splitStateDiffs(res.Traces)
+		shadowTraces, shadowStateDiffs := splitStateDiffs(shadowRes.Traces)
+		mismatches = appendIfDifferent(mismatches, "traces", traces, shadowTraces)
+		mismatches = appendIfDifferent(mismatches, "state_diffs", stateDiffs, shadowStateDiffs)
+	}
+	var firstTxn *felt.Felt
+	if len(txns) > 0 {
+		firstTxn = txns[0].Hash()
+	}
+	d.report("execute", mismatches, err, shadowErr, "transactions", len(txns), "first_transaction", firstTxn,
+		"block", blockInfo.Header.Number)
+	return res, err
+}
+
+// runShadow runs fn in its own goroutine, turning a panic of the shadow VM into an error so that it never
+// takes the node down. The returned channel is closed once fn is done.
+func runShadow(fn func(), shadowErr *error) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				*shadowErr = fmt.Errorf("shadow VM panicked: %v", recovered)
+			}
+		}()
+		fn()
+	}()
+	return done
+}
+
+func (d *DifferentialVM) report(method string, mismatches []string, err, shadowErr error, keysAndValues ...any) {
+	d.listener.OnComparison(method)
+	if len(mismatches) == 0 {
+		return
+	}
+	for _, field := range mismatches {
+		d.listener.OnMismatch(method, field)
+	}
+	keysAndValues = append([]any{"method", method, "fields", mismatches}, keysAndValues...)
+	if err != nil || shadowErr != nil {
+		keysAndValues = append(keysAndValues, "err", err, "shadow_err", shadowErr)
+	}
+	d.log.Warnw("Shadow VM results differ", keysAndValues...)
+}
+
+func compareErrors(err, shadowErr error) []string {
+	switch {
+	case err == nil && shadowErr == nil:
+		return nil
+	case err == nil || shadowErr == nil || err.Error() != shadowErr.Error():
+		return []string{"error"}
+	default:
+		return nil
+	}
+}
+
+func appendIfDifferent(mismatches []string, field string, a, b any) []string {
+	if !reflect.DeepEqual(a, b) {
+		return append(mismatches, field)
+	}
+	return mismatches
+}
+
+// splitStateDiffs separates the state diffs from the traces so that they are compared on their own.
+func splitStateDiffs(traces []TransactionTrace) ([]TransactionTrace, []*StateDiff) {
+	withoutDiffs := make([]TransactionTrace, len(traces))
+	stateDiffs := make([]*StateDiff, len(traces))
+	for idx := range traces {
+		withoutDiffs[idx] = traces[idx]
+		withoutDiffs[idx].StateDiff = nil
+		stateDiffs[idx] = traces[idx].StateDiff
+	}
+	return withoutDiffs, stateDiffs
+}
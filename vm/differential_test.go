@@ -0,0 +1,143 @@
+package vm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVM returns fixed results, or panics with panicValue if it is set.
+type fakeVM struct {
+	callResult CallResult
+	execResult ExecutionResults
+	err        error
+	panicValue any
+}
+
+func (f *fakeVM) Call(*CallInfo, *BlockInfo, core.StateReader, *utils.Network, uint64, string, bool, bool,
+) (CallResult, error) {
+	if f.panicValue != nil {
+		panic(f.panicValue)
+	}
+	return f.callResult, f.err
+}
+
+func (f *fakeVM) Execute([]core.Transaction, []core.Class, []*felt.Felt, *BlockInfo, core.StateReader,
+	*utils.Network, bool, bool, bool, bool,
+) (ExecutionResults, error) {
+	if f.panicValue != nil {
+		panic(f.panicValue)
+	}
+	return f.execResult, f.err
+}
+
+type recordingListener struct {
+	comparisons []string
+	mismatches  []string
+}
+
+func (l *recordingListener) OnComparison(method string) {
+	l.comparisons = append(l.comparisons, method)
+}
+
+func (l *recordingListener) OnMismatch(method, field string) {
+	l.mismatches = append(l.mismatches, method+"."+field)
+}
+
+func TestDifferentialVMExecute(t *testing.T) {
+	one, two := new(felt.Felt).SetUint64(1), new(felt.Felt).SetUint64(2)
+	results := func(fee *felt.Felt, storageValue uint64) ExecutionResults {
+		return ExecutionResults{
+			OverallFees: []*felt.Felt{fee},
+			GasConsumed: []core.GasConsumed{{L2Gas: 7}},
+			NumSteps:    3,
+			Traces: []TransactionTrace{{
+				Type: TxnInvoke,
+				StateDiff: &StateDiff{StorageDiffs: []StorageDiff{{
+					Address:        *one,
+					StorageEntries: []Entry{{Key: *one, Value: *new(felt.Felt).SetUint64(storageValue)}},
+				}}},
+			}},
+		}
+	}
+	blockInfo := &BlockInfo{Header: &core.Header{Number: 5}}
+	txns := []core.Transaction{&core.InvokeTransaction{TransactionHash: one}}
+
+	tests := map[string]struct {
+		primary    *fakeVM
+		shadow     *fakeVM
+		mismatches []string
+	}{
+		"same results": {
+			primary: &fakeVM{execResult: results(one, 1)},
+			shadow:  &fakeVM{execResult: results(one, 1)},
+		},
+		"different fee and state diff": {
+			primary:    &fakeVM{execResult: results(one, 1)},
+			shadow:     &fakeVM{execResult: results(two, 2)},
+			mismatches: []string{"execute.fees", "execute.state_diffs"},
+		},
+		"shadow error": {
+			primary:    &fakeVM{execResult: results(one, 1)},
+			shadow:     &fakeVM{err: errors.New("unsupported")},
+			mismatches: []string{"execute.error"},
+		},
+		"same error": {
+			primary: &fakeVM{err: errors.New("reverted")},
+			shadow:  &fakeVM{err: errors.New("reverted")},
+		},
+		"shadow panic": {
+			primary:    &fakeVM{execResult: results(one, 1)},
+			shadow:     &fakeVM{panicValue: "boom"},
+			mismatches: []string{"execute.error"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			listener := new(recordingListener)
+			differential := NewDifferential(test.primary, test.shadow, utils.NewNopZapLogger()).WithListener(listener)
+
+			res, err := differential.Execute(txns, nil, nil, blockInfo, nil, &utils.Mainnet, false, false, false, false)
+			assert.Equal(t, test.primary.execResult, res)
+			assert.Equal(t, test.primary.err, err)
+			assert.Equal(t, []string{"execute"}, listener.comparisons)
+			assert.Equal(t, test.mismatches, listener.mismatches)
+		})
+	}
+}
+
+func TestDifferentialVMCall(t *testing.T) {
+	primary := &fakeVM{callResult: CallResult{Result: []*felt.Felt{new(felt.Felt).SetUint64(1)}}}
+	shadow := &fakeVM{callResult: CallResult{Result: []*felt.Felt{new(felt.Felt).SetUint64(1)}, ExecutionFailed: true}}
+	listener := new(recordingListener)
+	differential := NewDifferential(primary, shadow, utils.NewNopZapLogger()).WithListener(listener)
+
+	res, err := differential.Call(&CallInfo{ContractAddress: new(felt.Felt)}, &BlockInfo{Header: &core.Header{}}, nil,
+		&utils.Mainnet, 0, "", false, false)
+	require.NoError(t, err)
+	assert.Equal(t, primary.callResult, res)
+	assert.Equal(t, []string{"call.execution_failed"}, listener.mismatches)
+}
+
+func TestNewBackend(t *testing.T) {
+	_, err := NewBackend("unknown", &BackendConfig{}, utils.NewNopZapLogger())
+	require.ErrorContains(t, err, `unknown VM backend "unknown"`)
+
+	RegisterBackend("fake", func(*BackendConfig, utils.SimpleLogger) (VM, error) {
+		return &fakeVM{}, nil
+	})
+	assert.Equal(t, []string{"fake", RustBackend}, Backends())
+	v, err := NewBackend("fake", &BackendConfig{}, utils.NewNopZapLogger())
+	require.NoError(t, err)
+	assert.IsType(t, &fakeVM{}, v)
+
+	assert.Panics(t, func() {
+		RegisterBackend(RustBackend, newRustVM)
+	})
+}
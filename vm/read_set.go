@@ -0,0 +1,91 @@
+package vm
+
+import (
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+)
+
+// ReadSet lists the parts of the state that were read during execution, in the order of their first read.
+type ReadSet struct {
+	StorageKeys []StorageKeys `json:"storage_keys"`
+	// Nonces are the addresses of the contracts whose nonce was read.
+	Nonces []felt.Felt `json:"nonces"`
+	// ClassHashes are the addresses of the contracts whose class hash was read.
+	ClassHashes []felt.Felt `json:"class_hashes"`
+	// Classes are the hashes of the classes that were read.
+	Classes []felt.Felt `json:"classes"`
+}
+
+type StorageKeys struct {
+	ContractAddress felt.Felt   `json:"contract_address"`
+	Keys            []felt.Felt `json:"keys"`
+}
+
+type storageSlot struct {
+	address felt.Felt
+	key     felt.Felt
+}
+
+// ReadRecorder is a core.StateReader recording what is read through it, including reads that fail.
+type ReadRecorder struct {
+	core.StateReader
+
+	storage     *utils.OrderedSet[storageSlot, storageSlot]
+	nonces      *utils.OrderedSet[felt.Felt, felt.Felt]
+	classHashes *utils.OrderedSet[felt.Felt, felt.Felt]
+	classes     *utils.OrderedSet[felt.Felt, felt.Felt]
+}
+
+func NewReadRecorder(state core.StateReader) *ReadRecorder {
+	return &ReadRecorder{
+		StateReader: state,
+		storage:     utils.NewOrderedSet[storageSlot, storageSlot](),
+		nonces:      utils.NewOrderedSet[felt.Felt, felt.Felt](),
+		classHashes: utils.NewOrderedSet[felt.Felt, felt.Felt](),
+		classes:     utils.NewOrderedSet[felt.Felt, felt.Felt](),
+	}
+}
+
+func (r *ReadRecorder) ContractClassHash(addr *felt.Felt) (*felt.Felt, error) {
+	r.classHashes.Put(*addr, *addr)
+	return r.StateReader.ContractClassHash(addr)
+}
+
+func (r *ReadRecorder) ContractNonce(addr *felt.Felt) (*felt.Felt, error) {
+	r.nonces.Put(*addr, *addr)
+	return r.StateReader.ContractNonce(addr)
+}
+
+func (r *ReadRecorder) ContractStorage(addr, key *felt.Felt) (*felt.Felt, error) {
+	slot := storageSlot{address: *addr, key: *key}
+	r.storage.Put(slot, slot)
+	return r.StateReader.ContractStorage(addr, key)
+}
+
+func (r *ReadRecorder) Class(classHash *felt.Felt) (*core.DeclaredClass, error) {
+	r.classes.Put(*classHash, *classHash)
+	return r.StateReader.Class(classHash)
+}
+
+// ReadSet returns everything read so far.
+func (r *ReadRecorder) ReadSet() *ReadSet {
+	readSet := &ReadSet{
+		StorageKeys: []StorageKeys{},
+		Nonces:      r.nonces.List(),
+		ClassHashes: r.classHashes.List(),
+		Classes:     r.classes.List(),
+	}
+
+	contractIdx := make(map[felt.Felt]int)
+	for _, slot := range r.storage.List() {
+		idx, found := contractIdx[slot.address]
+		if !found {
+			idx = len(readSet.StorageKeys)
+			contractIdx[slot.address] = idx
+			readSet.StorageKeys = append(readSet.StorageKeys, StorageKeys{ContractAddress: slot.address})
+		}
+		readSet.StorageKeys[idx].Keys = append(readSet.StorageKeys[idx].Keys, slot.key)
+	}
+	return readSet
+}
@@ -13,7 +13,7 @@ use serde_json::json;
 use std::{
     ffi::{c_char, c_longlong, c_uchar, c_ulonglong, c_void, CStr, CString},
     slice,
-    sync::Arc,
+    sync::{Arc, RwLock},
 };
 
 use anyhow::Result;
@@ -119,6 +119,7 @@ pub struct BlockInfo {
     pub use_blob_data: c_uchar,
     pub l2_gas_price_wei: [c_uchar; 32],
     pub l2_gas_price_fri: [c_uchar; 32],
+    pub versioned_constants_id: c_ulonglong,
 }
 
 #[no_mangle]
@@ -158,7 +159,8 @@ pub extern "C" fn cairoVMCall(
         }
     }
 
-    let version_constants = get_versioned_constants(block_info.version);
+    let version_constants =
+        get_versioned_constants(block_info.version, block_info.versioned_constants_id);
     let sierra_version_str = unsafe { CStr::from_ptr(sierra_version) }.to_str().unwrap();
     let sierra_version = SierraVersion::from_str(sierra_version_str).unwrap();
     let initial_gas: u64 = if sierra_version < version_constants.min_sierra_version_for_sierra_gas {
@@ -692,7 +694,8 @@ fn build_block_context(
             )),
         })
     }
-    let mut constants = get_versioned_constants(block_info.version);
+    let mut constants =
+        get_versioned_constants(block_info.version, block_info.versioned_constants_id);
     if let Some(max_steps) = max_steps {
         constants.invoke_tx_max_n_steps = max_steps as u32;
     }
@@ -753,7 +756,13 @@ fn build_block_context(
 }
 
 #[allow(static_mut_refs)]
-fn get_versioned_constants(version: *const c_char) -> VersionedConstants {
+fn get_versioned_constants(version: *const c_char, override_id: c_ulonglong) -> VersionedConstants {
+    if override_id != 0 {
+        let overrides = VERSIONED_CONSTANTS_OVERRIDES.read().unwrap();
+        if let Some(constants) = overrides.get((override_id - 1) as usize) {
+            return constants.clone();
+        }
+    }
     if let Some(constants) = unsafe { &CUSTOM_VERSIONED_CONSTANTS } {
         return constants.clone();
     }
@@ -795,6 +804,42 @@ pub extern "C" fn setVersionedConstants(json_bytes: *const c_char) -> *const c_c
     }
 }
 
+// Versioned constants used instead of the default ones by the VMs given their id, which is the index in the
+// list plus one so that zero means no override. Entries are never removed, since a VM may use its id for as long
+// as the process runs, so constants should only be registered when setting up long lived VMs.
+static VERSIONED_CONSTANTS_OVERRIDES: Lazy<RwLock<Vec<VersionedConstants>>> =
+    Lazy::new(|| RwLock::new(Vec::new()));
+
+#[no_mangle]
+#[allow(clippy::not_unsafe_ptr_arg_deref)]
+pub extern "C" fn registerVersionedConstants(
+    json_bytes: *const c_char,
+    id: *mut c_ulonglong,
+) -> *const c_char {
+    let json_str = unsafe {
+        match CStr::from_ptr(json_bytes).to_str() {
+            Ok(s) => s,
+            Err(_) => {
+                return CString::new("Failed to convert JSON bytes to string")
+                    .unwrap()
+                    .into_raw()
+            }
+        }
+    };
+
+    match serde_json::from_str::<VersionedConstants>(json_str) {
+        Ok(parsed) => {
+            let mut overrides = VERSIONED_CONSTANTS_OVERRIDES.write().unwrap();
+            overrides.push(parsed);
+            unsafe { *id = overrides.len() as c_ulonglong };
+            CString::new("").unwrap().into_raw() // No error, return an empty string
+        }
+        Err(e) => CString::new(format!("Failed to parse JSON: {}", e))
+            .unwrap()
+            .into_raw(),
+    }
+}
+
 #[no_mangle]
 #[allow(clippy::not_unsafe_ptr_arg_deref)]
 pub extern "C" fn freeString(s: *mut c_char) {
@@ -73,21 +73,14 @@ func JunoStateGetClassHashAt(readerHandle C.uintptr_t, contractAddress, buffer u
 func JunoStateGetCompiledClass(readerHandle C.uintptr_t, classHash unsafe.Pointer) unsafe.Pointer {
 	context := unwrapContext(readerHandle)
 
-	classHashFelt := makeFeltFromPtr(classHash)
-	val, err := context.state.Class(classHashFelt)
+	compiledClass, err := stateClassInfo(context.state, makeFeltFromPtr(classHash))
 	if err != nil {
 		if !errors.Is(err, db.ErrKeyNotFound) {
-			context.log.Errorw("JunoStateGetCompiledClass failed to read class", "err", err)
+			context.log.Errorw("JunoStateGetCompiledClass failed to read compiled class", "err", err)
 		}
 		return nil
 	}
 
-	compiledClass, err := marshalClassInfo(val.Class)
-	if err != nil {
-		context.log.Errorw("JunoStateGetCompiledClass failed to marshal compiled class", "err", err)
-		return nil
-	}
-
 	return unsafe.Pointer(cstring(compiledClass))
 }
 
@@ -9,10 +9,10 @@ package vm
 import "C"
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"runtime"
 	"runtime/cgo"
@@ -47,9 +47,39 @@ type VM interface {
 	) (ExecutionResults, error)
 }
 
+// ContextBinder is implemented by VMs that can abandon queued work when a context is done.
+type ContextBinder interface {
+	WithContext(ctx context.Context) VM
+}
+
+// WithContext binds ctx to v if v supports it, otherwise v is returned as is.
+func WithContext(ctx context.Context, v VM) VM {
+	if binder, ok := v.(ContextBinder); ok {
+		return binder.WithContext(ctx)
+	}
+	return v
+}
+
+// Batcher is implemented by VMs that can run several executions as a single unit of work, e.g. taking
+// a single execution slot for all of them.
+type Batcher interface {
+	Batch(fn func(VM) error) error
+}
+
+// Batch runs fn with a VM that the whole batch can be executed on. VMs that are not Batchers are
+// passed to fn as is.
+func Batch(v VM, fn func(VM) error) error {
+	if batcher, ok := v.(Batcher); ok {
+		return batcher.Batch(fn)
+	}
+	return fn(v)
+}
+
 type vm struct {
 	log             utils.SimpleLogger
 	concurrencyMode bool
+	// versionedConstantsID identifies the versioned constants registered for this VM only, zero if none
+	versionedConstantsID uint64
 }
 
 func New(concurrencyMode bool, log utils.SimpleLogger) VM {
@@ -254,6 +284,7 @@ func (v *vm) Call(callInfo *CallInfo, blockInfo *BlockInfo, state core.StateRead
 
 	cCallInfo, callInfoPinner := makeCCallInfo(callInfo)
 	cBlockInfo := makeCBlockInfo(blockInfo)
+	cBlockInfo.versioned_constants_id = C.ulonglong(v.versionedConstantsID)
 	chainID := C.CString(network.L2ChainID)
 	cSierraVersion := C.CString(sierraVersion)
 	C.cairoVMCall(
@@ -316,6 +347,7 @@ func (v *vm) Execute(txns []core.Transaction, declaredClasses []core.Class, paid
 	classesJSONCStr := cstring(classesJSON)
 
 	cBlockInfo := makeCBlockInfo(blockInfo)
+	cBlockInfo.versioned_constants_id = C.ulonglong(v.versionedConstantsID)
 	chainID := C.CString(network.L2ChainID)
 	C.cairoVMExecute(txnsJSONCstr,
 		classesJSONCStr,
@@ -400,29 +432,45 @@ func marshalTxnsAndDeclaredClasses(txns []core.Transaction, declaredClasses []co
 }
 
 func SetVersionedConstants(filename string) error {
-	fd, err := os.Open(filename)
+	buff, err := os.ReadFile(filename)
 	if err != nil {
 		return err
 	}
-	defer fd.Close()
 
-	buff, err := io.ReadAll(fd)
+	jsonStr := C.CString(string(buff))
+	err = cError(C.setVersionedConstants(jsonStr))
+	C.free(unsafe.Pointer(jsonStr))
+
+	return err
+}
+
+// registerVersionedConstants loads the versioned constants in filename for the VMs using the returned id only.
+// The constants are kept until the process exits, every call registers them again under a new id.
+func registerVersionedConstants(filename string) (uint64, error) {
+	buff, err := os.ReadFile(filename)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
+	var id C.ulonglong
 	jsonStr := C.CString(string(buff))
-	if errCStr := C.setVersionedConstants(jsonStr); errCStr != nil {
-		var errStr string = C.GoString(errCStr)
-		// empty string is not an error
-		if errStr != "" {
-			err = errors.New(errStr)
-		}
-		// here we rely on free call on Rust side, because on Go side we can have different allocator
-		C.freeString((*C.char)(unsafe.Pointer(errCStr)))
-	}
+	err = cError(C.registerVersionedConstants(jsonStr, &id))
 	C.free(unsafe.Pointer(jsonStr))
 
+	return uint64(id), err
+}
+
+// cError converts an error string returned by the Rust side, where an empty string is not an error, and frees it.
+func cError(errCStr *C.char) error {
+	if errCStr == nil {
+		return nil
+	}
+	var err error
+	if errStr := C.GoString(errCStr); errStr != "" {
+		err = errors.New(errStr)
+	}
+	// here we rely on free call on Rust side, because on Go side we can have different allocator
+	C.freeString(errCStr)
 	return err
 }
 
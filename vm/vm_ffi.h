@@ -29,6 +29,7 @@ typedef struct BlockInfo {
 	unsigned char use_blob_data;
 	unsigned char l2_gas_price_wei[FELT_SIZE];
 	unsigned char l2_gas_price_fri[FELT_SIZE];
+	unsigned long long versioned_constants_id;
 } BlockInfo;
 
 extern void cairoVMCall(CallInfo* call_info_ptr, BlockInfo* block_info_ptr, uintptr_t readerHandle, char* chain_id,
@@ -40,6 +41,7 @@ extern void cairoVMExecute(char* txns_json, char* classes_json, char* paid_fees_
 					unsigned char concurrency_mode, unsigned char err_stack);
 
 extern char* setVersionedConstants(char* json);
+extern char* registerVersionedConstants(char* json, unsigned long long* id);
 extern void freeString(char* str);
 
 #endif // VM_FFI_H